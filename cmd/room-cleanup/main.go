@@ -1,8 +1,9 @@
-// room-cleanup は一定期間活動がない部屋を自動解散する Cloud Run Job 用コマンド。
+// room-cleanup は一定期間活動がない部屋を自動解散し、開始予定が近い部屋のメンバーにお知らせを送る Cloud Run Job 用コマンド。
 // Cloud Scheduler から定期的に実行される想定（詳細は docs/deploy.md を参照）。
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -14,11 +15,16 @@ import (
 
 	"mhp-rooms/internal/config"
 	"mhp-rooms/internal/infrastructure/persistence"
+	"mhp-rooms/internal/infrastructure/storage"
 	"mhp-rooms/internal/repository"
 	"mhp-rooms/internal/services"
 )
 
-const defaultInactiveHours = 48
+const (
+	defaultInactiveHours        = 48
+	defaultStartReminderMinutes = 60  // Job は 1 時間ごとに実行されるため、次の実行までに開始する部屋を対象にする
+	attachmentPurgeLimit        = 500 // 1回の実行で後始末する添付画像の上限
+)
 
 func main() {
 	startTime := time.Now()
@@ -32,9 +38,13 @@ func main() {
 	if err != nil {
 		log.Fatalf("環境変数 ROOM_INACTIVE_HOURS が不正です: %v", err)
 	}
+	reminderLead, err := parseStartReminderMinutes(os.Getenv("ROOM_START_REMINDER_MINUTES"))
+	if err != nil {
+		log.Fatalf("環境変数 ROOM_START_REMINDER_MINUTES が不正です: %v", err)
+	}
 	dryRun := parseBool(os.Getenv("DRY_RUN"))
 
-	log.Printf("部屋の自動削除を開始: idle=%s, dry_run=%t", idleDuration, dryRun)
+	log.Printf("部屋の自動削除を開始: idle=%s, reminder_lead=%s, dry_run=%t", idleDuration, reminderLead, dryRun)
 
 	cfg := &config.Config{
 		Database: config.DatabaseConfig{
@@ -50,7 +60,21 @@ func main() {
 	}
 	defer dbAdapter.Close()
 
-	cleanup := services.NewRoomCleanupService(repository.NewRepository(dbAdapter))
+	repo := repository.NewRepository(dbAdapter)
+	cleanup := services.NewRoomCleanupService(repo)
+
+	// チャットの添付画像を削除するため、バケットが指定されていればアップローダーを用意する
+	if bucket := os.Getenv("GCS_BUCKET"); bucket != "" {
+		uploader, err := storage.NewGCSUploaderWithConfig(context.Background(), &config.GCSConfig{Bucket: bucket})
+		if err != nil {
+			log.Printf("GCSアップローダーの初期化に失敗しました（添付画像は削除しません）: %v", err)
+		} else {
+			defer uploader.Close()
+			cleanup.SetChatAttachments(services.NewChatAttachmentService(repo, uploader))
+		}
+	} else {
+		log.Println("GCS_BUCKET が未設定のため、添付画像は削除しません")
+	}
 
 	if dryRun {
 		rooms, err := cleanup.FindInactiveRooms(idleDuration)
@@ -61,15 +85,41 @@ func main() {
 			log.Printf("[dry-run] 削除対象: room_id=%s name=%q created_at=%s updated_at=%s", room.ID, room.Name, room.CreatedAt.Format(time.RFC3339), room.UpdatedAt.Format(time.RFC3339))
 		}
 		log.Printf("[dry-run] 削除対象 %d 件（実際の削除は行っていません） duration_ms=%d", len(rooms), time.Since(startTime).Milliseconds())
+
+		upcoming, err := cleanup.FindRoomsStartingSoon(reminderLead)
+		if err != nil {
+			log.Fatalf("開始予定の部屋の取得失敗: %v", err)
+		}
+		for _, room := range upcoming {
+			log.Printf("[dry-run] 開始前お知らせ対象: room_id=%s name=%q scheduled_start_at=%s", room.ID, room.Name, room.ScheduledStartAt.Format(time.RFC3339))
+		}
 		return
 	}
 
+	// 開始前のお知らせは自動削除の成否に関わらず送る
+	reminded, reminderErr := cleanup.SendStartReminders(reminderLead)
+	for _, room := range reminded {
+		log.Printf("開始前お知らせ: room_id=%s name=%q scheduled_start_at=%s", room.ID, room.Name, room.ScheduledStartAt.Format(time.RFC3339))
+	}
+	if reminderErr != nil {
+		log.Printf("一部の部屋の開始前お知らせに失敗しました: %v", reminderErr)
+	}
+
 	dismissed, err := cleanup.DismissInactiveRooms(idleDuration)
 	for _, room := range dismissed {
 		log.Printf("自動削除: room_id=%s name=%q host_user_id=%s", room.ID, room.Name, room.HostUserID)
 	}
 	log.Printf("部屋の自動削除完了: dismissed=%d duration_ms=%d", len(dismissed), time.Since(startTime).Milliseconds())
 
+	// 削除済みメッセージ・解散済みの部屋に残った添付画像の後始末（失敗しても Job は失敗扱いにしない）
+	if purged, purgeErr := cleanup.PurgeOrphanedAttachments(attachmentPurgeLimit); purgeErr != nil {
+		if !errors.Is(purgeErr, services.ErrChatImageUnavailable) {
+			log.Printf("添付画像の後始末に失敗しました: %v", purgeErr)
+		}
+	} else {
+		log.Printf("添付画像の後始末完了: purged=%d", purged)
+	}
+
 	if err != nil {
 		log.Fatalf("一部の部屋の削除に失敗しました: %v", err)
 	}
@@ -92,6 +142,23 @@ func parseInactiveHours(value string) (time.Duration, error) {
 	return time.Duration(hours) * time.Hour, nil
 }
 
+// parseStartReminderMinutes ROOM_START_REMINDER_MINUTES（分）を Duration に変換する。未指定は defaultStartReminderMinutes
+func parseStartReminderMinutes(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultStartReminderMinutes * time.Minute, nil
+	}
+
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse %q as integer: %w", value, err)
+	}
+	if minutes <= 0 {
+		return 0, errors.New("must be a positive integer")
+	}
+
+	return time.Duration(minutes) * time.Minute, nil
+}
+
 // parseBool "true" / "1" を真として扱う
 func parseBool(value string) bool {
 	return value == "true" || value == "1"
@@ -32,6 +32,32 @@ func TestParseInactiveHours(t *testing.T) {
 	}
 }
 
+func TestParseStartReminderMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "未指定は既定値60分", value: "", want: 60 * time.Minute},
+		{name: "正の整数", value: "15", want: 15 * time.Minute},
+		{name: "0は不正", value: "0", wantErr: true},
+		{name: "数値以外は不正", value: "1h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStartReminderMinutes(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStartReminderMinutes(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseStartReminderMinutes(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseBool(t *testing.T) {
 	for value, want := range map[string]bool{"true": true, "1": true, "false": false, "": false, "yes": false} {
 		if got := parseBool(value); got != want {
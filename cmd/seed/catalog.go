@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// catalogSeed ゲームバージョンごとの図鑑の初期データ
+type catalogSeed struct {
+	Monsters []string // 表示順
+	Ranks    []string // クエストのランク帯。モンスターごとに「{モンスター}の狩猟（{ランク}）」を作る
+}
+
+// catalogSeeds ゲームバージョンのコードごとの図鑑。既存データは名前で照合して重複作成しない
+var catalogSeeds = map[string]catalogSeed{
+	"MHP": {
+		Monsters: []string{
+			"ドスランポス", "ドスゲネポス", "ドスイーオス", "イャンクック", "ゲリョス", "フルフル",
+			"ガノトトス", "ダイミョウザザミ", "バサルモス", "グラビモス", "イャンガルルガ", "リオレイア",
+			"リオレウス", "ディアブロス", "モノブロス", "ラオシャンロン", "キリン", "クシャルダオラ",
+			"テオ・テスカトル", "ナナ・テスカトリ",
+		},
+		Ranks: []string{"下位", "上位"},
+	},
+	"MHP2": {
+		Monsters: []string{
+			"ドスランポス", "ドスゲネポス", "ドスギアノス", "イャンクック", "ゲリョス", "ババコンガ",
+			"ドドブランゴ", "フルフル", "ガノトトス", "ダイミョウザザミ", "ショウグンギザミ", "バサルモス",
+			"グラビモス", "イャンガルルガ", "リオレイア", "リオレウス", "ティガレックス", "ディアブロス",
+			"モノブロス", "ラオシャンロン", "キリン", "クシャルダオラ", "テオ・テスカトル", "ナナ・テスカトリ",
+			"オオナズチ",
+		},
+		Ranks: []string{"下位", "上位"},
+	},
+	"MHP2G": {
+		Monsters: []string{
+			"ドスランポス", "ドスゲネポス", "ドスギアノス", "イャンクック", "ヒプノック", "ゲリョス",
+			"ババコンガ", "ドドブランゴ", "フルフル", "ガノトトス", "ヴォルガノス", "ダイミョウザザミ",
+			"ショウグンギザミ", "バサルモス", "グラビモス", "イャンガルルガ", "リオレイア", "リオレウス",
+			"ティガレックス", "ナルガクルガ", "ディアブロス", "モノブロス", "ラージャン", "ラオシャンロン",
+			"キリン", "クシャルダオラ", "テオ・テスカトル", "ナナ・テスカトリ", "オオナズチ", "ヤマツカミ",
+			"ウカムルバス", "アカムトルム", "ミラボレアス",
+		},
+		Ranks: []string{"下位", "上位", "G級"},
+	},
+	"MHP3": {
+		Monsters: []string{
+			"ドスジャギィ", "ドスバギィ", "ドスフロギィ", "クルペッコ", "ロアルドロス", "アオアシラ",
+			"ウルクスス", "ラングロトラ", "ボルボロス", "ハプルボッカ", "ベリオロス", "ナルガクルガ",
+			"リオレイア", "リオレウス", "ラギアクルス", "ジンオウガ", "アグナコトル", "ウラガンキン",
+			"ディアブロス", "ドボルベルク", "イビルジョー", "ジエン・モーラン", "アマツマガツチ",
+		},
+		Ranks: []string{"下位", "上位"},
+	},
+	"MHXX": {
+		Monsters: []string{
+			"ドスマッカォ", "ホロロホルル", "ケチャワチャ", "イャンクック", "ゲリョス", "ババコンガ",
+			"フルフル", "ダイミョウザザミ", "ショウグンギザミ", "テツカブラ", "ザボアザギル", "アルセルタス",
+			"ゲネル・セルタス", "リオレイア", "リオレウス", "ティガレックス", "ナルガクルガ", "ジンオウガ",
+			"ブラキディオス", "セルレギオス", "ライゼクス", "ガムート", "ディノバルド", "タマミツネ",
+			"ゴア・マガラ", "シャガルマガラ", "ディアブロス", "ラージャン", "イビルジョー", "アトラル・カ",
+			"オストガロア", "バルファルク", "アルバトリオン", "ミラボレアス",
+		},
+		Ranks: []string{"下位", "上位", "G級"},
+	},
+}
+
+// seedCatalog モンスター・クエスト図鑑を投入する。何度実行しても既存の項目は作り直さない
+func seedCatalog(db *gorm.DB, gameVersions []models.GameVersion) error {
+	for _, gameVersion := range gameVersions {
+		seed, ok := catalogSeeds[gameVersion.Code]
+		if !ok {
+			continue
+		}
+
+		createdMonsters, createdQuests := 0, 0
+		for i, name := range seed.Monsters {
+			monster := models.Monster{GameVersionID: gameVersion.ID, Name: name}
+			result := db.Where("game_version_id = ? AND name = ?", gameVersion.ID, name).
+				Attrs(models.Monster{DisplayOrder: i + 1, IsActive: true}).
+				FirstOrCreate(&monster)
+			if result.Error != nil {
+				return fmt.Errorf("モンスターの作成に失敗しました (%s %s): %w", gameVersion.Code, name, result.Error)
+			}
+			createdMonsters += int(result.RowsAffected)
+
+			for j, rank := range seed.Ranks {
+				questName := fmt.Sprintf("%sの狩猟（%s）", name, rank)
+				quest := models.Quest{GameVersionID: gameVersion.ID, Name: questName}
+				result := db.Where("game_version_id = ? AND name = ?", gameVersion.ID, questName).
+					Attrs(models.Quest{MonsterID: &monster.ID, Rank: rank, DisplayOrder: (i+1)*10 + j, IsActive: true}).
+					FirstOrCreate(&quest)
+				if result.Error != nil {
+					return fmt.Errorf("クエストの作成に失敗しました (%s %s): %w", gameVersion.Code, questName, result.Error)
+				}
+				createdQuests += int(result.RowsAffected)
+			}
+		}
+		log.Printf("図鑑投入: %s - モンスター %d件 / クエスト %d件を追加", gameVersion.Code, createdMonsters, createdQuests)
+	}
+	return nil
+}
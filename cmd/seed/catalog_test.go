@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+func TestSeedCatalogIsIdempotent(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.GameVersion{}, &models.Monster{}, &models.Quest{}); err != nil {
+		t.Fatal(err)
+	}
+	gameVersions := []models.GameVersion{
+		{BaseModel: models.BaseModel{ID: uuid.New()}, Code: "MHP2G", Name: "2G", DisplayOrder: 3, PlatformID: uuid.New(), IsActive: true},
+		{BaseModel: models.BaseModel{ID: uuid.New()}, Code: "UNKNOWN", Name: "図鑑なし", DisplayOrder: 9, PlatformID: uuid.New(), IsActive: true},
+	}
+	for i := range gameVersions {
+		if err := db.Create(&gameVersions[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seed := catalogSeeds["MHP2G"]
+	wantMonsters := int64(len(seed.Monsters))
+	wantQuests := wantMonsters * int64(len(seed.Ranks))
+
+	for run := 1; run <= 2; run++ {
+		if err := seedCatalog(db, gameVersions); err != nil {
+			t.Fatalf("%d回目: %v", run, err)
+		}
+		var monsters, quests int64
+		db.Model(&models.Monster{}).Count(&monsters)
+		db.Model(&models.Quest{}).Count(&quests)
+		if monsters != wantMonsters || quests != wantQuests {
+			t.Fatalf("%d回目: モンスター %d件 / クエスト %d件, want %d / %d", run, monsters, quests, wantMonsters, wantQuests)
+		}
+	}
+
+	// クエストはメインターゲットのモンスターに紐付く
+	var quest models.Quest
+	if err := db.Preload("Monster").Where("name = ?", "ティガレックスの狩猟（G級）").First(&quest).Error; err != nil {
+		t.Fatal(err)
+	}
+	if quest.Monster == nil || quest.Monster.Name != "ティガレックス" || quest.Rank != "G級" {
+		t.Errorf("quest = %+v, want ティガレックスの G級クエスト", quest)
+	}
+}
+
+func TestCatalogSeedsHaveNoDuplicates(t *testing.T) {
+	for code, seed := range catalogSeeds {
+		seen := map[string]bool{}
+		for _, name := range seed.Monsters {
+			if seen[name] {
+				t.Errorf("%s: %s が重複しています", code, name)
+			}
+			seen[name] = true
+		}
+		if len(seed.Ranks) == 0 {
+			t.Errorf("%s: ランク帯が空です", code)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"time"
 
@@ -13,7 +14,14 @@ import (
 )
 
 func main() {
-	log.Println("テストデータの作成を開始します...")
+	catalogOnly := flag.Bool("catalog-only", false, "モンスター・クエスト図鑑のみを投入する（本番環境向け。テストユーザー・部屋は作らない）")
+	flag.Parse()
+
+	if *catalogOnly {
+		log.Println("図鑑データの投入を開始します...")
+	} else {
+		log.Println("テストデータの作成を開始します...")
+	}
 
 	if err := godotenv.Load(); err != nil {
 		log.Println(".envファイルが見つかりません。環境変数から設定を読み込みます。")
@@ -32,6 +40,19 @@ func main() {
 	}
 	defer db.Close()
 
+	if *catalogOnly {
+		var gameVersions []models.GameVersion
+		db.GetConn().Find(&gameVersions)
+		if len(gameVersions) == 0 {
+			log.Fatal("ゲームバージョンが見つかりません。先にマイグレーションを実行してください。")
+		}
+		if err := seedCatalog(db.GetConn(), gameVersions); err != nil {
+			log.Fatalf("図鑑データの投入に失敗しました: %v", err)
+		}
+		log.Println("図鑑データの投入が完了しました")
+		return
+	}
+
 	users := []models.User{
 		{
 			BaseModel: models.BaseModel{
@@ -180,6 +201,10 @@ func main() {
 		log.Fatal("ゲームバージョンが見つかりません。先にマイグレーションを実行してください。")
 	}
 
+	if err := seedCatalog(db.GetConn(), gameVersions); err != nil {
+		log.Fatalf("図鑑データの投入に失敗しました: %v", err)
+	}
+
 	rooms := []models.Room{
 		{
 			BaseModel: models.BaseModel{
@@ -272,6 +297,14 @@ func main() {
 			rooms[i] = room // パスワードハッシュを反映
 		}
 
+		// ターゲットモンスターが図鑑にあれば紐付ける
+		if room.TargetMonster != nil {
+			var monster models.Monster
+			if err := db.GetConn().Where("game_version_id = ? AND name = ?", room.GameVersionID, *room.TargetMonster).First(&monster).Error; err == nil {
+				room.MonsterID = &monster.ID
+			}
+		}
+
 		if err := db.GetConn().Create(&room).Error; err != nil {
 			log.Printf("ルーム作成エラー: %v", err)
 		} else {
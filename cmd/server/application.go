@@ -14,6 +14,7 @@ import (
 	"mhp-rooms/internal/infrastructure/storage"
 	"mhp-rooms/internal/middleware"
 	"mhp-rooms/internal/repository"
+	"mhp-rooms/internal/services"
 )
 
 type Application struct {
@@ -48,6 +49,7 @@ type Application struct {
 	generalLimiter      *middleware.RateLimiter
 	authLimiter         *middleware.RateLimiter
 	contactLimiter      *middleware.RateLimiter
+	roomCodeLimiter     *middleware.RateLimiter
 	sseHub              *sse.Hub
 }
 
@@ -120,7 +122,7 @@ func (app *Application) initHandlers() error {
 	app.roomMessageHandler = handlers.NewRoomMessageHandler(app.repo, app.sseHub)
 	app.sseTokenHandler = handlers.NewSSETokenHandler(app.repo)
 	app.pageHandler = handlers.NewPageHandler(app.repo, articleGenerator)
-	app.reactionHandler = handlers.NewReactionHandler(app.repo)
+	app.reactionHandler = handlers.NewReactionHandler(app.repo, app.sseHub)
 	app.gameVersionHandler = handlers.NewGameVersionHandler(app.repo)
 	app.profileHandler = handlers.NewProfileHandler(app.repo, app.authMiddleware)
 	app.userHandler = handlers.NewUserHandler(app.repo)
@@ -142,6 +144,11 @@ func (app *Application) initHandlers() error {
 
 	app.reportHandler = handlers.NewReportHandler(app.repo.Report, app.repo.User, gcsUploader)
 
+	// チャットの画像添付はレポートと同じアップローダーを使う
+	chatAttachments := services.NewChatAttachmentService(app.repo, gcsUploader)
+	app.roomHandler.SetChatAttachments(chatAttachments)
+	app.roomMessageHandler.SetChatAttachments(chatAttachments)
+
 	// セキュリティ設定の初期化
 	app.securityConfig = middleware.NewSecurityConfig()
 
@@ -150,6 +157,7 @@ func (app *Application) initHandlers() error {
 	app.generalLimiter = middleware.NewRateLimiter(rateLimitConfig.General)
 	app.authLimiter = middleware.NewRateLimiter(rateLimitConfig.Auth)
 	app.contactLimiter = middleware.NewRateLimiter(rateLimitConfig.Contact)
+	app.roomCodeLimiter = middleware.NewRateLimiter(rateLimitConfig.RoomCode)
 
 	app.authHandler.SetAuthMiddleware(authMiddleware)
 
@@ -100,6 +100,7 @@ func (app *Application) setupPageRoutes(r chi.Router) {
 	r.Get("/profile/view", app.withAuth(profileHandler.ViewProfile))
 	r.Get("/users", app.withOptionalAuth(app.userHandler.List))
 	r.Get("/users/{uuid}", app.withOptionalAuth(app.userHandler.Show))
+	r.Get("/leaderboard", app.withOptionalAuth(app.gameVersionHandler.HuntLeaderboard))
 
 	// 更新情報・ロードマップ（完全静的のため認証ミドルウェアを適用しない）
 	r.Get("/info", infoHandler.List)
@@ -129,14 +130,20 @@ func (app *Application) setupRoomRoutes(r chi.Router) {
 			rr.Get("/", app.withOptionalAuth(rh.Rooms))
 			rr.Get("/recent-activity", app.withOptionalAuth(rh.RecentActivity))
 			rr.Get("/{id}", app.withOptionalAuth(rdh.RoomDetail))
+			// 解散した部屋のセッションのまとめ（参加者・管理者のみ）
+			rr.Get("/{id}/summary", app.withOptionalAuth(rdh.SessionSummary))
 			// 部屋参加ページ（スケルトン、OGPクローラー対応のため認証オプション）
 			rr.Get("/{id}/join", app.withOptionalAuth(rjh.RoomJoinPage))
+			// ルームコード入力ページ
+			rr.Get("/code", app.withOptionalAuth(rjh.RoomCodePage))
 		} else {
 			rr.Get("/", rh.Rooms)
 			rr.Get("/recent-activity", rh.RecentActivity)
 			rr.Get("/{id}", rdh.RoomDetail)
+			rr.Get("/{id}/summary", rdh.SessionSummary)
 			// 部屋参加ページ（開発環境では認証なし）
 			rr.Get("/{id}/join", rjh.RoomJoinPage)
+			rr.Get("/code", rjh.RoomCodePage)
 		}
 
 		// 部屋操作・メッセージ機能（本番環境では認証必須、開発環境では認証なし）
@@ -150,11 +157,46 @@ func (app *Application) setupRoomRoutes(r chi.Router) {
 				protected.Post("/{id}/join", rh.JoinRoom)
 				protected.Post("/{id}/leave", rh.LeaveRoom)
 				protected.Post("/{id}/kick", rh.KickMember)
+				protected.Post("/{id}/transfer-host", rh.TransferHost)
 				protected.Put("/{id}/toggle-closed", rh.ToggleRoomClosed)
 
+				// 招待リンク（ホストのみ）
+				protected.Get("/{id}/invites", rh.GetRoomInvites)
+				protected.Post("/{id}/invites", rh.CreateRoomInvite)
+				protected.Delete("/{id}/invites/{inviteID}", rh.RevokeRoomInvite)
+
+				// キャンセル待ち
+				protected.Get("/{id}/waitlist", rh.GetWaitlistStatus)
+				protected.Post("/{id}/waitlist", rh.JoinWaitlist)
+				protected.Delete("/{id}/waitlist", rh.LeaveWaitlist)
+
+				// 参加申請（承認制の部屋）
+				protected.Get("/{id}/join-requests", rh.GetJoinRequests)
+				protected.Post("/{id}/join-requests/{requestID}/approve", rh.ApproveJoinRequest)
+				protected.Post("/{id}/join-requests/{requestID}/reject", rh.RejectJoinRequest)
+				protected.Delete("/{id}/join-request", rh.CancelJoinRequest)
+
+				// 準備確認
+				protected.Get("/{id}/ready-check", rh.GetReadyCheck)
+				protected.Post("/{id}/ready-check", rh.StartReadyCheck)
+				protected.Put("/{id}/ready-check", rh.SetReady)
+				protected.Delete("/{id}/ready-check", rh.CancelReadyCheck)
+
+				// 装備（武器種・HR）の申告
+				protected.Put("/{id}/loadout", rh.UpdateLoadout)
+
+				// 狩猟記録（記録はホストのみ）
+				protected.Get("/{id}/hunts", rh.GetHuntRecords)
+				protected.Post("/{id}/hunts", rh.CreateHuntRecord)
+
 				// メッセージ関連
 				protected.Post("/{id}/messages", rmh.SendMessage)
 				protected.Get("/{id}/messages", rmh.GetMessages)
+				protected.Put("/{id}/messages/{messageID}", rmh.EditMessage)
+				protected.Delete("/{id}/messages/{messageID}", rmh.DeleteMessage)
+				// チャット記録の書き出し（参加者・管理者のみ。解散後は保存期間内）
+				protected.Get("/{id}/transcript", rmh.ExportTranscript)
+				protected.Post("/{id}/typing", rmh.Typing)
 				protected.Post("/{id}/sse-token", app.sseTokenHandler.GenerateSSEToken)
 			})
 
@@ -167,11 +209,45 @@ func (app *Application) setupRoomRoutes(r chi.Router) {
 			rr.Post("/{id}/join", rh.JoinRoom)
 			rr.Post("/{id}/leave", rh.LeaveRoom)
 			rr.Post("/{id}/kick", rh.KickMember)
+			rr.Post("/{id}/transfer-host", rh.TransferHost)
 			rr.Put("/{id}/toggle-closed", rh.ToggleRoomClosed)
 
+			// 招待リンク（ホストのみ）
+			rr.Get("/{id}/invites", rh.GetRoomInvites)
+			rr.Post("/{id}/invites", rh.CreateRoomInvite)
+			rr.Delete("/{id}/invites/{inviteID}", rh.RevokeRoomInvite)
+
+			// キャンセル待ち
+			rr.Get("/{id}/waitlist", rh.GetWaitlistStatus)
+			rr.Post("/{id}/waitlist", rh.JoinWaitlist)
+			rr.Delete("/{id}/waitlist", rh.LeaveWaitlist)
+
+			// 参加申請（承認制の部屋）
+			rr.Get("/{id}/join-requests", rh.GetJoinRequests)
+			rr.Post("/{id}/join-requests/{requestID}/approve", rh.ApproveJoinRequest)
+			rr.Post("/{id}/join-requests/{requestID}/reject", rh.RejectJoinRequest)
+			rr.Delete("/{id}/join-request", rh.CancelJoinRequest)
+
+			// 準備確認
+			rr.Get("/{id}/ready-check", rh.GetReadyCheck)
+			rr.Post("/{id}/ready-check", rh.StartReadyCheck)
+			rr.Put("/{id}/ready-check", rh.SetReady)
+			rr.Delete("/{id}/ready-check", rh.CancelReadyCheck)
+
+			// 装備（武器種・HR）の申告
+			rr.Put("/{id}/loadout", rh.UpdateLoadout)
+
+			// 狩猟記録（記録はホストのみ）
+			rr.Get("/{id}/hunts", rh.GetHuntRecords)
+			rr.Post("/{id}/hunts", rh.CreateHuntRecord)
+
 			// メッセージ関連
 			rr.Post("/{id}/messages", rmh.SendMessage)
 			rr.Get("/{id}/messages", rmh.GetMessages)
+			rr.Put("/{id}/messages/{messageID}", rmh.EditMessage)
+			rr.Delete("/{id}/messages/{messageID}", rmh.DeleteMessage)
+			rr.Get("/{id}/transcript", rmh.ExportTranscript)
+			rr.Post("/{id}/typing", rmh.Typing)
 			rr.Post("/{id}/sse-token", app.sseTokenHandler.GenerateSSEToken)
 			rr.Get("/{id}/messages/stream", rmh.StreamMessages)
 		}
@@ -190,6 +266,10 @@ func (app *Application) setupAdminRoutes(r chi.Router) {
 		ar.Get("/", app.adminHandler.Dashboard)
 		ar.Get("/rooms", app.adminHandler.Rooms)
 		ar.Get("/rooms/{id}", app.adminHandler.RoomDetail)
+		ar.Get("/ng-words", app.adminHandler.NGWords)
+		ar.Post("/ng-words", app.adminHandler.CreateNGWord)
+		ar.Post("/ng-words/{id}/toggle", app.adminHandler.ToggleNGWord)
+		ar.Post("/ng-words/{id}/delete", app.adminHandler.DeleteNGWord)
 	})
 }
 
@@ -220,12 +300,14 @@ func (app *Application) setupAPIRoutes(r chi.Router) {
 		// 認証不要なAPIエンドポイント
 		ar.Get("/health", app.healthCheck)
 		ar.Get("/game-versions/active", app.gameVersionHandler.GetActiveGameVersionsAPI)
+		ar.Get("/game-versions/{id}/catalog", app.gameVersionHandler.GetCatalogAPI)
 
 		// 他のユーザーのプロフィール関連API（認証オプション）
 		ar.Get("/users/{uuid}", app.withOptionalAuth(app.userHandler.GetUserProfile))
 		ar.Get("/users/{uuid}/profile-card", app.withOptionalAuth(app.userHandler.GetProfileCard))
 		ar.Get("/users/{uuid}/rooms", app.withOptionalAuth(app.userHandler.Rooms))
 		ar.Get("/users/{uuid}/activity", app.withOptionalAuth(app.profileHandler.Activity))
+		ar.Get("/users/{uuid}/hunts", app.withOptionalAuth(app.profileHandler.Hunts))
 		ar.Get("/users/{uuid}/followers", app.withOptionalAuth(app.profileHandler.Followers))
 		ar.Get("/users/{uuid}/following", app.withOptionalAuth(app.profileHandler.Following))
 
@@ -245,6 +327,8 @@ func (app *Application) setupAPIRoutes(r chi.Router) {
 		ar.Get("/user/current-room", app.withAuth(app.roomHandler.GetCurrentRoom))
 		ar.Get("/user/current/room-status", app.withAuth(app.roomHandler.GetUserRoomStatus))
 		ar.Post("/leave-current-room", app.withAuth(app.roomHandler.LeaveCurrentRoom))
+		// ルームコードの照会（総当たり対策のため厳しいレート制限）
+		ar.Post("/rooms/code", app.withAuth(middleware.RoomCodeRateLimitMiddleware(app.roomCodeLimiter)(http.HandlerFunc(app.roomHandler.ResolveRoomCode)).ServeHTTP))
 
 		// プロフィール関連API（認証必須）
 		ar.Get("/profile/edit-form", app.withAuth(app.profileHandler.EditForm))
@@ -253,8 +337,12 @@ func (app *Application) setupAPIRoutes(r chi.Router) {
 		ar.Post("/profile/upload-avatar", app.withAuth(app.profileHandler.UploadAvatar))
 		ar.Get("/profile/activity", app.withAuth(app.profileHandler.Activity))
 		ar.Get("/profile/rooms", app.withAuth(app.profileHandler.Rooms))
+		ar.Get("/profile/hunts", app.withAuth(app.profileHandler.Hunts))
 		ar.Get("/profile/followers", app.withAuth(app.profileHandler.Followers))
 		ar.Get("/profile/following", app.withAuth(app.profileHandler.Following))
+		ar.Get("/profile/bans", app.withAuth(app.profileHandler.Bans))
+		ar.Delete("/profile/bans/{userID}", app.withAuth(app.profileHandler.Unban))
+		ar.Post("/users/{userID}/ban", app.withAuth(app.profileHandler.BanUser))
 
 		// フォロー関連API（認証必須）
 		ar.Post("/users/{userID}/follow", app.withAuth(app.followHandler.FollowUser))
@@ -33,7 +33,16 @@ var adminActionLabels = map[string]string{
 	"join":            "参加",
 	"leave":           "退出",
 	"kick":            "キック",
+	"transfer_host":   "ホスト交代",
+	"create_invite":   "招待リンク作成",
+	"revoke_invite":   "招待リンク無効化",
+	"use_invite":      "招待リンクで参加",
+	"approve_join":    "参加申請を承認",
+	"reject_join":     "参加申請を見送り",
 	"update_settings": "設定変更",
+	"hunt_clear":      "クエストクリア",
+	"delete_message":  "メッセージ削除",
+	"auto_mute":       "自動ミュート",
 	"dismiss":         "解散",
 	"auto_dismiss":    "自動解散",
 	AdminActionView:   "管理者閲覧",
@@ -71,6 +80,17 @@ type adminMessageRow struct {
 	CreatedAt string
 	UserName  string
 	Message   string
+	IsDeleted bool
+	DeletedBy string
+	EditedAt  string
+	Edits     []adminMessageEditRow
+}
+
+// adminMessageEditRow メッセージの編集履歴1件分（編集前の本文）
+type adminMessageEditRow struct {
+	EditedAt        string
+	EditorName      string
+	PreviousMessage string
 }
 
 // adminMemberRow 部屋詳細のメンバー1行分
@@ -103,6 +123,7 @@ type adminRoomDetailData struct {
 	Members     []adminMemberRow
 	Messages    []adminMessageRow
 	OlderCursor string
+	Session     *SessionSummaryView // 解散済みの部屋のセッションのまとめ（ない場合は nil）
 }
 
 func NewAdminHandler(repo *repository.Repository) *AdminHandler {
@@ -245,11 +266,15 @@ func (h *AdminHandler) RoomDetail(w http.ResponseWriter, r *http.Request) {
 		StatusClass: statusClass,
 		CreatedAt:   formatAdminTime(room.CreatedAt),
 		Members:     buildAdminMemberRows(members),
-		Messages:    buildAdminMessageRows(messages),
+		Messages:    buildAdminMessageRows(messages, h.messageEdits(messages)),
 		OlderCursor: olderCursor,
 	}
 	if room.DismissedAt != nil {
 		data.DismissedAt = formatAdminTime(*room.DismissedAt)
+		if summary, err := h.repo.RoomSession.FindByRoomID(roomID); err == nil {
+			sessionView := newSessionSummaryView(summary)
+			data.Session = &sessionView
+		}
 	}
 
 	view.Template(w, "admin_room_detail.tmpl", view.Data{
@@ -319,15 +344,53 @@ func buildAdminMemberRows(members []models.RoomMember) []adminMemberRow {
 	return rows
 }
 
-func buildAdminMessageRows(messages []models.RoomMessage) []adminMessageRow {
+// messageEdits 表示中のメッセージの編集履歴をメッセージIDごとにまとめる
+func (h *AdminHandler) messageEdits(messages []models.RoomMessage) map[uuid.UUID][]models.RoomMessageEdit {
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, m := range messages {
+		if m.EditedAt != nil {
+			ids = append(ids, m.ID)
+		}
+	}
+	edits, err := h.repo.RoomMessage.GetMessageEdits(ids)
+	if err != nil {
+		log.Printf("管理画面: 編集履歴の取得に失敗しました: %v", err)
+		return nil
+	}
+	byMessage := make(map[uuid.UUID][]models.RoomMessageEdit, len(ids))
+	for _, e := range edits {
+		byMessage[e.MessageID] = append(byMessage[e.MessageID], e)
+	}
+	return byMessage
+}
+
+func buildAdminMessageRows(messages []models.RoomMessage, edits map[uuid.UUID][]models.RoomMessageEdit) []adminMessageRow {
 	rows := make([]adminMessageRow, 0, len(messages))
 	for i := range messages {
 		m := &messages[i]
-		rows = append(rows, adminMessageRow{
+		row := adminMessageRow{
 			CreatedAt: formatAdminTime(m.CreatedAt),
 			UserName:  adminUserName(&m.UserID, &m.User),
 			Message:   m.Message,
-		})
+			IsDeleted: m.IsDeleted,
+		}
+		if m.IsDeleted {
+			row.DeletedBy = "ホスト"
+			if m.DeletedByUserID == nil || *m.DeletedByUserID == m.UserID {
+				row.DeletedBy = "投稿者"
+			}
+		}
+		if m.EditedAt != nil {
+			row.EditedAt = formatAdminTime(*m.EditedAt)
+		}
+		for _, e := range edits[m.ID] {
+			row.Edits = append(row.Edits, adminMessageEditRow{
+				EditedAt:        formatAdminTime(e.CreatedAt),
+				EditorName:      adminUserName(&e.EditorUserID, &e.Editor),
+				PreviousMessage: e.PreviousMessage,
+			})
+		}
+		rows = append(rows, row)
 	}
 	return rows
 }
@@ -370,7 +433,7 @@ func adminLogDetail(details models.JSONB) string {
 	}
 
 	parts := make([]string, 0, 2)
-	for _, key := range []string{"user_name", "room_name", "reason", "admin_name"} {
+	for _, key := range []string{"user_name", "room_name", "monster_name", "reason", "admin_name"} {
 		if v, ok := data[key].(string); ok && v != "" {
 			parts = append(parts, v)
 		}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/services"
+	"mhp-rooms/internal/view"
+)
+
+const adminNGWordHitsPerPage = 50
+
+// ngWordMatchTypeLabels NGワードの照合方法の表示名
+var ngWordMatchTypeLabels = map[string]string{
+	models.NGWordMatchExact:     "単語一致",
+	models.NGWordMatchSubstring: "部分一致",
+	models.NGWordMatchRegex:     "正規表現",
+}
+
+// ngWordActionLabels NGワードに一致したときの扱いの表示名
+var ngWordActionLabels = map[string]string{
+	models.NGWordActionBlock: "拒否",
+	models.NGWordActionMask:  "伏せ字",
+}
+
+// ngWordTargetLabels NGワードを適用した入力欄の表示名
+var ngWordTargetLabels = map[string]string{
+	models.NGWordTargetChat:            "チャット",
+	models.NGWordTargetRoomName:        "部屋名",
+	models.NGWordTargetRoomDescription: "部屋の説明",
+	models.NGWordTargetDisplayName:     "表示名",
+	models.NGWordTargetBio:             "自己紹介",
+}
+
+// adminNGWordRow NGワード一覧の1行分
+type adminNGWordRow struct {
+	ID             uuid.UUID
+	Pattern        string
+	MatchTypeLabel string
+	ActionLabel    string
+	IsBlock        bool
+	IsActive       bool
+	Note           string
+	CreatedBy      string
+	CreatedAt      string
+}
+
+// adminNGWordHitRow NGワードの一致記録1行分
+type adminNGWordHitRow struct {
+	CreatedAt   string
+	UserName    string
+	TargetLabel string
+	Pattern     string
+	ActionLabel string
+	Content     string
+	RoomID      *uuid.UUID
+}
+
+// adminNGWordForm NGワード登録フォームの入力値（エラー時の再表示用）
+type adminNGWordForm struct {
+	Pattern   string
+	MatchType string
+	Action    string
+	Note      string
+}
+
+// adminNGWordsData NGワード管理画面の PageData
+type adminNGWordsData struct {
+	Words      []adminNGWordRow
+	Hits       []adminNGWordHitRow
+	Pagination Pagination
+	Form       adminNGWordForm
+	Error      string
+}
+
+// NGWords NGワードの一覧・登録フォームと、一致記録を表示する
+func (h *AdminHandler) NGWords(w http.ResponseWriter, r *http.Request) {
+	form := adminNGWordForm{MatchType: models.NGWordMatchSubstring, Action: models.NGWordActionMask}
+	h.renderNGWords(w, r, http.StatusOK, form, "")
+}
+
+// CreateNGWord NGワードを登録する
+func (h *AdminHandler) CreateNGWord(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	form := adminNGWordForm{
+		Pattern:   strings.TrimSpace(r.FormValue("pattern")),
+		MatchType: r.FormValue("match_type"),
+		Action:    r.FormValue("action"),
+		Note:      strings.TrimSpace(r.FormValue("note")),
+	}
+
+	if err := services.ValidateNGWordPattern(form.MatchType, form.Pattern); err != nil {
+		h.renderNGWords(w, r, http.StatusBadRequest, form, err.Error())
+		return
+	}
+	if !models.IsValidNGWordAction(form.Action) {
+		h.renderNGWords(w, r, http.StatusBadRequest, form, "無効な扱いです")
+		return
+	}
+
+	word := &models.NGWord{
+		Pattern:   form.Pattern,
+		MatchType: form.MatchType,
+		Action:    form.Action,
+		IsActive:  true,
+	}
+	if form.Note != "" {
+		word.Note = &form.Note
+	}
+	if admin, ok := middleware.GetDBUserFromContext(r.Context()); ok && admin != nil {
+		word.CreatedByUserID = &admin.ID
+	}
+	if err := h.repo.NGWord.CreateNGWord(word); err != nil {
+		log.Printf("管理画面: NGワードの登録に失敗しました: %v", err)
+		h.renderNGWords(w, r, http.StatusInternalServerError, form, "NGワードの登録に失敗しました")
+		return
+	}
+
+	http.Redirect(w, r, "/admin/ng-words", http.StatusSeeOther)
+}
+
+// ToggleNGWord NGワードの有効・無効を切り替える
+func (h *AdminHandler) ToggleNGWord(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := h.repo.NGWord.SetNGWordActive(id, r.FormValue("active") == "true"); err != nil {
+		http.Error(w, "NGワードの更新に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin/ng-words", http.StatusSeeOther)
+}
+
+// DeleteNGWord NGワードを削除する（一致記録は残す）
+func (h *AdminHandler) DeleteNGWord(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := h.repo.NGWord.DeleteNGWord(id); err != nil {
+		http.Error(w, "NGワードの削除に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin/ng-words", http.StatusSeeOther)
+}
+
+func (h *AdminHandler) renderNGWords(w http.ResponseWriter, r *http.Request, status int, form adminNGWordForm, formError string) {
+	page := parsePageParam(r)
+
+	words, err := h.repo.NGWord.ListNGWords()
+	if err != nil {
+		http.Error(w, "NGワードの取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	hits, err := h.repo.NGWord.ListRecentHits(adminNGWordHitsPerPage, (page-1)*adminNGWordHitsPerPage)
+	if err != nil {
+		http.Error(w, "一致記録の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	total, err := h.repo.NGWord.CountHits()
+	if err != nil {
+		http.Error(w, "一致記録の件数の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	data := adminNGWordsData{
+		Words:      buildAdminNGWordRows(words),
+		Hits:       buildAdminNGWordHitRows(hits),
+		Pagination: newPagination(total, page, adminNGWordHitsPerPage, "/admin/ng-words"),
+		Form:       form,
+		Error:      formError,
+	}
+
+	// 入力エラーは 400 でフォームごと再表示する
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	view.Template(w, "admin_ng_words.tmpl", view.Data{
+		Title:    "NGワード（管理）",
+		PageData: data,
+	})
+}
+
+func buildAdminNGWordRows(words []models.NGWord) []adminNGWordRow {
+	rows := make([]adminNGWordRow, 0, len(words))
+	for i := range words {
+		word := &words[i]
+		row := adminNGWordRow{
+			ID:             word.ID,
+			Pattern:        word.Pattern,
+			MatchTypeLabel: ngWordMatchTypeLabels[word.MatchType],
+			ActionLabel:    ngWordActionLabels[word.Action],
+			IsBlock:        word.Action == models.NGWordActionBlock,
+			IsActive:       word.IsActive,
+			CreatedBy:      adminUserName(word.CreatedByUserID, word.CreatedBy),
+			CreatedAt:      formatAdminTime(word.CreatedAt),
+		}
+		if word.Note != nil {
+			row.Note = *word.Note
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func buildAdminNGWordHitRows(hits []models.NGWordHit) []adminNGWordHitRow {
+	rows := make([]adminNGWordHitRow, 0, len(hits))
+	for i := range hits {
+		hit := &hits[i]
+		label, ok := ngWordTargetLabels[hit.Target]
+		if !ok {
+			label = hit.Target
+		}
+		rows = append(rows, adminNGWordHitRow{
+			CreatedAt:   formatAdminTime(hit.CreatedAt),
+			UserName:    adminUserName(&hit.UserID, &hit.User),
+			TargetLabel: label,
+			Pattern:     hit.Pattern,
+			ActionLabel: ngWordActionLabels[hit.Action],
+			Content:     hit.Content,
+			RoomID:      hit.RoomID,
+		})
+	}
+	return rows
+}
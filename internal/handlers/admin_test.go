@@ -195,10 +195,29 @@ func TestRenderAdminTemplates(t *testing.T) {
 				},
 				Messages: []adminMessageRow{
 					{CreatedAt: "2026-08-20 12:01", UserName: "参加者", Message: "よろしく！"},
+					{CreatedAt: "2026-08-20 12:02", UserName: "参加者", Message: "荒らし発言", IsDeleted: true, DeletedBy: "ホスト"},
+					{CreatedAt: "2026-08-20 12:03", UserName: "参加者", Message: "集会所2です", EditedAt: "2026-08-20 12:04", Edits: []adminMessageEditRow{
+						{EditedAt: "2026-08-20 12:04", EditorName: "参加者", PreviousMessage: "集会所1です"},
+					}},
 				},
 				OlderCursor: uuid.New().String(),
 			},
-			want: []string{"チャットログ", "よろしく！", "ホスト太郎", "さらに古いログ"},
+			want: []string{"チャットログ", "よろしく！", "ホスト太郎", "さらに古いログ", "ホストが削除", "編集履歴（1件）", "集会所1です"},
+		},
+		{
+			template: "admin_ng_words.tmpl",
+			data: adminNGWordsData{
+				Words: []adminNGWordRow{
+					{ID: uuid.New(), Pattern: "spam", MatchTypeLabel: "部分一致", ActionLabel: "伏せ字", IsActive: true, CreatedBy: "管理者", CreatedAt: "2026-08-20 12:00"},
+				},
+				Hits: []adminNGWordHitRow{
+					{CreatedAt: "2026-08-20 12:05", UserName: "参加者", TargetLabel: "チャット", Pattern: "spam", ActionLabel: "伏せ字", Content: "spam部屋です", RoomID: &roomID},
+				},
+				Pagination: newPagination(1, 1, adminNGWordHitsPerPage, "/admin/ng-words"),
+				Form:       adminNGWordForm{Pattern: "(spam", MatchType: "regex", Action: "block"},
+				Error:      "正規表現が正しくありません",
+			},
+			want: []string{"NGワード", "無効にする", "spam部屋です", "/admin/rooms/" + roomID.String(), "正規表現が正しくありません", `value="(spam"`},
 		},
 	}
 
@@ -5,6 +5,10 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
 	"mhp-rooms/internal/repository"
 )
 
@@ -41,3 +45,42 @@ func (h *GameVersionHandler) GetActiveGameVersionsAPI(w http.ResponseWriter, r *
 		return
 	}
 }
+
+// GetCatalogAPI ゲームバージョンのモンスター・クエスト図鑑を返す（部屋作成・設定モーダルの候補表示用）
+func (h *GameVersionHandler) GetCatalogAPI(w http.ResponseWriter, r *http.Request) {
+	gameVersionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なゲームバージョンIDです", http.StatusBadRequest)
+		return
+	}
+
+	monsters, err := h.repo.Catalog.GetMonsters(gameVersionID)
+	if err != nil {
+		log.Printf("モンスター図鑑取得エラー: %v", err)
+		http.Error(w, "モンスター一覧の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	quests, err := h.repo.Catalog.GetQuests(gameVersionID, nil)
+	if err != nil {
+		log.Printf("クエスト図鑑取得エラー: %v", err)
+		http.Error(w, "クエスト一覧の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	// 図鑑は更新頻度が低いためブラウザにキャッシュさせる
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+
+	response := struct {
+		Monsters []models.Monster `json:"monsters"`
+		Quests   []models.Quest   `json:"quests"`
+	}{
+		Monsters: monsters,
+		Quests:   quests,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("JSONエンコードエラー: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// newTestRequest chi のルートパラメータとログイン中のユーザーを設定したリクエストを作る
+func newTestRequest(method, target string, body io.Reader, params map[string]string, user *models.User) *http.Request {
+	r := httptest.NewRequest(method, target, body)
+	routeCtx := chi.NewRouteContext()
+	for key, value := range params {
+		routeCtx.URLParams.Add(key, value)
+	}
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = context.WithValue(ctx, middleware.DBUserContextKey, user)
+	return r.WithContext(ctx)
+}
+
+// newTestFormRequest フォームを送る newTestRequest
+func newTestFormRequest(method, target string, form url.Values, params map[string]string, user *models.User) *http.Request {
+	r := newTestRequest(method, target, strings.NewReader(form.Encode()), params, user)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// newTestRoomMessageHandler room に members が参加していて、message が1件だけある RoomMessageHandler を作る
+func newTestRoomMessageHandler(room *models.Room, message *models.RoomMessage, members ...uuid.UUID) *RoomMessageHandler {
+	joined := make(map[uuid.UUID]bool, len(members))
+	for _, id := range members {
+		joined[id] = true
+	}
+	hub := sse.NewHub()
+	go hub.Run()
+	return NewRoomMessageHandler(&repository.Repository{
+		Room:        &fakeRoomRepo{room: room, members: joined},
+		RoomMessage: &fakeRoomMessageRepo{message: message},
+		NGWord:      &fakeNGWordRepo{},
+		RoomLog:     fakeRoomLogRepo{},
+	}, hub)
+}
+
+// fakeRoomRepo 1つの部屋と、その部屋に参加中のユーザーだけを扱うテスト用リポジトリ
+type fakeRoomRepo struct {
+	repository.RoomRepository
+	room    *models.Room
+	members map[uuid.UUID]bool
+}
+
+func (f *fakeRoomRepo) FindRoomByID(id uuid.UUID) (*models.Room, error) {
+	if f.room == nil || id != f.room.ID {
+		return nil, repository.ErrNotFound
+	}
+	copied := *f.room
+	return &copied, nil
+}
+
+func (f *fakeRoomRepo) IsUserJoinedRoom(roomID, userID uuid.UUID) bool {
+	return f.room != nil && roomID == f.room.ID && f.members[userID]
+}
+
+func (f *fakeRoomRepo) GetRoomMembers(roomID uuid.UUID) ([]models.RoomMember, error) {
+	return nil, nil
+}
+
+// fakeRoomMessageRepo 1件のメッセージの取得・編集・削除だけを扱うテスト用リポジトリ
+type fakeRoomMessageRepo struct {
+	repository.RoomMessageRepository
+	message *models.RoomMessage
+	edits   int
+}
+
+func (f *fakeRoomMessageRepo) FindMessageByID(id uuid.UUID) (*models.RoomMessage, error) {
+	if f.message == nil || id != f.message.ID {
+		return nil, repository.ErrNotFound
+	}
+	copied := *f.message
+	return &copied, nil
+}
+
+func (f *fakeRoomMessageRepo) EditMessage(id, editorUserID uuid.UUID, text string) (*models.RoomMessage, error) {
+	f.edits++
+	f.message.Message = text
+	copied := *f.message
+	return &copied, nil
+}
+
+func (f *fakeRoomMessageRepo) DeleteMessage(id, deletedByUserID uuid.UUID) error {
+	f.message.IsDeleted = true
+	return nil
+}
+
+// fakeNGWordRepo 有効なNGワードを返し、一致記録を保持するだけのテスト用リポジトリ
+type fakeNGWordRepo struct {
+	repository.NGWordRepository
+	words []models.NGWord
+	hits  []models.NGWordHit
+}
+
+func (f *fakeNGWordRepo) ListActiveNGWords() ([]models.NGWord, error) { return f.words, nil }
+func (f *fakeNGWordRepo) CreateHits(hits []models.NGWordHit) error {
+	f.hits = append(f.hits, hits...)
+	return nil
+}
+
+// fakeRoomLogRepo 部屋のログを捨てるテスト用リポジトリ
+type fakeRoomLogRepo struct{ repository.RoomLogRepository }
+
+func (fakeRoomLogRepo) CreateLog(*models.RoomLog) error { return nil }
+
+// fakeReactionRepo 付いているリアクションを「ユーザーID:種類」の組で持つテスト用リポジトリ
+type fakeReactionRepo struct {
+	repository.ReactionRepository
+	reactions map[string]bool
+}
+
+func (f *fakeReactionRepo) AddReaction(reaction *models.MessageReaction) error {
+	key := reaction.UserID.String() + ":" + reaction.ReactionType
+	if f.reactions[key] {
+		return repository.ErrReactionExists
+	}
+	f.reactions[key] = true
+	return nil
+}
+
+func (f *fakeReactionRepo) RemoveReaction(messageID, userID uuid.UUID, reactionType string) error {
+	key := userID.String() + ":" + reactionType
+	if !f.reactions[key] {
+		return repository.ErrNotFound
+	}
+	delete(f.reactions, key)
+	return nil
+}
+
+func (f *fakeReactionRepo) CheckReactionTypeExists(code string) error { return nil }
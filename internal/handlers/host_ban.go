@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/middleware"
+)
+
+const (
+	// maxBanReasonLength キック・BANに添える理由の最大文字数
+	maxBanReasonLength = 200
+	// maxKickDurationHours キックで再参加を禁止できる最長時間（30日）
+	maxKickDurationHours = 24 * 30
+)
+
+// normalizeBanReason 理由を前後の空白を除いて返す。空なら nil（理由なし）
+func normalizeBanReason(reason string) (*string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil
+	}
+	if len([]rune(reason)) > maxBanReasonLength {
+		return nil, fmt.Errorf("理由は%d文字以内で入力してください", maxBanReasonLength)
+	}
+	return &reason, nil
+}
+
+// kickUntil キックの期間（時間）から再参加できるようになる日時を求める。0 は無期限（nil）
+func kickUntil(durationHours int, now time.Time) (*time.Time, error) {
+	if durationHours < 0 || durationHours > maxKickDurationHours {
+		return nil, fmt.Errorf("期間は%d時間以内で指定してください", maxKickDurationHours)
+	}
+	if durationHours == 0 {
+		return nil, nil
+	}
+	until := now.Add(time.Duration(durationHours) * time.Hour)
+	return &until, nil
+}
+
+// HostBanItem BANリストタブに表示する1件分
+type HostBanItem struct {
+	UserID      uuid.UUID
+	DisplayName string
+	AvatarURL   string
+	Reason      string
+	BannedAt    string
+}
+
+// hostBansTabData 「BANリスト」タブの描画データ
+type hostBansTabData struct {
+	Bans []HostBanItem
+}
+
+// Bans 自分のBANリストタブコンテンツを返す（htmx用）
+func (ph *ProfileHandler) Bans(w http.ResponseWriter, r *http.Request) {
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていません", http.StatusUnauthorized)
+		return
+	}
+	ph.renderBansTab(w, dbUser.ID)
+}
+
+// Unban BANを解除し、更新後のBANリストタブコンテンツを返す（htmx用）
+func (ph *ProfileHandler) Unban(w http.ResponseWriter, r *http.Request) {
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていません", http.StatusUnauthorized)
+		return
+	}
+	bannedUserID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "無効なユーザーIDです", http.StatusBadRequest)
+		return
+	}
+
+	if err := ph.repo.HostBan.UnbanUser(dbUser.ID, bannedUserID); err != nil {
+		ph.logger.Printf("BAN解除エラー: %v", err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	ph.renderBansTab(w, dbUser.ID)
+}
+
+func (ph *ProfileHandler) renderBansTab(w http.ResponseWriter, hostUserID uuid.UUID) {
+	bans, err := ph.repo.HostBan.GetBansByHost(hostUserID)
+	if err != nil {
+		ph.logger.Printf("BANリスト取得エラー: %v", err)
+		http.Error(w, "BANリストの取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]HostBanItem, len(bans))
+	for i, ban := range bans {
+		items[i] = HostBanItem{
+			UserID:      ban.BannedUserID,
+			DisplayName: ban.Banned.DisplayName,
+			AvatarURL:   getStringValue(ban.Banned.AvatarURL),
+			Reason:      getStringValue(ban.Reason),
+			BannedAt:    formatRelativeTime(ban.CreatedAt),
+		}
+	}
+
+	if err := renderPartialTemplate(w, "profile_bans", hostBansTabData{Bans: items}); err != nil {
+		ph.logger.Printf("テンプレートレンダリングエラー: %v", err)
+		http.Error(w, "テンプレートの描画に失敗しました", http.StatusInternalServerError)
+		return
+	}
+}
+
+// BanUser 他のユーザーのプロフィールから、そのユーザーを自分のBANリストに追加する
+func (ph *ProfileHandler) BanUser(w http.ResponseWriter, r *http.Request) {
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+	targetUserID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "無効なユーザーIDです", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	reason, err := normalizeBanReason(req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	target, err := ph.repo.User.FindUserByID(targetUserID)
+	if err != nil {
+		http.Error(w, "対象のユーザーが見つかりません", http.StatusNotFound)
+		return
+	}
+	if _, err := ph.repo.HostBan.BanUser(dbUser.ID, target.ID, reason); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("%sさんをBANリストに追加しました", target.DisplayName),
+	})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKickUntil(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	after := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		hours   int
+		want    *time.Time
+		wantErr bool
+	}{
+		{name: "0は無期限", hours: 0},
+		{name: "1時間", hours: 1, want: after(time.Hour)},
+		{name: "上限の30日", hours: maxKickDurationHours, want: after(30 * 24 * time.Hour)},
+		{name: "上限超過", hours: maxKickDurationHours + 1, wantErr: true},
+		{name: "負の値", hours: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kickUntil(tt.hours, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("kickUntil(%d) error = %v, wantErr %v", tt.hours, err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("kickUntil(%d) = %v, want %v", tt.hours, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBanReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "空は理由なし", input: "", wantNil: true},
+		{name: "空白のみは理由なし", input: "  　", wantNil: true},
+		{name: "前後の空白を除く", input: " 暴言 ", want: "暴言"},
+		{name: "上限ちょうど", input: strings.Repeat("あ", maxBanReasonLength), want: strings.Repeat("あ", maxBanReasonLength)},
+		{name: "上限超過", input: strings.Repeat("あ", maxBanReasonLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeBanReason(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeBanReason() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("normalizeBanReason() = %q, want nil", *got)
+				}
+				return
+			}
+			if got == nil || *got != tt.want {
+				t.Errorf("normalizeBanReason() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderBansTabPartial(t *testing.T) {
+	chdirRepoRoot(t)
+
+	bannedID := uuid.New()
+	tests := []struct {
+		name string
+		data hostBansTabData
+		want []string
+	}{
+		{
+			name: "BANしたユーザーと解除ボタンを描画",
+			data: hostBansTabData{Bans: []HostBanItem{{UserID: bannedID, DisplayName: "荒らし", Reason: "暴言", BannedAt: "3日前"}}},
+			want: []string{"荒らし", "理由: 暴言", `hx-delete="/api/profile/bans/` + bannedID.String() + `"`, `hx-target="#tab-content"`},
+		},
+		{
+			name: "空のときは案内を描画",
+			data: hostBansTabData{},
+			want: []string{"BANしたユーザーはいません"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := renderPartialTemplate(w, "profile_bans", tt.data); err != nil {
+				t.Fatalf("renderPartialTemplate() error = %v", err)
+			}
+			body := w.Body.String()
+			for _, s := range tt.want {
+				if !strings.Contains(body, s) {
+					t.Errorf("%q が見つからない:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// huntLeaderboardLimit ランキングに表示する人数
+const huntLeaderboardLimit = 50
+
+// HuntRecordRequest クエストのクリアの記録リクエスト。participant_user_ids を省略すると参加中のメンバー全員
+type HuntRecordRequest struct {
+	MonsterID          string   `json:"monster_id"`
+	QuestID            string   `json:"quest_id"`
+	ClearTimeSeconds   int      `json:"clear_time_seconds"`
+	ParticipantUserIDs []string `json:"participant_user_ids"`
+}
+
+// HuntRecordParticipantItem 狩猟記録の参加者
+type HuntRecordParticipantItem struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+}
+
+// HuntRecordItem 部屋の狩猟記録1件分
+type HuntRecordItem struct {
+	ID               uuid.UUID                   `json:"id"`
+	MonsterName      string                      `json:"monster_name"`
+	QuestName        string                      `json:"quest_name,omitempty"`
+	ClearTimeSeconds int                         `json:"clear_time_seconds"`
+	ClearTime        string                      `json:"clear_time"`
+	ClearedAt        time.Time                   `json:"cleared_at"`
+	Participants     []HuntRecordParticipantItem `json:"participants"`
+}
+
+// formatClearTime クリアタイムを「9分05秒」の形式で返す
+func formatClearTime(seconds int) string {
+	return fmt.Sprintf("%d分%02d秒", seconds/60, seconds%60)
+}
+
+// huntLeaderboardURL モンスターのランキングページのURL
+func huntLeaderboardURL(gameVersionCode string, monsterID uuid.UUID) string {
+	query := url.Values{}
+	query.Set("game_version", gameVersionCode)
+	query.Set("monster", monsterID.String())
+	return "/leaderboard?" + query.Encode()
+}
+
+// toHuntRecord リクエストを検証して記録と参加者のユーザーIDに変換する
+func (req HuntRecordRequest) toHuntRecord() (*models.HuntRecord, []uuid.UUID, error) {
+	monsterID, err := uuid.Parse(req.MonsterID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("モンスターを選んでください")
+	}
+	if req.ClearTimeSeconds < 1 || req.ClearTimeSeconds > models.MaxHuntClearSeconds {
+		return nil, nil, fmt.Errorf("クリアタイムは%d分以内で入力してください", models.MaxHuntClearSeconds/60)
+	}
+	record := &models.HuntRecord{
+		MonsterID:        monsterID,
+		ClearTimeSeconds: req.ClearTimeSeconds,
+	}
+	if req.QuestID != "" {
+		questID, err := uuid.Parse(req.QuestID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("無効なクエストです")
+		}
+		record.QuestID = &questID
+	}
+
+	participants := make([]uuid.UUID, 0, len(req.ParticipantUserIDs))
+	for _, idStr := range req.ParticipantUserIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("無効なユーザーIDです")
+		}
+		participants = append(participants, id)
+	}
+	return record, participants, nil
+}
+
+func newHuntRecordItem(record models.HuntRecord) HuntRecordItem {
+	item := HuntRecordItem{
+		ID:               record.ID,
+		MonsterName:      record.Monster.Name,
+		ClearTimeSeconds: record.ClearTimeSeconds,
+		ClearTime:        formatClearTime(record.ClearTimeSeconds),
+		ClearedAt:        record.ClearedAt,
+		Participants:     make([]HuntRecordParticipantItem, 0, len(record.Participants)),
+	}
+	if record.Quest != nil {
+		item.QuestName = record.Quest.Name
+	}
+	for _, p := range record.Participants {
+		item.Participants = append(item.Participants, HuntRecordParticipantItem{
+			UserID:      p.UserID,
+			DisplayName: p.User.DisplayName,
+		})
+	}
+	return item
+}
+
+// CreateHuntRecord ホストが部屋でクリアしたクエストを記録し、チャットに知らせる
+func (h *RoomHandler) CreateHuntRecord(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	var req HuntRecordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	record, participants, err := req.toHuntRecord()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	if room.HostUserID != dbUser.ID {
+		http.Error(w, "部屋のホストのみがクリアを記録できます", http.StatusForbidden)
+		return
+	}
+
+	record.RoomID = roomID
+	record.RecordedByUserID = dbUser.ID
+	record.ClearedAt = time.Now()
+	if err := h.repo.HuntRecord.CreateHuntRecord(record, participants); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	text := fmt.Sprintf("%sを %s でクリアしました！", record.Monster.Name, formatClearTime(record.ClearTimeSeconds))
+	h.broadcastSystemMessage(h.createSystemMessage(roomID, dbUser, text))
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "クリアを記録しました",
+		"id":      record.ID,
+	})
+}
+
+// GetHuntRecords 部屋の狩猟記録を新しい順に返す
+func (h *RoomHandler) GetHuntRecords(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.repo.HuntRecord.GetRecordsByRoom(roomID)
+	if err != nil {
+		log.Printf("狩猟記録の取得に失敗: %v", err)
+		http.Error(w, "狩猟記録の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	items := make([]HuntRecordItem, len(records))
+	for i, record := range records {
+		items[i] = newHuntRecordItem(record)
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"hunt_records": items,
+	})
+}
+
+// MonsterHuntItem 狩猟記録タブのモンスターごとの行
+type MonsterHuntItem struct {
+	GameVersionCode string
+	MonsterName     string
+	Clears          int64
+	BestTime        string
+	LeaderboardURL  string
+}
+
+// huntsTabData 「狩猟記録」タブの描画データ
+type huntsTabData struct {
+	TotalClears  int64
+	GameVersions []repository.GameVersionHuntStat
+	Monsters     []MonsterHuntItem
+}
+
+// Hunts 狩猟記録タブコンテンツを返す（htmx用）。URLにユーザーIDがなければ自分の記録
+func (ph *ProfileHandler) Hunts(w http.ResponseWriter, r *http.Request) {
+	var targetUserID uuid.UUID
+	if userIDParam := chi.URLParam(r, "uuid"); userIDParam != "" {
+		id, err := uuid.Parse(userIDParam)
+		if err != nil {
+			http.Error(w, "無効なユーザーIDです", http.StatusBadRequest)
+			return
+		}
+		targetUserID = id
+	} else {
+		dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+		if !exists || dbUser == nil {
+			http.Error(w, "認証されていません", http.StatusUnauthorized)
+			return
+		}
+		targetUserID = dbUser.ID
+	}
+
+	stats, err := ph.repo.HuntRecord.GetUserHuntStats(targetUserID)
+	if err != nil {
+		ph.logger.Printf("狩猟記録の集計エラー: %v", err)
+		http.Error(w, "狩猟記録の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	data := huntsTabData{
+		TotalClears:  stats.TotalClears,
+		GameVersions: stats.ByGameVersion,
+		Monsters:     make([]MonsterHuntItem, len(stats.ByMonster)),
+	}
+	for i, stat := range stats.ByMonster {
+		data.Monsters[i] = MonsterHuntItem{
+			GameVersionCode: stat.GameVersionCode,
+			MonsterName:     stat.MonsterName,
+			Clears:          stat.Clears,
+			BestTime:        formatClearTime(stat.BestSeconds),
+			LeaderboardURL:  huntLeaderboardURL(stat.GameVersionCode, stat.MonsterID),
+		}
+	}
+
+	if err := renderPartialTemplate(w, "profile_hunts", data); err != nil {
+		ph.logger.Printf("テンプレートレンダリングエラー: %v", err)
+		http.Error(w, "テンプレートの描画に失敗しました", http.StatusInternalServerError)
+		return
+	}
+}
+
+// HuntLeaderboardRow ランキングの1行
+type HuntLeaderboardRow struct {
+	Rank        int
+	UserID      uuid.UUID
+	DisplayName string
+	AvatarURL   string
+	BestTime    string
+	Clears      int64
+}
+
+// HuntLeaderboardPageData ランキングページのデータ
+type HuntLeaderboardPageData struct {
+	GameVersions    []models.GameVersion
+	Monsters        []models.Monster
+	SelectedVersion *models.GameVersion
+	SelectedMonster *models.Monster
+	Entries         []HuntLeaderboardRow
+}
+
+// HuntLeaderboard モンスターごとのクリアタイムのランキング。?game_version=コード&monster=ID で絞り込む
+func (h *GameVersionHandler) HuntLeaderboard(w http.ResponseWriter, r *http.Request) {
+	versions, err := h.repo.GameVersion.GetActiveGameVersions()
+	if err != nil {
+		log.Printf("ゲームバージョン取得エラー: %v", err)
+		http.Error(w, "ゲームバージョンの取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	data := HuntLeaderboardPageData{GameVersions: versions}
+	code := r.URL.Query().Get("game_version")
+	for i := range versions {
+		if versions[i].Code == code {
+			data.SelectedVersion = &versions[i]
+			break
+		}
+	}
+	if data.SelectedVersion == nil && len(versions) > 0 {
+		data.SelectedVersion = &versions[0]
+	}
+
+	if data.SelectedVersion != nil {
+		monsters, err := h.repo.Catalog.GetMonsters(data.SelectedVersion.ID)
+		if err != nil {
+			log.Printf("モンスター図鑑取得エラー: %v", err)
+			http.Error(w, "モンスター一覧の取得に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		data.Monsters = monsters
+
+		// 別のゲームバージョンのモンスターが指定された場合は先頭のモンスターを表示する
+		monsterID, _ := uuid.Parse(r.URL.Query().Get("monster"))
+		for i := range monsters {
+			if monsters[i].ID == monsterID {
+				data.SelectedMonster = &monsters[i]
+				break
+			}
+		}
+		if data.SelectedMonster == nil && len(monsters) > 0 {
+			data.SelectedMonster = &monsters[0]
+		}
+	}
+
+	if data.SelectedMonster != nil {
+		entries, err := h.repo.HuntRecord.GetMonsterLeaderboard(data.SelectedVersion.ID, data.SelectedMonster.ID, huntLeaderboardLimit)
+		if err != nil {
+			log.Printf("ランキング取得エラー: %v", err)
+			http.Error(w, "ランキングの取得に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		data.Entries = make([]HuntLeaderboardRow, len(entries))
+		for i, entry := range entries {
+			data.Entries[i] = HuntLeaderboardRow{
+				Rank:        entry.Rank,
+				UserID:      entry.UserID,
+				DisplayName: entry.DisplayName,
+				AvatarURL:   getStringValue(entry.AvatarURL),
+				BestTime:    formatClearTime(entry.BestSeconds),
+				Clears:      entry.Clears,
+			}
+		}
+	}
+
+	title := "狩猟ランキング"
+	if data.SelectedMonster != nil {
+		title = fmt.Sprintf("%s（%s）の狩猟ランキング", data.SelectedMonster.Name, data.SelectedVersion.Code)
+	}
+	renderTemplate(w, r, "hunt_leaderboard.tmpl", TemplateData{
+		Title:    title,
+		HasHero:  false,
+		User:     r.Context().Value("user"),
+		PageData: data,
+	})
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+	"mhp-rooms/internal/view"
+)
+
+func TestFormatClearTime(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{5, "0分05秒"},
+		{545, "9分05秒"},
+		{models.MaxHuntClearSeconds, "50分00秒"},
+	}
+	for _, tt := range tests {
+		if got := formatClearTime(tt.seconds); got != tt.want {
+			t.Errorf("formatClearTime(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestHuntRecordRequestToHuntRecord(t *testing.T) {
+	monsterID, questID, userID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name             string
+		req              HuntRecordRequest
+		wantErr          bool
+		wantQuest        bool
+		wantParticipants int
+	}{
+		{
+			name: "モンスターとクリアタイムのみ",
+			req:  HuntRecordRequest{MonsterID: monsterID.String(), ClearTimeSeconds: 600},
+		},
+		{
+			name:             "クエストと参加者を指定",
+			req:              HuntRecordRequest{MonsterID: monsterID.String(), QuestID: questID.String(), ClearTimeSeconds: 600, ParticipantUserIDs: []string{userID.String()}},
+			wantQuest:        true,
+			wantParticipants: 1,
+		},
+		{name: "モンスター未選択", req: HuntRecordRequest{ClearTimeSeconds: 600}, wantErr: true},
+		{name: "クリアタイムが0", req: HuntRecordRequest{MonsterID: monsterID.String()}, wantErr: true},
+		{name: "制限時間を超えるクリアタイム", req: HuntRecordRequest{MonsterID: monsterID.String(), ClearTimeSeconds: models.MaxHuntClearSeconds + 1}, wantErr: true},
+		{name: "無効なクエストID", req: HuntRecordRequest{MonsterID: monsterID.String(), QuestID: "x", ClearTimeSeconds: 600}, wantErr: true},
+		{name: "無効な参加者ID", req: HuntRecordRequest{MonsterID: monsterID.String(), ClearTimeSeconds: 600, ParticipantUserIDs: []string{"x"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, participants, err := tt.req.toHuntRecord()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("エラーになるはずが成功した")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toHuntRecord() error = %v", err)
+			}
+			if record.MonsterID != monsterID {
+				t.Errorf("MonsterID = %v, want %v", record.MonsterID, monsterID)
+			}
+			if (record.QuestID != nil) != tt.wantQuest {
+				t.Errorf("QuestID = %v, wantQuest %v", record.QuestID, tt.wantQuest)
+			}
+			if len(participants) != tt.wantParticipants {
+				t.Errorf("参加者 = %d 人, want %d", len(participants), tt.wantParticipants)
+			}
+		})
+	}
+}
+
+func TestRenderHuntViews(t *testing.T) {
+	chdirRepoRoot(t)
+	monsterID := uuid.New()
+
+	t.Run("狩猟記録タブ", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		data := huntsTabData{
+			TotalClears:  3,
+			GameVersions: []repository.GameVersionHuntStat{{GameVersionCode: "MHP2G", Clears: 3}},
+			Monsters: []MonsterHuntItem{
+				{GameVersionCode: "MHP2G", MonsterName: "リオレウス", Clears: 3, BestTime: formatClearTime(545), LeaderboardURL: huntLeaderboardURL("MHP2G", monsterID)},
+			},
+		}
+		if err := renderPartialTemplate(w, "profile_hunts", data); err != nil {
+			t.Fatalf("renderPartialTemplate() error = %v", err)
+		}
+		body := w.Body.String()
+		for _, want := range []string{"リオレウス", "9分05秒", "3回", "/leaderboard?game_version=MHP2G&amp;monster=" + monsterID.String()} {
+			if !strings.Contains(body, want) {
+				t.Errorf("描画結果に %q が含まれていない", want)
+			}
+		}
+	})
+
+	t.Run("ランキングページ", func(t *testing.T) {
+		version := models.GameVersion{BaseModel: models.BaseModel{ID: uuid.New()}, Code: "MHP2G", Name: "MHP2G"}
+		monster := models.Monster{BaseModel: models.BaseModel{ID: monsterID}, Name: "リオレウス"}
+		w := httptest.NewRecorder()
+		view.Template(w, "hunt_leaderboard.tmpl", view.Data{
+			Title: "ランキング",
+			PageData: HuntLeaderboardPageData{
+				GameVersions:    []models.GameVersion{version},
+				Monsters:        []models.Monster{monster},
+				SelectedVersion: &version,
+				SelectedMonster: &monster,
+				Entries: []HuntLeaderboardRow{
+					{Rank: 1, UserID: uuid.New(), DisplayName: "ハンター花子", BestTime: "8分30秒", Clears: 2},
+				},
+			},
+		})
+		body := w.Body.String()
+		for _, want := range []string{"ハンター花子", "8分30秒", "selected"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("描画結果に %q が含まれていない", want)
+			}
+		}
+	})
+}
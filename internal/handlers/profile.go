@@ -12,6 +12,7 @@ import (
 	"mhp-rooms/internal/middleware"
 	"mhp-rooms/internal/models"
 	"mhp-rooms/internal/repository"
+	"mhp-rooms/internal/services"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -19,9 +20,10 @@ import (
 
 type ProfileHandler struct {
 	BaseHandler
-	logger   *log.Logger
-	uploader *storage.GCSUploader
-	jwtAuth  *middleware.JWTAuth
+	logger       *log.Logger
+	uploader     *storage.GCSUploader
+	jwtAuth      *middleware.JWTAuth
+	ngWordFilter *services.NGWordFilter
 }
 
 func NewProfileHandler(repo *repository.Repository, jwtAuth *middleware.JWTAuth) *ProfileHandler {
@@ -37,9 +39,10 @@ func NewProfileHandler(repo *repository.Repository, jwtAuth *middleware.JWTAuth)
 		BaseHandler: BaseHandler{
 			repo: repo,
 		},
-		logger:   log.New(log.Writer(), "[ProfileHandler] ", log.LstdFlags),
-		uploader: uploader,
-		jwtAuth:  jwtAuth,
+		logger:       log.New(log.Writer(), "[ProfileHandler] ", log.LstdFlags),
+		uploader:     uploader,
+		jwtAuth:      jwtAuth,
+		ngWordFilter: services.NewNGWordFilter(repo),
 	}
 }
 
@@ -90,6 +93,8 @@ type RoomSummary struct {
 	StatusNote  string    `json:"statusNote"`
 	CreatedAt   string    `json:"createdAt"`
 	IsClickable bool      `json:"isClickable"`
+	// 解散済みの部屋のセッションのまとめ。ホスト本人が見る場合のみ設定する
+	Session *SessionSummaryView `json:"session,omitempty"`
 }
 
 type Follower struct {
@@ -128,7 +133,7 @@ func (ph *ProfileHandler) Profile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 作成した部屋の1ページ目を取得（タブ初期表示用）
-	rooms, roomsPagination, err := ph.hostedRoomsPage(user.ID, 1, "/api/profile/rooms")
+	rooms, roomsPagination, err := ph.hostedRoomsPage(user.ID, &user.ID, 1, "/api/profile/rooms")
 	if err != nil {
 		ph.logger.Printf("部屋取得エラー: %v", err)
 	}
@@ -298,7 +303,7 @@ func (ph *ProfileHandler) Rooms(w http.ResponseWriter, r *http.Request) {
 		targetUserID = user.ID
 	}
 
-	rooms, pagination, err := ph.hostedRoomsPage(targetUserID, parsePageParam(r), r.URL.Path)
+	rooms, pagination, err := ph.hostedRoomsPage(targetUserID, viewerUserID(r), parsePageParam(r), r.URL.Path)
 	if err != nil {
 		ph.logger.Printf("部屋取得エラー: %v", err)
 		http.Error(w, "部屋データの取得に失敗しました", http.StatusInternalServerError)
@@ -498,21 +503,42 @@ func formatRelativeTime(t time.Time) string {
 	}
 }
 
-// hostedRoomsPage ユーザーが作成した部屋の指定ページとページ情報を返す
-func (b *BaseHandler) hostedRoomsPage(userID uuid.UUID, page int, baseURL string) ([]RoomSummary, Pagination, error) {
-	total, err := b.repo.Room.CountRoomsByHostUser(userID)
+// hostedRoomsPage ユーザーが作成した部屋のうち viewerID に公開されている部屋の指定ページとページ情報を返す
+func (b *BaseHandler) hostedRoomsPage(userID uuid.UUID, viewerID *uuid.UUID, page int, baseURL string) ([]RoomSummary, Pagination, error) {
+	total, err := b.repo.Room.CountRoomsByHostUser(userID, viewerID)
 	if err != nil {
 		return nil, Pagination{}, fmt.Errorf("count rooms by host user: %w", err)
 	}
 
-	rooms, err := b.repo.Room.GetRoomsByHostUser(userID, tabPerPage, (page-1)*tabPerPage)
+	rooms, err := b.repo.Room.GetRoomsByHostUser(userID, viewerID, tabPerPage, (page-1)*tabPerPage)
 	if err != nil {
 		return nil, Pagination{}, fmt.Errorf("get rooms by host user: %w", err)
 	}
 
+	// セッションのまとめはホスト本人にだけ見せる
+	var sessions map[uuid.UUID]*models.RoomSessionSummary
+	if viewerID != nil && *viewerID == userID {
+		roomIDs := make([]uuid.UUID, 0, len(rooms))
+		for _, room := range rooms {
+			if !room.IsActive {
+				roomIDs = append(roomIDs, room.ID)
+			}
+		}
+		sessions, err = b.repo.RoomSession.FindByRoomIDs(roomIDs)
+		if err != nil {
+			// まとめは補助情報のため、取得に失敗しても一覧表示は継続する
+			log.Printf("セッションのまとめの取得に失敗: %v", err)
+		}
+	}
+
 	var summaries []RoomSummary
 	for _, room := range rooms {
-		summaries = append(summaries, roomToSummary(room))
+		summary := roomToSummary(room)
+		if session, ok := sessions[room.ID]; ok {
+			sessionView := newSessionSummaryView(session)
+			summary.Session = &sessionView
+		}
+		summaries = append(summaries, summary)
 	}
 
 	return summaries, newPagination(total, page, tabPerPage, baseURL), nil
@@ -542,6 +568,11 @@ func roomToSummary(room models.Room) RoomSummary {
 		status = "終了"
 		statusColor = "text-red-600"
 		isClickable = false
+	} else if room.IsUpcoming(time.Now()) {
+		status = "開始前"
+		statusColor = "text-blue-600"
+		statusNote = room.ScheduledStartAt.In(adminJST).Format("1/2 15:04") + " 開始予定"
+		isClickable = true
 	} else {
 		status = "アクティブ"
 		statusColor = "text-green-600"
@@ -605,6 +636,17 @@ func (ph *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// NGワードのチェック（block は拒否、mask は伏せ字にして受け付ける）
+	var err error
+	if req.DisplayName, err = ph.ngWordFilter.Apply(user.ID, nil, models.NGWordTargetDisplayName, req.DisplayName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Bio, err = ph.ngWordFilter.Apply(user.ID, nil, models.NGWordTargetBio, req.Bio); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// ユーザー情報を更新
 	user.DisplayName = req.DisplayName
 	if req.Bio != "" {
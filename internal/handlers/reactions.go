@@ -3,31 +3,52 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
 	"mhp-rooms/internal/models"
 	"mhp-rooms/internal/repository"
 )
 
+// リアクションの変化（ReactionUpdateData.Action）
+const (
+	ReactionActionAdded   = "added"
+	ReactionActionRemoved = "removed"
+)
+
 // ReactionHandler はリアクション関連のハンドラー
 type ReactionHandler struct {
 	BaseHandler
+	hub *sse.Hub
 }
 
 // NewReactionHandler は新しいReactionHandlerを作成する
-func NewReactionHandler(repo *repository.Repository) *ReactionHandler {
+func NewReactionHandler(repo *repository.Repository, hub *sse.Hub) *ReactionHandler {
 	return &ReactionHandler{
 		BaseHandler: BaseHandler{repo: repo},
+		hub:         hub,
 	}
 }
 
+// ReactionUpdateData SSE の reaction_update で送るリアクションの変化と、変化後のメッセージの集計
+type ReactionUpdateData struct {
+	MessageID      uuid.UUID                     `json:"message_id"`
+	UserID         uuid.UUID                     `json:"user_id"`
+	SupabaseUserID uuid.UUID                     `json:"supabase_user_id"`
+	ReactionType   string                        `json:"reaction_type"`
+	Action         string                        `json:"action"`    // added / removed
+	Reactions      []models.MessageReactionCount `json:"reactions"` // has_reacted は含まない（受け取った側で判定する）
+}
+
 // AddReaction はメッセージにリアクションを追加する
 func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
 	// ユーザー認証チェック
-	userID, ok := r.Context().Value("userID").(uuid.UUID)
-	if !ok {
+	user, ok := middleware.GetDBUserFromContext(r.Context())
+	if !ok || user == nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -49,11 +70,20 @@ func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// メッセージの存在確認
-	if err := h.repo.Reaction.CheckMessageExists(messageID); err != nil {
+	// メッセージの存在確認（リアクションは部屋のメンバーが削除されていないチャット・画像に付けられる）
+	message, err := h.repo.RoomMessage.FindMessageByID(messageID)
+	if err != nil {
 		http.Error(w, "Message not found", http.StatusNotFound)
 		return
 	}
+	if !message.IsUserMessage() || message.IsDeleted {
+		http.Error(w, "Cannot react to this message", http.StatusBadRequest)
+		return
+	}
+	if !h.repo.Room.IsUserJoinedRoom(message.RoomID, user.ID) {
+		http.Error(w, "Not a member of this room", http.StatusForbidden)
+		return
+	}
 
 	// リアクションタイプの有効性確認
 	if err := h.repo.Reaction.CheckReactionTypeExists(req.ReactionType); err != nil {
@@ -64,13 +94,13 @@ func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
 	// リアクションを追加
 	reaction := &models.MessageReaction{
 		MessageID:    messageID,
-		UserID:       userID,
+		UserID:       user.ID,
 		ReactionType: req.ReactionType,
 	}
 
 	if err := h.repo.Reaction.AddReaction(reaction); err != nil {
 		// 既にリアクションが存在する場合
-		if err.Error() == "ERROR: duplicate key value violates unique constraint \"unique_user_message_reaction\" (SQLSTATE 23505)" {
+		if errors.Is(err, repository.ErrReactionExists) {
 			http.Error(w, "Reaction already exists", http.StatusConflict)
 			return
 		}
@@ -78,6 +108,8 @@ func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.broadcastReactionUpdate(message, user, req.ReactionType, ReactionActionAdded)
+
 	// 成功レスポンス
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -89,8 +121,8 @@ func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
 // RemoveReaction はメッセージからリアクションを削除する
 func (h *ReactionHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
 	// ユーザー認証チェック
-	userID, ok := r.Context().Value("userID").(uuid.UUID)
-	if !ok {
+	user, ok := middleware.GetDBUserFromContext(r.Context())
+	if !ok || user == nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -105,8 +137,19 @@ func (h *ReactionHandler) RemoveReaction(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	message, err := h.repo.RoomMessage.FindMessageByID(messageID)
+	if err != nil {
+		http.Error(w, "Message not found", http.StatusNotFound)
+		return
+	}
+	// 付けるときと同じく、外せるのも部屋のメンバーだけ
+	if !h.repo.Room.IsUserJoinedRoom(message.RoomID, user.ID) {
+		http.Error(w, "Not a member of this room", http.StatusForbidden)
+		return
+	}
+
 	// リアクションを削除
-	if err := h.repo.Reaction.RemoveReaction(messageID, userID, reactionType); err != nil {
+	if err := h.repo.Reaction.RemoveReaction(messageID, user.ID, reactionType); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			http.Error(w, "Reaction not found", http.StatusNotFound)
 			return
@@ -115,6 +158,8 @@ func (h *ReactionHandler) RemoveReaction(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.broadcastReactionUpdate(message, user, reactionType, ReactionActionRemoved)
+
 	// 成功レスポンス
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -134,12 +179,9 @@ func (h *ReactionHandler) GetMessageReactions(w http.ResponseWriter, r *http.Req
 	}
 
 	// 現在のユーザーID（ログインしていない場合もある）
-	userID, isAuthenticated := r.Context().Value("userID").(uuid.UUID)
-
-	// リアクションの集計データを取得
 	var userIDPtr *uuid.UUID
-	if isAuthenticated {
-		userIDPtr = &userID
+	if user, ok := middleware.GetDBUserFromContext(r.Context()); ok && user != nil {
+		userIDPtr = &user.ID
 	}
 
 	reactionCounts, err := h.repo.Reaction.GetMessageReactions(messageID, userIDPtr)
@@ -170,3 +212,32 @@ func (h *ReactionHandler) GetAvailableReactions(w http.ResponseWriter, r *http.R
 		"types":   reactionTypes,
 	})
 }
+
+// broadcastReactionUpdate リアクションの変化と変化後の集計を部屋のメンバーに SSE で送る
+func (h *ReactionHandler) broadcastReactionUpdate(message *models.RoomMessage, user *models.User, reactionType, action string) {
+	if h.hub == nil {
+		return
+	}
+
+	reactions, err := h.repo.Reaction.GetMessageReactions(message.ID, nil)
+	if err != nil {
+		log.Printf("リアクションの集計に失敗しました message_id=%s: %v", message.ID, err)
+		return
+	}
+	if reactions == nil {
+		reactions = []models.MessageReactionCount{}
+	}
+
+	h.hub.BroadcastToRoom(message.RoomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "reaction_update",
+		Data: ReactionUpdateData{
+			MessageID:      message.ID,
+			UserID:         user.ID,
+			SupabaseUserID: user.SupabaseUserID,
+			ReactionType:   reactionType,
+			Action:         action,
+			Reactions:      reactions,
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+func TestReactionsRequireMember(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+
+	tests := []struct {
+		name     string
+		member   bool
+		wantCode int
+	}{
+		{name: "参加中のメンバー", member: true, wantCode: http.StatusOK},
+		{name: "退出・キックされたメンバー", wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, HostUserID: uuid.New(), IsActive: true}
+			message := &models.RoomMessage{
+				BaseModel:   models.BaseModel{ID: uuid.New()},
+				RoomID:      room.ID,
+				UserID:      uuid.New(),
+				Message:     "よろしく",
+				MessageType: models.RoomMessageTypeChat,
+			}
+			// 参加中に付けたリアクションが残っている
+			reactions := &fakeReactionRepo{reactions: map[string]bool{user.ID.String() + ":fire": true}}
+			h := NewReactionHandler(&repository.Repository{
+				Room:        &fakeRoomRepo{room: room, members: map[uuid.UUID]bool{user.ID: tt.member}},
+				RoomMessage: &fakeRoomMessageRepo{message: message},
+				Reaction:    reactions,
+			}, nil)
+			target := "/api/messages/" + message.ID.String() + "/reactions"
+
+			w := httptest.NewRecorder()
+			h.AddReaction(w, newTestRequest("POST", target, strings.NewReader(`{"reaction_type":"thumbs_up"}`),
+				map[string]string{"messageId": message.ID.String()}, user))
+			if w.Code != tt.wantCode {
+				t.Errorf("追加: status = %d, want %d", w.Code, tt.wantCode)
+			}
+
+			w = httptest.NewRecorder()
+			h.RemoveReaction(w, newTestRequest("DELETE", target+"/fire", nil,
+				map[string]string{"messageId": message.ID.String(), "reactionType": "fire"}, user))
+			if w.Code != tt.wantCode {
+				t.Errorf("削除: status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if removed := !reactions.reactions[user.ID.String()+":fire"]; removed != tt.member {
+				t.Errorf("削除された = %v, want %v", removed, tt.member)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// ターゲットモンスターの最大文字数（rooms.target_monster は varchar(100)）
+const maxTargetMonsterLength = 50
+
+// roomCatalogSelection 部屋のターゲットモンスター・クエストを図鑑と照合した結果
+type roomCatalogSelection struct {
+	TargetMonster *string    // 表示用のモンスター名。図鑑に一致した場合は図鑑の表記に揃える
+	MonsterID     *uuid.UUID // 図鑑に一致しない自由入力の場合は nil
+	QuestID       *uuid.UUID
+}
+
+// resolveRoomCatalog リクエストのモンスター・クエスト指定を図鑑と照合する。
+// monster_id / quest_id が指定されていればそれを優先し、なければ target_monster の名前で図鑑を引く。
+// 図鑑に無い名前は自由入力としてそのまま残す
+func resolveRoomCatalog(catalog repository.CatalogRepository, gameVersionID uuid.UUID, req CreateRoomRequest) (roomCatalogSelection, error) {
+	var selection roomCatalogSelection
+
+	var quest *models.Quest
+	if questIDStr := strings.TrimSpace(req.QuestID); questIDStr != "" {
+		questID, err := uuid.Parse(questIDStr)
+		if err != nil {
+			return selection, fmt.Errorf("無効なクエストIDです")
+		}
+		quest, err = catalog.FindQuestByID(questID)
+		if err != nil || !quest.IsActive || quest.GameVersionID != gameVersionID {
+			return selection, fmt.Errorf("選択したクエストはこのゲームでは選べません")
+		}
+		selection.QuestID = &quest.ID
+	}
+
+	var monster *models.Monster
+	targetMonster := strings.TrimSpace(req.TargetMonster)
+	if monsterIDStr := strings.TrimSpace(req.MonsterID); monsterIDStr != "" {
+		monsterID, err := uuid.Parse(monsterIDStr)
+		if err != nil {
+			return selection, fmt.Errorf("無効なモンスターIDです")
+		}
+		monster, err = catalog.FindMonsterByID(monsterID)
+		if err != nil || !monster.IsActive || monster.GameVersionID != gameVersionID {
+			return selection, fmt.Errorf("選択したモンスターはこのゲームでは選べません")
+		}
+	} else if targetMonster != "" {
+		if len([]rune(targetMonster)) > maxTargetMonsterLength {
+			return selection, fmt.Errorf("ターゲットモンスターは%d文字以内で入力してください", maxTargetMonsterLength)
+		}
+		found, err := catalog.FindMonsterByName(gameVersionID, targetMonster)
+		if err != nil {
+			return selection, err
+		}
+		monster = found
+	} else if quest != nil && quest.Monster != nil {
+		// モンスター未入力でクエストだけ選んだ場合はクエストのメインターゲットを使う
+		monster = quest.Monster
+	}
+
+	switch {
+	case monster != nil:
+		name := monster.Name
+		selection.TargetMonster = &name
+		selection.MonsterID = &monster.ID
+	case targetMonster != "":
+		selection.TargetMonster = &targetMonster
+	}
+
+	return selection, nil
+}
+
+// apply 照合結果を部屋に反映する。関連の読み込み済みデータは外部キーと食い違わないよう外す
+func (s roomCatalogSelection) apply(room *models.Room) {
+	room.TargetMonster = s.TargetMonster
+	room.MonsterID = s.MonsterID
+	room.QuestID = s.QuestID
+	room.Monster = nil
+	room.Quest = nil
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+// fakeCatalogRepo 図鑑をメモリ上に持つ CatalogRepository
+type fakeCatalogRepo struct {
+	monsters []models.Monster
+	quests   []models.Quest
+}
+
+func (f *fakeCatalogRepo) GetMonsters(gameVersionID uuid.UUID) ([]models.Monster, error) {
+	var result []models.Monster
+	for _, m := range f.monsters {
+		if m.GameVersionID == gameVersionID {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeCatalogRepo) FindMonsterByID(id uuid.UUID) (*models.Monster, error) {
+	for i := range f.monsters {
+		if f.monsters[i].ID == id {
+			return &f.monsters[i], nil
+		}
+	}
+	return nil, fmt.Errorf("モンスターが見つかりません")
+}
+
+func (f *fakeCatalogRepo) FindMonsterByName(gameVersionID uuid.UUID, name string) (*models.Monster, error) {
+	for i := range f.monsters {
+		if f.monsters[i].GameVersionID == gameVersionID && strings.EqualFold(f.monsters[i].Name, strings.TrimSpace(name)) {
+			return &f.monsters[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeCatalogRepo) GetQuests(gameVersionID uuid.UUID, monsterID *uuid.UUID) ([]models.Quest, error) {
+	return f.quests, nil
+}
+
+func (f *fakeCatalogRepo) FindQuestByID(id uuid.UUID) (*models.Quest, error) {
+	for i := range f.quests {
+		if f.quests[i].ID == id {
+			quest := f.quests[i]
+			if quest.MonsterID != nil {
+				quest.Monster, _ = f.FindMonsterByID(*quest.MonsterID)
+			}
+			return &quest, nil
+		}
+	}
+	return nil, fmt.Errorf("クエストが見つかりません")
+}
+
+func TestResolveRoomCatalog(t *testing.T) {
+	mhp2g, mhp3 := uuid.New(), uuid.New()
+	tigrex := models.Monster{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: mhp2g, Name: "ティガレックス", IsActive: true}
+	rajang := models.Monster{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: mhp2g, Name: "ラージャン", IsActive: true}
+	jinouga := models.Monster{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: mhp3, Name: "ジンオウガ", IsActive: true}
+	rajangQuest := models.Quest{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: mhp2g, MonsterID: &rajang.ID, Name: "ラージャンの狩猟（G級）", IsActive: true}
+	repo := &fakeCatalogRepo{
+		monsters: []models.Monster{tigrex, rajang, jinouga},
+		quests:   []models.Quest{rajangQuest},
+	}
+
+	tests := []struct {
+		name        string
+		req         CreateRoomRequest
+		wantTarget  string
+		wantMonster *uuid.UUID
+		wantQuest   *uuid.UUID
+		wantErr     bool
+	}{
+		{
+			name: "指定なし",
+			req:  CreateRoomRequest{},
+		},
+		{
+			name:        "図鑑の名前に一致すれば紐付ける",
+			req:         CreateRoomRequest{TargetMonster: " ティガレックス "},
+			wantTarget:  "ティガレックス",
+			wantMonster: &tigrex.ID,
+		},
+		{
+			name:       "図鑑に無い名前は自由入力のまま",
+			req:        CreateRoomRequest{TargetMonster: "ティガ亜種"},
+			wantTarget: "ティガ亜種",
+		},
+		{
+			name:        "モンスターIDは名前より優先",
+			req:         CreateRoomRequest{TargetMonster: "てぃが", MonsterID: tigrex.ID.String()},
+			wantTarget:  "ティガレックス",
+			wantMonster: &tigrex.ID,
+		},
+		{
+			name:    "別のゲームのモンスターは選べない",
+			req:     CreateRoomRequest{MonsterID: jinouga.ID.String()},
+			wantErr: true,
+		},
+		{
+			name:        "クエストだけ選ぶとメインターゲットを使う",
+			req:         CreateRoomRequest{QuestID: rajangQuest.ID.String()},
+			wantTarget:  "ラージャン",
+			wantMonster: &rajang.ID,
+			wantQuest:   &rajangQuest.ID,
+		},
+		{
+			name:    "存在しないクエスト",
+			req:     CreateRoomRequest{QuestID: uuid.New().String()},
+			wantErr: true,
+		},
+		{
+			name:    "長すぎる自由入力",
+			req:     CreateRoomRequest{TargetMonster: strings.Repeat("ア", maxTargetMonsterLength+1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveRoomCatalog(repo, mhp2g, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			gotTarget := ""
+			if got.TargetMonster != nil {
+				gotTarget = *got.TargetMonster
+			}
+			if gotTarget != tt.wantTarget {
+				t.Errorf("TargetMonster = %q, want %q", gotTarget, tt.wantTarget)
+			}
+			if fmt.Sprint(got.MonsterID) != fmt.Sprint(tt.wantMonster) {
+				t.Errorf("MonsterID = %v, want %v", got.MonsterID, tt.wantMonster)
+			}
+			if fmt.Sprint(got.QuestID) != fmt.Sprint(tt.wantQuest) {
+				t.Errorf("QuestID = %v, want %v", got.QuestID, tt.wantQuest)
+			}
+		})
+	}
+}
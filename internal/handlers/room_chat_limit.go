@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+// チャット送信を断った理由（429 のレスポンスの error）
+const (
+	chatLimitRateLimited = "RATE_LIMITED" // 短時間に送りすぎた
+	chatLimitSlowMode    = "SLOW_MODE"    // 低速モードの間隔が空いていない
+	chatLimitMuted       = "MUTED"        // 制限を繰り返し超えたため一時的にミュート中
+)
+
+const (
+	// chatStrikeWindow この時間内に制限を StrikeLimit 回超えるとミュートする
+	chatStrikeWindow = time.Minute
+	// chatOffenseMemory 前回のミュートからこの時間内に再びミュートされると、ミュートの時間を倍にする
+	chatOffenseMemory = time.Hour
+	// chatMaxMute ミュートの最長時間
+	chatMaxMute = 30 * time.Minute
+	// chatLimiterCleanupInterval 使われなくなった記録を掃除する間隔
+	chatLimiterCleanupInterval = time.Minute
+)
+
+// ChatRateLimitConfig チャット送信のレート制限の設定
+type ChatRateLimitConfig struct {
+	PerMinute   int           // 送信枠が回復する速さ（1分あたりの件数）
+	Burst       int           // 続けて送れる件数
+	StrikeLimit int           // chatStrikeWindow 内にこの回数だけ制限を超えるとミュートする
+	Mute        time.Duration // 最初のミュートの時間。繰り返すと倍になる
+}
+
+// loadChatRateLimitConfig 環境変数からチャット送信のレート制限の設定を取得
+func loadChatRateLimitConfig() ChatRateLimitConfig {
+	config := ChatRateLimitConfig{
+		PerMinute:   20,              // デフォルト: 1分あたり20件（3秒に1件）
+		Burst:       5,               // デフォルト: 5件までは続けて送れる
+		StrikeLimit: 5,               // デフォルト: 1分間に5回制限を超えるとミュート
+		Mute:        2 * time.Minute, // デフォルト: 2分間（繰り返すと4分、8分…と延びる）
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("CHAT_RATE_LIMIT_PER_MINUTE")); err == nil && v > 0 {
+		config.PerMinute = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHAT_RATE_LIMIT_BURST")); err == nil && v > 0 {
+		config.Burst = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHAT_MUTE_STRIKES")); err == nil && v > 0 {
+		config.StrikeLimit = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHAT_MUTE_SECONDS")); err == nil && v > 0 {
+		config.Mute = time.Duration(v) * time.Second
+	}
+
+	return config
+}
+
+// chatLimitResult 送信してよいかの判定結果
+type chatLimitResult struct {
+	Allowed    bool
+	Reason     string        // 断った理由（chatLimitRateLimited など）
+	RetryAfter time.Duration // 次に送れるようになるまでの時間
+	MutedNow   bool          // この送信でミュートされた
+}
+
+// chatLimiter 部屋×ユーザーごとに送信枠（トークンバケット）と低速モード・ミュートの状態を管理する
+type chatLimiter struct {
+	mu          sync.Mutex
+	config      ChatRateLimitConfig
+	refill      time.Duration // 送信枠が1件分回復する間隔
+	states      map[chatLimitKey]*chatSendState
+	lastCleanup time.Time
+}
+
+type chatLimitKey struct {
+	roomID uuid.UUID
+	userID uuid.UUID
+}
+
+// chatSendState 1人の1部屋での送信の状態
+type chatSendState struct {
+	tokens     float64
+	updatedAt  time.Time // tokens を計算した時刻
+	lastSentAt time.Time
+	prevSentAt time.Time   // lastSentAt の1つ前の送信時刻（refund で戻すため）
+	strikes    []time.Time // chatStrikeWindow 内に制限を超えた時刻
+	mutedUntil time.Time
+	mutes      int // chatOffenseMemory 内に続けてミュートされた回数
+	lastMuteAt time.Time
+}
+
+func newChatLimiter(config ChatRateLimitConfig) *chatLimiter {
+	return &chatLimiter{
+		config: config,
+		refill: time.Minute / time.Duration(config.PerMinute),
+		states: make(map[chatLimitKey]*chatSendState),
+	}
+}
+
+// check now の時点でメッセージを送ってよいか。送ってよければ送信枠を1件使う。
+// slowMode は部屋の低速モードの間隔で、ホストなど対象外のユーザーは 0 を渡す。
+// 入力の検証を済ませてから呼び、保存に失敗したら refund で戻す
+func (l *chatLimiter) check(roomID, userID uuid.UUID, slowMode time.Duration, now time.Time) chatLimitResult {
+	return l.take(roomID, userID, slowMode, true, now)
+}
+
+// checkEdit now の時点でメッセージを編集してよいか。編集も部屋に配信されるため送信と同じ枠・ミュートを使うが、
+// 新しいメッセージは増えないので低速モードの間隔には数えない
+func (l *chatLimiter) checkEdit(roomID, userID uuid.UUID, now time.Time) chatLimitResult {
+	return l.take(roomID, userID, 0, false, now)
+}
+
+// refund check / checkEdit で使った送信枠を戻す（send は check で使った枠か）
+func (l *chatLimiter) refund(roomID, userID uuid.UUID, send bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.states[chatLimitKey{roomID: roomID, userID: userID}]
+	if !ok {
+		return
+	}
+	state.tokens++
+	if state.tokens > float64(l.config.Burst) {
+		state.tokens = float64(l.config.Burst)
+	}
+	if send {
+		state.lastSentAt = state.prevSentAt
+	}
+}
+
+func (l *chatLimiter) take(roomID, userID uuid.UUID, slowMode time.Duration, send bool, now time.Time) chatLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cleanup(now)
+
+	key := chatLimitKey{roomID: roomID, userID: userID}
+	state, ok := l.states[key]
+	if !ok {
+		state = &chatSendState{tokens: float64(l.config.Burst), updatedAt: now}
+		l.states[key] = state
+	}
+
+	// ミュート中は制限超過に数えない（ミュートが延び続けないようにする）
+	if now.Before(state.mutedUntil) {
+		return chatLimitResult{Reason: chatLimitMuted, RetryAfter: state.mutedUntil.Sub(now)}
+	}
+
+	state.tokens += float64(now.Sub(state.updatedAt)) / float64(l.refill)
+	if state.tokens > float64(l.config.Burst) {
+		state.tokens = float64(l.config.Burst)
+	}
+	state.updatedAt = now
+
+	if slowMode > 0 && !state.lastSentAt.IsZero() {
+		if wait := slowMode - now.Sub(state.lastSentAt); wait > 0 {
+			return l.strike(state, chatLimitResult{Reason: chatLimitSlowMode, RetryAfter: wait}, now)
+		}
+	}
+	if state.tokens < 1 {
+		wait := time.Duration((1 - state.tokens) * float64(l.refill))
+		return l.strike(state, chatLimitResult{Reason: chatLimitRateLimited, RetryAfter: wait}, now)
+	}
+
+	state.tokens--
+	if send {
+		state.prevSentAt = state.lastSentAt
+		state.lastSentAt = now
+	}
+	return chatLimitResult{Allowed: true}
+}
+
+// strike 制限を超えたことを記録し、回数が上限に達したらミュートする
+func (l *chatLimiter) strike(state *chatSendState, result chatLimitResult, now time.Time) chatLimitResult {
+	recent := state.strikes[:0]
+	for _, at := range state.strikes {
+		if now.Sub(at) < chatStrikeWindow {
+			recent = append(recent, at)
+		}
+	}
+	state.strikes = append(recent, now)
+	if len(state.strikes) < l.config.StrikeLimit {
+		return result
+	}
+
+	if !state.lastMuteAt.IsZero() && now.Sub(state.lastMuteAt) < chatOffenseMemory {
+		state.mutes++
+	} else {
+		state.mutes = 1
+	}
+	mute := l.config.Mute
+	for i := 1; i < state.mutes && mute < chatMaxMute; i++ {
+		mute *= 2
+	}
+	if mute > chatMaxMute {
+		mute = chatMaxMute
+	}
+
+	state.strikes = nil
+	state.lastMuteAt = now
+	state.mutedUntil = now.Add(mute)
+	return chatLimitResult{Reason: chatLimitMuted, RetryAfter: mute, MutedNow: true}
+}
+
+// cleanup しばらく送信がなく、ミュートの履歴も意味を持たなくなった記録を捨てる
+func (l *chatLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < chatLimiterCleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+	for key, state := range l.states {
+		if now.Sub(state.updatedAt) >= chatOffenseMemory && now.After(state.mutedUntil) {
+			delete(l.states, key)
+		}
+	}
+}
+
+// ChatLimitResponse チャット送信を断ったときのレスポンス（429）
+type ChatLimitResponse struct {
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after"` // 次に送れるようになるまでの秒数
+}
+
+// newChatLimitResponse 判定結果からレスポンスを作る。待ち時間は秒単位に切り上げる
+func newChatLimitResponse(result chatLimitResult) ChatLimitResponse {
+	seconds := int((result.RetryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	var message string
+	switch {
+	case result.MutedNow:
+		message = fmt.Sprintf("短時間に送信を繰り返したため、%s間チャットを送信できません", formatChatWait(seconds))
+	case result.Reason == chatLimitMuted:
+		message = fmt.Sprintf("一時的にチャットを送信できません。あと%sお待ちください", formatChatWait(seconds))
+	case result.Reason == chatLimitSlowMode:
+		message = fmt.Sprintf("低速モード中です。あと%sで送信できます", formatChatWait(seconds))
+	default:
+		message = fmt.Sprintf("送信が早すぎます。あと%sで送信できます", formatChatWait(seconds))
+	}
+	return ChatLimitResponse{Error: result.Reason, Message: message, RetryAfter: seconds}
+}
+
+// respondChatLimited 429 と Retry-After を返す
+func respondChatLimited(w http.ResponseWriter, result chatLimitResult) {
+	response := newChatLimitResponse(result)
+	w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+	respondWithJSON(w, http.StatusTooManyRequests, response)
+}
+
+// allowChat check / checkEdit の結果を見て、断る場合は 429 を返して false を返す
+func (h *RoomMessageHandler) allowChat(w http.ResponseWriter, roomID uuid.UUID, user *models.User, result chatLimitResult) bool {
+	if result.Allowed {
+		return true
+	}
+	if result.MutedNow {
+		h.logAutoMute(roomID, user, result.RetryAfter)
+	}
+	respondChatLimited(w, result)
+	return false
+}
+
+// logAutoMute 自動ミュートを部屋の操作ログに残す（管理画面のタイムラインで確認できる）
+func (h *RoomMessageHandler) logAutoMute(roomID uuid.UUID, user *models.User, duration time.Duration) {
+	displayName := user.DisplayName
+	if displayName == "" && user.Username != nil {
+		displayName = *user.Username
+	}
+	roomLog := &models.RoomLog{
+		RoomID: roomID,
+		UserID: &user.ID,
+		Action: "auto_mute",
+		Details: models.JSONB{
+			Data: map[string]interface{}{
+				"user_name":        displayName,
+				"duration_seconds": int(duration / time.Second),
+				"muted_until":      time.Now().Add(duration),
+			},
+		},
+	}
+	if err := h.repo.RoomLog.CreateLog(roomLog); err != nil {
+		log.Printf("自動ミュートのログ記録に失敗 room_id=%s user_id=%s: %v", roomID, user.ID, err)
+	}
+}
+
+// formatChatWait 待ち時間を「45秒」「2分」「2分30秒」の形式で返す
+func formatChatWait(seconds int) string {
+	switch {
+	case seconds < 60:
+		return fmt.Sprintf("%d秒", seconds)
+	case seconds%60 == 0:
+		return fmt.Sprintf("%d分", seconds/60)
+	default:
+		return fmt.Sprintf("%d分%d秒", seconds/60, seconds%60)
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+func testChatRateLimitConfig() ChatRateLimitConfig {
+	return ChatRateLimitConfig{PerMinute: 20, Burst: 3, StrikeLimit: 3, Mute: 2 * time.Minute}
+}
+
+func TestChatLimiterBurstAndRefill(t *testing.T) {
+	limiter := newChatLimiter(testChatRateLimitConfig())
+	roomID, userID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if result := limiter.check(roomID, userID, 0, now); !result.Allowed {
+			t.Fatalf("%d件目が断られた: %+v", i+1, result)
+		}
+	}
+	result := limiter.check(roomID, userID, 0, now)
+	if result.Allowed || result.Reason != chatLimitRateLimited || result.RetryAfter != 3*time.Second {
+		t.Fatalf("枠を使い切った後 = %+v, want RATE_LIMITED 3秒", result)
+	}
+
+	// 別の部屋・別のユーザーの枠には影響しない
+	if result := limiter.check(uuid.New(), userID, 0, now); !result.Allowed {
+		t.Errorf("別の部屋で断られた: %+v", result)
+	}
+	if result := limiter.check(roomID, uuid.New(), 0, now); !result.Allowed {
+		t.Errorf("別のユーザーが断られた: %+v", result)
+	}
+
+	// 3秒で1件分回復する
+	if result := limiter.check(roomID, userID, 0, now.Add(3*time.Second)); !result.Allowed {
+		t.Errorf("回復後に断られた: %+v", result)
+	}
+}
+
+func TestChatLimiterSlowMode(t *testing.T) {
+	limiter := newChatLimiter(testChatRateLimitConfig())
+	roomID, userID, hostID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	if result := limiter.check(roomID, userID, 10*time.Second, now); !result.Allowed {
+		t.Fatalf("最初の送信が断られた: %+v", result)
+	}
+	result := limiter.check(roomID, userID, 10*time.Second, now.Add(4*time.Second))
+	if result.Allowed || result.Reason != chatLimitSlowMode || result.RetryAfter != 6*time.Second {
+		t.Fatalf("間隔内の送信 = %+v, want SLOW_MODE 6秒", result)
+	}
+	if result := limiter.check(roomID, userID, 10*time.Second, now.Add(10*time.Second)); !result.Allowed {
+		t.Errorf("間隔が空いた後に断られた: %+v", result)
+	}
+
+	// ホストは低速モードの対象外（0 を渡す）
+	for i := 0; i < 2; i++ {
+		if result := limiter.check(roomID, hostID, 0, now.Add(time.Duration(i)*time.Second)); !result.Allowed {
+			t.Errorf("ホストが断られた: %+v", result)
+		}
+	}
+}
+
+func TestChatLimiterAutoMute(t *testing.T) {
+	limiter := newChatLimiter(testChatRateLimitConfig())
+	roomID, userID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	// 枠を使い切ってから制限を3回超えるとミュートされる
+	flood := func(at time.Time) chatLimitResult {
+		var result chatLimitResult
+		for i := 0; i < 6; i++ {
+			result = limiter.check(roomID, userID, 0, at)
+		}
+		return result
+	}
+
+	result := flood(now)
+	if !result.MutedNow || result.Reason != chatLimitMuted || result.RetryAfter != 2*time.Minute {
+		t.Fatalf("1回目のミュート = %+v, want 2分", result)
+	}
+
+	// ミュート中は送れず、ミュートも延びない
+	result = limiter.check(roomID, userID, 0, now.Add(time.Minute))
+	if result.Allowed || result.MutedNow || result.Reason != chatLimitMuted || result.RetryAfter != time.Minute {
+		t.Fatalf("ミュート中 = %+v, want MUTED 残り1分", result)
+	}
+
+	// 1時間以内に繰り返すとミュートが倍になる
+	result = flood(now.Add(3 * time.Minute))
+	if !result.MutedNow || result.RetryAfter != 4*time.Minute {
+		t.Fatalf("2回目のミュート = %+v, want 4分", result)
+	}
+
+	// 間が空けば最初の長さに戻る
+	result = flood(now.Add(3*time.Minute + chatOffenseMemory))
+	if !result.MutedNow || result.RetryAfter != 2*time.Minute {
+		t.Fatalf("時間が空いた後のミュート = %+v, want 2分", result)
+	}
+}
+
+func TestChatLimiterMuteCap(t *testing.T) {
+	config := testChatRateLimitConfig()
+	config.Mute = 20 * time.Minute
+	limiter := newChatLimiter(config)
+	roomID, userID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	var result chatLimitResult
+	for round := 0; round < 2; round++ {
+		at := now.Add(time.Duration(round) * 21 * time.Minute)
+		for i := 0; i < 6; i++ {
+			result = limiter.check(roomID, userID, 0, at)
+		}
+	}
+	if !result.MutedNow || result.RetryAfter != chatMaxMute {
+		t.Errorf("ミュートの長さ = %+v, want 上限の %v", result, chatMaxMute)
+	}
+}
+
+func TestNewChatLimitResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      chatLimitResult
+		wantMessage string
+		wantRetry   int
+	}{
+		{
+			name:        "送りすぎ（秒は切り上げ）",
+			result:      chatLimitResult{Reason: chatLimitRateLimited, RetryAfter: 2100 * time.Millisecond},
+			wantMessage: "送信が早すぎます。あと3秒で送信できます",
+			wantRetry:   3,
+		},
+		{
+			name:        "低速モード",
+			result:      chatLimitResult{Reason: chatLimitSlowMode, RetryAfter: 45 * time.Second},
+			wantMessage: "低速モード中です。あと45秒で送信できます",
+			wantRetry:   45,
+		},
+		{
+			name:        "ミュートされた",
+			result:      chatLimitResult{Reason: chatLimitMuted, RetryAfter: 2 * time.Minute, MutedNow: true},
+			wantMessage: "短時間に送信を繰り返したため、2分間チャットを送信できません",
+			wantRetry:   120,
+		},
+		{
+			name:        "ミュート中",
+			result:      chatLimitResult{Reason: chatLimitMuted, RetryAfter: 90 * time.Second},
+			wantMessage: "一時的にチャットを送信できません。あと1分30秒お待ちください",
+			wantRetry:   90,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newChatLimitResponse(tt.result)
+			if got.Error != tt.result.Reason || got.Message != tt.wantMessage || got.RetryAfter != tt.wantRetry {
+				t.Errorf("newChatLimitResponse() = %+v, want message %q retry %d", got, tt.wantMessage, tt.wantRetry)
+			}
+		})
+	}
+}
+
+// newChatLimitTestHandler 1分に1件までしか送れない RoomMessageHandler を作る
+func newChatLimitTestHandler(room *models.Room, message *models.RoomMessage, members ...uuid.UUID) *RoomMessageHandler {
+	h := newTestRoomMessageHandler(room, message, members...)
+	h.chatLimits = newChatLimiter(ChatRateLimitConfig{PerMinute: 1, Burst: 1, StrikeLimit: 3, Mute: 2 * time.Minute})
+	return h
+}
+
+func TestSendMessageInvalidInputDoesNotSpendLimit(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, HostUserID: uuid.New(), IsActive: true, SlowModeSeconds: 30}
+	h := newChatLimitTestHandler(room, &models.RoomMessage{}, user.ID)
+
+	// 空のメッセージ・存在しない返信先で断られた送信は、何度送っても枠を使わず制限超過にも数えない
+	for _, form := range []url.Values{
+		{"message": {"   "}},
+		{"message": {"よろしく"}, "reply_to_id": {"invalid"}},
+		{"message": {"   "}},
+		{"message": {"   "}},
+	} {
+		r := newTestFormRequest("POST", "/rooms/"+room.ID.String()+"/messages", form, map[string]string{"id": room.ID.String()}, user)
+		w := httptest.NewRecorder()
+		h.SendMessage(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	}
+
+	if result := h.chatLimits.check(room.ID, user.ID, room.SlowModeInterval(), time.Now()); !result.Allowed {
+		t.Errorf("入力の誤りで送信枠が使われている: %+v", result)
+	}
+}
+
+func TestEditMessageRateLimited(t *testing.T) {
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, HostUserID: uuid.New(), IsActive: true}
+	message := &models.RoomMessage{
+		BaseModel:   models.BaseModel{ID: uuid.New()},
+		RoomID:      room.ID,
+		UserID:      user.ID,
+		Message:     "よろしく",
+		MessageType: models.RoomMessageTypeChat,
+	}
+	h := newChatLimitTestHandler(room, message, user.ID)
+
+	edit := func(text string) *httptest.ResponseRecorder {
+		params := map[string]string{"id": room.ID.String(), "messageID": message.ID.String()}
+		r := newTestFormRequest("PUT", "/rooms/"+room.ID.String()+"/messages/"+message.ID.String(), url.Values{"message": {text}}, params, user)
+		w := httptest.NewRecorder()
+		h.EditMessage(w, r)
+		return w
+	}
+
+	if w := edit("よろしくお願いします"); w.Code != http.StatusOK {
+		t.Fatalf("1回目の編集: status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	w := edit("よろしくお願いします！")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("続けての編集: status = %d, Retry-After = %q, want 429", w.Code, w.Header().Get("Retry-After"))
+	}
+	if message.Message != "よろしくお願いします" {
+		t.Errorf("制限された編集が保存されている: %q", message.Message)
+	}
+
+	// ミュート中は編集もできない
+	h.chatLimits = newChatLimiter(testChatRateLimitConfig())
+	now := time.Now()
+	for i := 0; i < 6; i++ {
+		h.chatLimits.check(room.ID, user.ID, 0, now)
+	}
+	w = edit("ミュート中の編集")
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), chatLimitMuted) {
+		t.Errorf("ミュート中の編集: status = %d, body = %s, want 429 MUTED", w.Code, w.Body.String())
+	}
+}
+
+func TestChatLimiterRefund(t *testing.T) {
+	limiter := newChatLimiter(ChatRateLimitConfig{PerMinute: 1, Burst: 1, StrikeLimit: 3, Mute: time.Minute})
+	roomID, userID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+
+	if result := limiter.check(roomID, userID, time.Minute, now); !result.Allowed {
+		t.Fatalf("最初の送信が断られた: %+v", result)
+	}
+	// 保存に失敗した送信の枠と低速モードの間隔を戻す
+	limiter.refund(roomID, userID, true)
+	if result := limiter.check(roomID, userID, time.Minute, now.Add(time.Second)); !result.Allowed {
+		t.Errorf("戻した後に断られた: %+v", result)
+	}
+
+	// 編集は低速モードの間隔に数えない
+	other := uuid.New()
+	limiter = newChatLimiter(testChatRateLimitConfig())
+	if result := limiter.checkEdit(roomID, other, now); !result.Allowed {
+		t.Fatalf("編集が断られた: %+v", result)
+	}
+	if result := limiter.check(roomID, other, time.Minute, now); !result.Allowed {
+		t.Errorf("編集の直後の送信が低速モードで断られた: %+v", result)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// roomCodeLength utils.GenerateRoomCode が生成する部屋コードの長さ
+const roomCodeLength = 6
+
+// normalizeRoomCode 入力された部屋コードを照会用に整える。
+// 通話で読み上げたコードを打ち込むため、小文字・全角英数字・空白・ハイフンは許容する。形式が正しくなければ空文字を返す
+func normalizeRoomCode(input string) string {
+	var b strings.Builder
+	for _, r := range input {
+		// 全角英数字は半角に寄せる
+		if r >= '０' && r <= 'ｚ' {
+			r -= '０' - '0'
+		}
+		switch {
+		case unicode.IsSpace(r) || r == '-' || r == 'ー':
+			continue
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			return ""
+		}
+	}
+	if b.Len() != roomCodeLength {
+		return ""
+	}
+	return b.String()
+}
+
+// RoomCodePageData ルームコード入力ページのデータ
+type RoomCodePageData struct {
+	Code string `json:"code"` // ?code= で渡されたコード（入力欄の初期値）
+}
+
+// RoomCodePage ルームコードを入力して部屋を探すページ
+func (h *RoomJoinHandler) RoomCodePage(w http.ResponseWriter, r *http.Request) {
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		redirectURL := "/auth/login?redirect=" + url.QueryEscape(r.URL.RequestURI())
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+		return
+	}
+
+	data := TemplateData{
+		Title:   "ルームコードで参加",
+		HasHero: false,
+		User:    r.Context().Value("user"),
+		PageData: RoomCodePageData{
+			Code: strings.TrimSpace(r.URL.Query().Get("code")),
+		},
+	}
+	renderTemplate(w, r, "room_code.tmpl", data)
+}
+
+// RoomCodeRequest ルームコードの照会リクエスト
+type RoomCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// ResolveRoomCode ルームコードから部屋を探し、参加ページへの行き先を返す。
+// パスワード・承認制の確認は参加ページで行い、キック・BAN・ブロック・公開範囲で参加できない部屋はここで断る
+func (h *RoomHandler) ResolveRoomCode(w http.ResponseWriter, r *http.Request) {
+	var req RoomCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	code := normalizeRoomCode(req.Code)
+	if code == "" {
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "INVALID_CODE",
+			"message": "ルームコードは英数字6文字で入力してください",
+		})
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	// 存在しないコードと解散済みの部屋は区別しない
+	room, err := h.repo.Room.FindRoomByRoomCode(code)
+	if err != nil || !room.IsActive {
+		respondWithJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error":   "ROOM_NOT_FOUND",
+			"message": "このルームコードの部屋は見つかりませんでした",
+		})
+		return
+	}
+
+	// 参加中の部屋はそのまま部屋詳細へ
+	if room.HostUserID == dbUser.ID || h.repo.Room.IsUserJoinedRoom(room.ID, dbUser.ID) {
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"room":     h.roomCodeSummary(room),
+			"joined":   true,
+			"redirect": "/rooms/" + room.ID.String(),
+		})
+		return
+	}
+
+	if h.repo.Room.IsUserKickedFromRoom(room.ID, dbUser.ID) {
+		respondWithJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error":   "KICKED",
+			"message": "この部屋から退出させられたため、現在は参加できません",
+		})
+		return
+	}
+
+	banned, err := h.repo.HostBan.IsBanned(room.HostUserID, dbUser.ID)
+	if err != nil {
+		http.Error(w, "参加制限の確認に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if banned {
+		respondWithJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error":   "HOST_BANNED",
+			"message": "このホストの部屋には参加できません",
+		})
+		return
+	}
+
+	if !h.canViewRoom(room, dbUser) {
+		respondWithJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error":   "FOLLOWERS_ONLY",
+			"message": "この部屋はホストのフォロワーのみ参加できます",
+		})
+		return
+	}
+
+	blockResponse, err := h.joinBlockResponse(dbUser.ID, room)
+	if err != nil {
+		http.Error(w, "ブロック関係の確認に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if blockResponse != nil {
+		respondWithJSON(w, http.StatusForbidden, blockResponse)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"room":     h.roomCodeSummary(room),
+		"joined":   false,
+		"redirect": "/rooms/" + room.ID.String() + "/join",
+	})
+}
+
+// roomCodeSummary 参加前の確認用に表示する部屋の概要
+func (h *RoomHandler) roomCodeSummary(room *models.Room) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                room.ID,
+		"name":              room.Name,
+		"room_code":         room.RoomCode,
+		"game_version":      room.GameVersion.Name,
+		"host_name":         h.getDisplayName(&room.Host),
+		"current_players":   room.CurrentPlayers,
+		"max_players":       room.MaxPlayers,
+		"is_closed":         room.IsClosed,
+		"has_password":      room.HasPassword(),
+		"requires_approval": room.RequiresApproval,
+	}
+}
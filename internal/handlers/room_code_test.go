@@ -0,0 +1,28 @@
+package handlers
+
+import "testing"
+
+func TestNormalizeRoomCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"そのまま", "ABC123", "ABC123"},
+		{"小文字", "abc123", "ABC123"},
+		{"空白・ハイフン区切り", " abc-123 ", "ABC123"},
+		{"全角英数字と長音", "ＡＢＣー１２３", "ABC123"},
+		{"短い", "ABC12", ""},
+		{"長い", "ABC1234", ""},
+		{"記号", "ABC12!", ""},
+		{"空", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeRoomCode(tt.input); got != tt.want {
+				t.Errorf("normalizeRoomCode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"html/template"
+	"log"
 	"net/http"
 	"path/filepath"
 
@@ -28,12 +29,16 @@ func NewRoomDetailHandler(repo *repository.Repository) *RoomDetailHandler {
 }
 
 type RoomDetailPageData struct {
-	Room        *models.Room         `json:"room"`
-	Members     []*models.RoomMember `json:"members"`
-	Logs        []models.RoomLog     `json:"logs"`
-	MemberCount int                  `json:"member_count"`
-	IsHost      bool                 `json:"is_host"`
-	OGImageURL  string               `json:"og_image_url"`
+	Room               *models.Room         `json:"room"`
+	Members            []*models.RoomMember `json:"members"`
+	Logs               []models.RoomLog     `json:"logs"`
+	MemberCount        int                  `json:"member_count"`
+	IsHost             bool                 `json:"is_host"`
+	IsMember           bool                 `json:"is_member"`
+	Waitlist           RoomWaitlistStatus   `json:"waitlist"`             // メンバー以外のユーザーのキャンセル待ちの状況
+	JoinRequestPending bool                 `json:"join_request_pending"` // 承認制の部屋で自分の参加申請が審査待ちか
+	OGImageURL         string               `json:"og_image_url"`
+	LastReadMessageID  *uuid.UUID           `json:"last_read_message_id"` // 前回までに読んだメッセージ（新着メッセージの区切りの表示用）
 }
 
 func (h *RoomDetailHandler) RoomDetail(w http.ResponseWriter, r *http.Request) {
@@ -59,6 +64,13 @@ func (h *RoomDetailHandler) RoomDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// フォロワー限定の部屋はホストのフォロワーにのみ表示する
+	viewer, _ := middleware.GetDBUserFromContext(r.Context())
+	if !h.canViewRoom(room, viewer) {
+		http.Error(w, "この部屋はホストのフォロワーのみ閲覧できます", http.StatusForbidden)
+		return
+	}
+
 	// プリロードで取得できなかった場合は異常と判断
 	if room.Host.ID == uuid.Nil {
 		http.Error(w, "ホスト情報の取得に失敗しました", http.StatusInternalServerError)
@@ -103,10 +115,42 @@ func (h *RoomDetailHandler) RoomDetail(w http.ResponseWriter, r *http.Request) {
 		logs = []models.RoomLog{}
 	}
 
-	// 現在のユーザーがホストかどうかを判定
-	isHost := false
+	// 現在のユーザーがホストかどうか、メンバーでなければキャンセル待ちの状況を判定
+	isHost, isMember := false, false
+	viewerID := uuid.Nil
 	if dbUser, exists := middleware.GetDBUserFromContext(r.Context()); exists && dbUser != nil {
 		isHost = dbUser.ID == room.HostUserID
+		viewerID = dbUser.ID
+		for i := range members {
+			if members[i].UserID == dbUser.ID {
+				isMember = true
+				break
+			}
+		}
+	}
+	waitlist := RoomWaitlistStatus{Status: "none"}
+	if !isMember {
+		waitlist = loadRoomWaitlistStatus(h.repo, room, viewerID)
+	}
+	var joinRequestPending bool
+	if !isMember && !isHost && viewerID != uuid.Nil && room.RequiresApproval {
+		request, err := h.repo.JoinRequest.FindPendingRequest(roomID, viewerID)
+		if err != nil {
+			log.Printf("参加申請の取得に失敗: %v", err)
+		}
+		joinRequestPending = request != nil
+	}
+
+	// メッセージ一覧の取得で既読位置が進む前に、前回どこまで読んだかを控えておく
+	var lastReadMessageID *uuid.UUID
+	if (isMember || isHost) && viewerID != uuid.Nil {
+		marker, err := h.repo.RoomMessage.GetReadMarker(roomID, viewerID)
+		if err != nil {
+			log.Printf("既読位置の取得に失敗: %v", err)
+		}
+		if marker != nil {
+			lastReadMessageID = &marker.LastReadMessageID
+		}
 	}
 
 	ogImageURL := BuildOGPImageURL(room.ID, room.OGVersion)
@@ -118,12 +162,16 @@ func (h *RoomDetailHandler) RoomDetail(w http.ResponseWriter, r *http.Request) {
 		User:    r.Context().Value("user"),
 		SSEHost: config.AppConfig.Server.SSEHost, // SSEサーバーのホスト
 		PageData: RoomDetailPageData{
-			Room:        room,
-			Members:     memberSlots,
-			Logs:        logs,
-			MemberCount: memberCount,
-			IsHost:      isHost,
-			OGImageURL:  ogImageURL,
+			Room:               room,
+			Members:            memberSlots,
+			Logs:               logs,
+			MemberCount:        memberCount,
+			IsHost:             isHost,
+			IsMember:           isMember || isHost,
+			Waitlist:           waitlist,
+			JoinRequestPending: joinRequestPending,
+			OGImageURL:         ogImageURL,
+			LastReadMessageID:  lastReadMessageID,
 		},
 	}
 
@@ -144,6 +192,12 @@ func renderRoomDetailTemplate(w http.ResponseWriter, r *http.Request, templateNa
 		filepath.Join("templates", "components", "share_modal.tmpl"),
 		filepath.Join("templates", "components", "kick_modal.tmpl"),
 		filepath.Join("templates", "components", "report_modal.tmpl"),
+		filepath.Join("templates", "components", "room_waitlist_panel.tmpl"),
+		filepath.Join("templates", "components", "room_invite_modal.tmpl"),
+		filepath.Join("templates", "components", "hunt_record_modal.tmpl"),
+		filepath.Join("templates", "components", "room_ready_check_panel.tmpl"),
+		filepath.Join("templates", "components", "room_join_request_panel.tmpl"),
+		filepath.Join("templates", "components", "room_loadout_panel.tmpl"),
 	)
 	if err != nil {
 		http.Error(w, "Template parsing error: "+err.Error(), http.StatusInternalServerError)
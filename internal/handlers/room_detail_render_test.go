@@ -27,9 +27,11 @@ func sampleRoomDetailData() TemplateData {
 		GameVersion: models.GameVersion{Code: "MHP2G", Name: "モンスターハンターポータブル 2nd G"},
 		Host:        host,
 	}
+	room.SetWantedWeapons([]string{models.WeaponHuntingHorn, models.WeaponBow})
+	weaponType, hunterRank := models.WeaponBow, 120
 	members := []*models.RoomMember{
 		{ID: uuid.New(), RoomID: room.ID, UserID: host.ID, PlayerNumber: 1, IsHost: true, Status: models.MemberStatusActive, User: host, DisplayName: host.DisplayName},
-		{ID: uuid.New(), RoomID: room.ID, UserID: guest.ID, PlayerNumber: 2, Status: models.MemberStatusActive, User: guest, DisplayName: guest.DisplayName},
+		{ID: uuid.New(), RoomID: room.ID, UserID: guest.ID, PlayerNumber: 2, Status: models.MemberStatusActive, User: guest, DisplayName: guest.DisplayName, WeaponType: &weaponType, HunterRank: &hunterRank},
 	}
 	return TemplateData{
 		Title: "テスト部屋",
@@ -59,8 +61,19 @@ func TestRenderRoomDetailWithKickUI(t *testing.T) {
 	for _, want := range []string{
 		`@click="toggleMemberMenu(index)"`,
 		`@click="openKickModal(member)"`,
+		`@click="transferHost(member)"`,
 		`@click="openReportForMember(member)"`,
 		`x-show="showKickModal"`,
+		`x-show="showInviteModal"`,
+		`@click="openInviteModal()"`,
+		`@click="startReadyCheck()"`,
+		`@click="setReady(true)"`,
+		`decideJoinRequest(request.id, 'approve')`,
+		`@click="cancelMyJoinRequest()"`,
+		`@click="saveLoadout()"`,
+		`wantedWeapons: ["hunting_horn","bow"]`,
+		`weapon_type: "bow"`,
+		`hunter_rank:  120 `,
 		`id="reportModal"`,
 		`/kick`,
 	} {
@@ -93,6 +106,36 @@ func TestRenderRoomDetailWithKickUI(t *testing.T) {
 	}
 }
 
+// TestRenderRoomDetailWaitlist メンバー以外の閲覧者にキャンセル待ちの待ち順が表示され、
+// 待ち状況がスクリプトに JSON として埋め込まれることを確認する
+func TestRenderRoomDetailWaitlist(t *testing.T) {
+	chdirRepoRoot(t)
+
+	data := sampleRoomDetailData()
+	pageData := data.PageData.(RoomDetailPageData)
+	pageData.IsHost = false
+	pageData.Waitlist = RoomWaitlistStatus{Status: "waiting", Position: 2, WaitingCount: 3, Full: true}
+	data.PageData = pageData
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/rooms/test", nil)
+	renderRoomDetailTemplate(w, r, "room_detail.tmpl", data)
+
+	body := w.Body.String()
+	if w.Code != 200 || strings.Contains(body, "Template parsing error") || strings.Contains(body, "Template execution error") {
+		t.Fatalf("status = %d, body:\n%s", w.Code, truncate(body, 2000))
+	}
+	for _, want := range []string{
+		`"status":"waiting","position":2`,
+		`キャンセル待ちに登録`,
+		`@click="leaveWaitlist()"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("描画結果に %q が含まれていない", want)
+		}
+	}
+}
+
 func TestRoomJoinKickedErrorCode(t *testing.T) {
 	// リポジトリの KICKED エラーはハンドラーで 403 + error コードに変換される前提。プレフィックスの取り決めを固定する
 	err := "KICKED:この部屋から退出させられたため、再度参加することはできません"
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// TransferHost ホストが参加中のメンバーを指名してホストを譲る
+func (h *RoomHandler) TransferHost(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	targetUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		http.Error(w, "無効なユーザーIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	if room.HostUserID != dbUser.ID {
+		http.Error(w, "部屋のホストのみがホストを譲れます", http.StatusForbidden)
+		return
+	}
+
+	if err := h.repo.Room.TransferHost(roomID, dbUser.ID, targetUserID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	newHost := h.broadcastHostChange(roomID, dbUser, models.HostChangeReasonTransfer)
+
+	response := map[string]interface{}{
+		"message": "ホストを譲りました",
+	}
+	if newHost != nil {
+		response["message"] = fmt.Sprintf("%sさんにホストを譲りました", h.getDisplayName(newHost))
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// broadcastHostChange ホストの交代をシステムメッセージと room_update / member_update イベントで部屋に知らせ、新しいホストを返す。
+// actor はシステムメッセージの送信者（譲った・退出した元のホスト）
+func (h *RoomHandler) broadcastHostChange(roomID uuid.UUID, actor *models.User, reason string) *models.User {
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		log.Printf("部屋情報の取得に失敗: %v", err)
+		return nil
+	}
+	newHost := &room.Host
+	newHostName := h.getDisplayName(newHost)
+
+	text := fmt.Sprintf("%sさんがホストになりました", newHostName)
+	if reason == models.HostChangeReasonSuccession {
+		text = fmt.Sprintf("ホストの退出により、%sさんがホストを引き継ぎました", newHostName)
+	}
+	h.broadcastSystemMessage(h.createSystemMessage(roomID, actor, text))
+
+	if h.hub == nil {
+		return newHost
+	}
+
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "room_update",
+		Data: map[string]interface{}{
+			"action":                "host_change",
+			"reason":                reason,
+			"host_user_id":          newHost.ID,
+			"host_supabase_user_id": newHost.SupabaseUserID,
+			"host_display_name":     newHostName,
+		},
+	})
+
+	members, err := h.repo.Room.GetRoomMembers(roomID)
+	if err != nil {
+		log.Printf("メンバー情報取得エラー: %v", err)
+		members = []models.RoomMember{}
+	}
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "member_update",
+		Data: map[string]interface{}{
+			"action":  "host_change",
+			"members": members,
+			"count":   len(members),
+		},
+	})
+
+	return newHost
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/utils"
+)
+
+// 招待リンクに設定できる有効期限・使用回数の上限
+const (
+	maxInviteExpiresInHours = 24 * 30
+	maxInviteUses           = 100
+)
+
+// RoomInviteResponse ホスト向けに返す招待リンク
+type RoomInviteResponse struct {
+	models.RoomInvite
+	URL    string `json:"url"`    // 参加ページのパス（/rooms/{id}/join?invite=...）
+	Usable bool   `json:"usable"` // 現在このリンクから参加できるか
+}
+
+func newRoomInviteResponse(invite models.RoomInvite, now time.Time) RoomInviteResponse {
+	return RoomInviteResponse{
+		RoomInvite: invite,
+		URL:        "/rooms/" + invite.RoomID.String() + "/join?invite=" + invite.Token,
+		Usable:     invite.IsUsable(now),
+	}
+}
+
+type CreateRoomInviteRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"` // 0 は無期限
+	MaxUses        int `json:"max_uses"`         // 0 は回数無制限
+}
+
+// loadHostRoom 部屋を取得し、ログイン中のユーザーがホストであることを確認する。失敗時はレスポンスを書き込んで nil を返す
+func (h *RoomHandler) loadHostRoom(w http.ResponseWriter, r *http.Request, roomID uuid.UUID) (*models.Room, *models.User) {
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return nil, nil
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return nil, nil
+	}
+	if room.HostUserID != dbUser.ID {
+		http.Error(w, "この操作は部屋のホストのみが行えます", http.StatusForbidden)
+		return nil, nil
+	}
+	return room, dbUser
+}
+
+// GetRoomInvites ホストが発行した招待リンクの一覧を返す
+func (h *RoomHandler) GetRoomInvites(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	room, _ := h.loadHostRoom(w, r, roomID)
+	if room == nil {
+		return
+	}
+
+	invites, err := h.repo.RoomInvite.GetInvitesByRoom(roomID)
+	if err != nil {
+		log.Printf("招待リンクの取得に失敗: %v", err)
+		http.Error(w, "招待リンクの取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	responses := make([]RoomInviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		responses = append(responses, newRoomInviteResponse(invite, now))
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"invites": responses,
+	})
+}
+
+// CreateRoomInvite 有効期限・使用回数の上限付きの招待リンクを発行する
+func (h *RoomHandler) CreateRoomInvite(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateRoomInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	if req.ExpiresInHours < 0 || req.ExpiresInHours > maxInviteExpiresInHours {
+		http.Error(w, "有効期限は30日以内で指定してください", http.StatusBadRequest)
+		return
+	}
+	if req.MaxUses < 0 || req.MaxUses > maxInviteUses {
+		http.Error(w, "使用回数の上限は100回以内で指定してください", http.StatusBadRequest)
+		return
+	}
+
+	room, dbUser := h.loadHostRoom(w, r, roomID)
+	if room == nil {
+		return
+	}
+	if !room.IsActive {
+		http.Error(w, "この部屋は利用できません", http.StatusBadRequest)
+		return
+	}
+
+	token, err := utils.GenerateInviteToken()
+	if err != nil {
+		log.Printf("招待トークンの生成に失敗: %v", err)
+		http.Error(w, "招待リンクの作成に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	invite := models.RoomInvite{
+		RoomID:          roomID,
+		Token:           token,
+		CreatedByUserID: dbUser.ID,
+		MaxUses:         req.MaxUses,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	if err := h.repo.RoomInvite.CreateInvite(&invite); err != nil {
+		log.Printf("招待リンクの作成に失敗: %v", err)
+		http.Error(w, "招待リンクの作成に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "招待リンクを作成しました",
+		"invite":  newRoomInviteResponse(invite, now),
+	})
+}
+
+// RevokeRoomInvite 招待リンクを無効にする
+func (h *RoomHandler) RevokeRoomInvite(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+	inviteID, err := uuid.Parse(chi.URLParam(r, "inviteID"))
+	if err != nil {
+		http.Error(w, "無効な招待リンクIDです", http.StatusBadRequest)
+		return
+	}
+
+	room, dbUser := h.loadHostRoom(w, r, roomID)
+	if room == nil {
+		return
+	}
+
+	if err := h.repo.RoomInvite.RevokeInvite(roomID, inviteID, dbUser.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "招待リンクを無効にしました",
+	})
+}
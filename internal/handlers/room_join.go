@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"mhp-rooms/internal/middleware"
 	"mhp-rooms/internal/models"
@@ -25,12 +28,17 @@ func NewRoomJoinHandler(repo *repository.Repository) *RoomJoinHandler {
 }
 
 type RoomJoinPageData struct {
-	Room          *RoomBasicInfo `json:"room"`
-	IsJoined      bool           `json:"is_joined"`
-	IsHost        bool           `json:"is_host"`
-	HasPassword   bool           `json:"has_password"`
-	OGImageURL    string         `json:"og_image_url"`
-	IsLimitedView bool           `json:"is_limited_view"`
+	Room               *RoomBasicInfo `json:"room"`
+	IsJoined           bool           `json:"is_joined"`
+	IsHost             bool           `json:"is_host"`
+	HasPassword        bool           `json:"has_password"`
+	OGImageURL         string         `json:"og_image_url"`
+	IsLimitedView      bool           `json:"is_limited_view"`
+	IsListed           bool           `json:"is_listed"`            // 部屋一覧に掲載する公開の部屋か（それ以外は検索エンジンに載せない）
+	InviteToken        string         `json:"invite_token"`         // 有効な招待リンクから開いた場合のトークン
+	InviteError        string         `json:"invite_error"`         // 招待リンクが使えない理由
+	RequiresApproval   bool           `json:"requires_approval"`    // 承認制の部屋か（参加ボタンで参加申請を送る）
+	JoinRequestPending bool           `json:"join_request_pending"` // 自分の参加申請が審査待ちか
 }
 
 type RoomBasicInfo struct {
@@ -82,7 +90,8 @@ func (h *RoomJoinHandler) RoomJoinPage(w http.ResponseWriter, r *http.Request) {
 
 	// 通常のユーザー（クローラーでない）かつ未認証の場合はログインへリダイレクト
 	if !isBot && !isAuthenticated {
-		redirectURL := "/auth/login?redirect=" + r.URL.Path
+		// 招待リンクのトークンを失わないよう、クエリごとログイン後の戻り先にする
+		redirectURL := "/auth/login?redirect=" + url.QueryEscape(r.URL.RequestURI())
 		http.Redirect(w, r, redirectURL, http.StatusFound)
 		return
 	}
@@ -115,6 +124,40 @@ func (h *RoomJoinHandler) RoomJoinPage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// 招待リンクから開いた場合は、使えるリンクならパスワード入力を省略する
+	var inviteToken, inviteError string
+	token := r.URL.Query().Get("invite")
+	if token != "" && isAuthenticated {
+		invite, err := h.repo.RoomInvite.FindInviteByToken(roomID, token)
+		switch {
+		case err != nil:
+			log.Printf("招待リンクの取得に失敗: %v", err)
+			inviteError = "招待リンクを確認できませんでした"
+		case invite == nil:
+			inviteError = "招待リンクが見つかりません"
+		case !invite.IsUsable(time.Now()):
+			inviteError = "この招待リンクは期限切れか、使用できなくなっています"
+		default:
+			inviteToken = token
+		}
+	}
+
+	// フォロワー限定の部屋は、フォロワーか有効な招待リンクを持つユーザーのみ参加ページを開ける
+	if isAuthenticated && inviteToken == "" && !h.canViewRoom(room, dbUser) {
+		http.Error(w, "この部屋はホストのフォロワーのみ参加できます", http.StatusForbidden)
+		return
+	}
+
+	// 承認制の部屋は、審査待ちの申請があれば再申請ではなく審査待ちであることを表示する
+	var joinRequestPending bool
+	if isAuthenticated && room.RequiresApproval {
+		request, err := h.repo.JoinRequest.FindPendingRequest(roomID, dbUser.ID)
+		if err != nil {
+			log.Printf("参加申請の取得に失敗: %v", err)
+		}
+		joinRequestPending = request != nil
+	}
+
 	basicInfo := &RoomBasicInfo{
 		ID:          room.ID,
 		Name:        room.Name,
@@ -133,12 +176,17 @@ func (h *RoomJoinHandler) RoomJoinPage(w http.ResponseWriter, r *http.Request) {
 		HasHero: false,
 		User:    r.Context().Value("user"),
 		PageData: RoomJoinPageData{
-			Room:          basicInfo,
-			IsJoined:      isJoined,
-			IsHost:        isHost,
-			HasPassword:   room.HasPassword(),
-			OGImageURL:    ogImageURL,
-			IsLimitedView: isLimitedView,
+			Room:               basicInfo,
+			IsJoined:           isJoined,
+			IsHost:             isHost,
+			HasPassword:        room.HasPassword(),
+			OGImageURL:         ogImageURL,
+			IsLimitedView:      isLimitedView,
+			IsListed:           room.IsListed(),
+			InviteToken:        inviteToken,
+			InviteError:        inviteError,
+			RequiresApproval:   room.RequiresApproval,
+			JoinRequestPending: joinRequestPending,
 		},
 	}
 
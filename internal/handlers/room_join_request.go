@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// GetJoinRequests 承認制の部屋の審査待ちの参加申請を返す（ホストのみ）
+func (h *RoomHandler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	room, _ := h.loadHostRoom(w, r, roomID)
+	if room == nil {
+		return
+	}
+
+	requests, err := h.repo.JoinRequest.GetPendingRequests(roomID)
+	if err != nil {
+		log.Printf("参加申請の取得に失敗: %v", err)
+		http.Error(w, "参加申請の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"requests": requests,
+		"count":    len(requests),
+	})
+}
+
+// ApproveJoinRequest ホストが参加申請を承認し、申請者の参加を完了させる
+func (h *RoomHandler) ApproveJoinRequest(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+	requestID, err := uuid.Parse(chi.URLParam(r, "requestID"))
+	if err != nil {
+		http.Error(w, "無効な参加申請IDです", http.StatusBadRequest)
+		return
+	}
+
+	room, _ := h.loadHostRoom(w, r, roomID)
+	if room == nil {
+		return
+	}
+
+	request, err := h.repo.Room.ApproveJoinRequest(roomID, requestID)
+	if err != nil {
+		for code, status := range map[string]int{
+			"JOIN_REQUEST_INVALID": http.StatusConflict,
+			"ROOM_FULL":            http.StatusConflict,
+			"ALREADY_JOINED":       http.StatusConflict,
+			"OTHER_ROOM_ACTIVE":    http.StatusConflict,
+			"KICKED":               http.StatusForbidden,
+			"HOST_BANNED":          http.StatusForbidden,
+		} {
+			if strings.HasPrefix(err.Error(), code+":") {
+				message := strings.TrimPrefix(err.Error(), code+":")
+				switch code {
+				case "OTHER_ROOM_ACTIVE":
+					message = "申請者は既に別の部屋に参加しています"
+				case "HOST_BANNED":
+					message = "申請者はBANリストに登録されています"
+				}
+				respondWithJSON(w, status, map[string]interface{}{
+					"error":   code,
+					"message": message,
+				})
+				return
+			}
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	applicant, err := h.repo.User.FindUserByID(request.UserID)
+	if err != nil {
+		log.Printf("申請者の取得に失敗: %v", err)
+	} else {
+		h.announceJoin(room, applicant)
+	}
+
+	// お知らせは失敗しても承認処理には影響させない
+	if err := h.notificationService.NotifyJoinRequestApproved(request.UserID, room); err != nil {
+		log.Printf("参加承認のお知らせ作成に失敗: %v", err)
+	}
+	h.sendJoinRequestDecision(roomID, request.UserID, "join_request_approved", "参加申請が承認されました")
+	h.broadcastJoinRequests(room)
+
+	message := "参加申請を承認しました"
+	if applicant != nil {
+		message = fmt.Sprintf("%sさんの参加を承認しました", h.getDisplayName(applicant))
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+	})
+}
+
+// RejectJoinRequest ホストが参加申請を見送る
+func (h *RoomHandler) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+	requestID, err := uuid.Parse(chi.URLParam(r, "requestID"))
+	if err != nil {
+		http.Error(w, "無効な参加申請IDです", http.StatusBadRequest)
+		return
+	}
+
+	room, host := h.loadHostRoom(w, r, roomID)
+	if room == nil {
+		return
+	}
+
+	request, err := h.repo.JoinRequest.RejectJoinRequest(roomID, requestID, host.ID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "JOIN_REQUEST_INVALID:") {
+			respondWithJSON(w, http.StatusConflict, map[string]interface{}{
+				"error":   "JOIN_REQUEST_INVALID",
+				"message": strings.TrimPrefix(err.Error(), "JOIN_REQUEST_INVALID:"),
+			})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notificationService.NotifyJoinRequestRejected(request.UserID, room); err != nil {
+		log.Printf("参加見送りのお知らせ作成に失敗: %v", err)
+	}
+	h.sendJoinRequestDecision(roomID, request.UserID, "join_request_rejected", "参加申請は見送られました")
+	h.broadcastJoinRequests(room)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "参加申請を見送りました",
+	})
+}
+
+// CancelJoinRequest 申請者が自分の参加申請を取り下げる
+func (h *RoomHandler) CancelJoinRequest(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.repo.JoinRequest.CancelJoinRequest(roomID, dbUser.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if room, err := h.repo.Room.FindRoomByID(roomID); err == nil {
+		h.broadcastJoinRequests(room)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "参加申請を取り下げました",
+	})
+}
+
+// broadcastJoinRequests 審査待ちの参加申請の一覧をホストに送る
+func (h *RoomHandler) broadcastJoinRequests(room *models.Room) {
+	if h.hub == nil {
+		return
+	}
+
+	requests, err := h.repo.JoinRequest.GetPendingRequests(room.ID)
+	if err != nil {
+		log.Printf("参加申請の取得に失敗: %v", err)
+		return
+	}
+	h.hub.SendToUser(room.ID, room.HostUserID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "join_request_update",
+		Data: map[string]interface{}{
+			"requests": requests,
+			"count":    len(requests),
+		},
+	})
+}
+
+// sendJoinRequestDecision 審査の結果を、部屋詳細ページで承認を待っている申請者に送る
+func (h *RoomHandler) sendJoinRequestDecision(roomID, userID uuid.UUID, eventType, message string) {
+	if h.hub == nil {
+		return
+	}
+	h.hub.SendToUser(roomID, userID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: eventType,
+		Data: map[string]interface{}{
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// MemberLoadoutRequest 参加時・参加中に申告する装備。空の項目は未申告として扱う
+type MemberLoadoutRequest struct {
+	WeaponType string `json:"weapon_type"` // models.WeaponTypes のコード
+	HunterRank int    `json:"hunter_rank"` // 0 は未申告
+}
+
+// loadout 申告内容を検証し、room_members に保存する値（未申告は nil）に変換する
+func (req MemberLoadoutRequest) loadout() (*string, *int, error) {
+	var weaponType *string
+	if req.WeaponType != "" {
+		if !models.IsValidWeaponType(req.WeaponType) {
+			return nil, nil, fmt.Errorf("無効な武器種です")
+		}
+		weaponType = &req.WeaponType
+	}
+
+	var hunterRank *int
+	if req.HunterRank != 0 {
+		if req.HunterRank < 1 || req.HunterRank > models.MaxHunterRank {
+			return nil, nil, fmt.Errorf("ハンターランクは1〜%dで入力してください", models.MaxHunterRank)
+		}
+		hunterRank = &req.HunterRank
+	}
+	return weaponType, hunterRank, nil
+}
+
+// isEmpty 装備を何も申告していないか
+func (req MemberLoadoutRequest) isEmpty() bool {
+	return req.WeaponType == "" && req.HunterRank == 0
+}
+
+// validateWantedWeapons 募集する武器種を検証する。枠の数は部屋の定員まで
+func validateWantedWeapons(codes []string, maxPlayers int) error {
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if !models.IsValidWeaponType(code) {
+			return fmt.Errorf("無効な武器種です")
+		}
+		seen[code] = true
+	}
+	if len(seen) > maxPlayers {
+		return fmt.Errorf("募集する武器種は最大人数（%d人）以内で選んでください", maxPlayers)
+	}
+	return nil
+}
+
+// UpdateLoadout 参加中のメンバーが自分の武器種とハンターランクを申告し直す
+func (h *RoomHandler) UpdateLoadout(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	var req MemberLoadoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	weaponType, hunterRank, err := req.loadout()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.repo.Room.UpdateMemberLoadout(roomID, dbUser.ID, weaponType, hunterRank); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastLoadoutUpdate(roomID)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message":     "装備を更新しました",
+		"weapon_type": weaponType,
+		"hunter_rank": hunterRank,
+	})
+}
+
+// broadcastLoadoutUpdate 装備の変更を member_update イベントで部屋に知らせる
+func (h *RoomHandler) broadcastLoadoutUpdate(roomID uuid.UUID) {
+	if h.hub == nil {
+		return
+	}
+
+	members, err := h.repo.Room.GetRoomMembers(roomID)
+	if err != nil {
+		log.Printf("メンバー情報取得エラー: %v", err)
+		return
+	}
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "member_update",
+		Data: map[string]interface{}{
+			"action":  "loadout",
+			"members": members,
+			"count":   len(members),
+		},
+	})
+}
+
+// loadMemberWeapons 部屋一覧の武器枠の表示用に、参加中のメンバーの武器種を部屋ごとにまとめて取得する
+func (h *RoomHandler) loadMemberWeapons(roomIDs []uuid.UUID) map[uuid.UUID][]string {
+	weapons, err := h.repo.Room.GetActiveMemberWeapons(roomIDs)
+	if err != nil {
+		// 取得できなくても一覧は表示する（武器枠は空きとして表示される）
+		log.Printf("メンバーの武器種の取得に失敗: %v", err)
+		return map[uuid.UUID][]string{}
+	}
+	return weapons
+}
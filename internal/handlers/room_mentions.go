@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+// mentionTarget メッセージ中で @ 指定された部屋のメンバー
+type mentionTarget struct {
+	UserID uuid.UUID
+	Name   string // メッセージ中に書かれた名前（@ を除く）
+}
+
+// mentionCandidate メンションとして受け付ける名前（表示名・ユーザー名）とそのメンバー
+type mentionCandidate struct {
+	name   string
+	userID uuid.UUID
+}
+
+// resolveMentions メッセージ中の @表示名 / @ユーザー名 を部屋のメンバーと照合する。
+// 表示名に空白を含む場合もあるため、@ の直後に一致する最も長い名前を採用し、同じメンバーは1回だけ返す
+func resolveMentions(text string, members []models.RoomMember) []mentionTarget {
+	if !strings.Contains(text, "@") || len(members) == 0 {
+		return nil
+	}
+
+	candidates := make([]mentionCandidate, 0, len(members)*2)
+	for _, member := range members {
+		if member.User.DisplayName != "" {
+			candidates = append(candidates, mentionCandidate{name: member.User.DisplayName, userID: member.UserID})
+		}
+		if member.User.Username != nil && *member.User.Username != "" {
+			candidates = append(candidates, mentionCandidate{name: *member.User.Username, userID: member.UserID})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].name) > len(candidates[j].name)
+	})
+
+	var targets []mentionTarget
+	seen := make(map[uuid.UUID]bool)
+	for i := 0; i < len(text); i++ {
+		if text[i] != '@' || !isMentionStart(text[:i]) {
+			continue
+		}
+		rest := text[i+1:]
+		for _, c := range candidates {
+			if len(rest) < len(c.name) || !strings.EqualFold(rest[:len(c.name)], c.name) {
+				continue
+			}
+			if !isMentionEnd(rest[len(c.name):]) {
+				continue
+			}
+			if !seen[c.userID] {
+				seen[c.userID] = true
+				targets = append(targets, mentionTarget{UserID: c.userID, Name: rest[:len(c.name)]})
+			}
+			i += len(c.name)
+			break
+		}
+	}
+	return targets
+}
+
+// isMentionStart @ の直前が文字・数字でないか（メールアドレスなどを除くため）
+func isMentionStart(before string) bool {
+	if before == "" {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(before)
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// isMentionEnd 名前の直後が英数字で続いていないか。「@花子さん」のように日本語が続くのは許可する
+func isMentionEnd(after string) bool {
+	if after == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(after)
+	return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// mentionNames 表示用のハイライト対象の名前を返す
+func mentionNames(targets []mentionTarget) []string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	return names
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+func TestResolveMentions(t *testing.T) {
+	hanakoID, hanako2ID, taroID := uuid.New(), uuid.New(), uuid.New()
+	username := "taro_mh"
+	members := []models.RoomMember{
+		{UserID: hanakoID, User: models.User{DisplayName: "花子"}},
+		{UserID: hanako2ID, User: models.User{DisplayName: "花子 2号"}},
+		{UserID: taroID, User: models.User{DisplayName: "Taro", Username: &username}},
+	}
+
+	tests := []struct {
+		name      string
+		text      string
+		wantIDs   []uuid.UUID
+		wantNames []string
+	}{
+		{name: "表示名", text: "@花子 よろしく", wantIDs: []uuid.UUID{hanakoID}, wantNames: []string{"花子"}},
+		{name: "空白を含む表示名は長い方を優先", text: "@花子 2号 集会所へ", wantIDs: []uuid.UUID{hanako2ID}, wantNames: []string{"花子 2号"}},
+		{name: "ユーザー名と大文字小文字の違い", text: "@TARO_MH と @taro", wantIDs: []uuid.UUID{taroID}, wantNames: []string{"TARO_MH"}},
+		{name: "日本語が続いてもよい", text: "@花子さん、準備OK？", wantIDs: []uuid.UUID{hanakoID}, wantNames: []string{"花子"}},
+		{name: "英字が続く名前は別人", text: "@Taroko こんにちは"},
+		{name: "メールアドレスはメンションにしない", text: "連絡先は user@Taro です"},
+		{name: "メンバー以外", text: "@次郎 いますか"},
+		{name: "複数", text: "@Taro @花子 @花子", wantIDs: []uuid.UUID{taroID, hanakoID}, wantNames: []string{"Taro", "花子"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := resolveMentions(tt.text, members)
+			var ids []uuid.UUID
+			for _, target := range targets {
+				ids = append(ids, target.UserID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("resolveMentions() ユーザー = %v, want %v", ids, tt.wantIDs)
+			}
+			if names := mentionNames(targets); len(tt.wantNames) > 0 && !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("mentionNames() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
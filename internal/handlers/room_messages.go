@@ -2,16 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/infrastructure/storage"
 	"mhp-rooms/internal/middleware"
 	"mhp-rooms/internal/models"
 	"mhp-rooms/internal/repository"
+	"mhp-rooms/internal/services"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -19,7 +24,12 @@ import (
 
 type RoomMessageHandler struct {
 	BaseHandler
-	hub *sse.Hub
+	hub                 *sse.Hub
+	notificationService *services.NotificationService
+	ngWordFilter        *services.NGWordFilter
+	chatAttachments     *services.ChatAttachmentService
+	typing              *typingThrottle
+	chatLimits          *chatLimiter
 }
 
 func NewRoomMessageHandler(repo *repository.Repository, hub *sse.Hub) *RoomMessageHandler {
@@ -27,10 +37,20 @@ func NewRoomMessageHandler(repo *repository.Repository, hub *sse.Hub) *RoomMessa
 		BaseHandler: BaseHandler{
 			repo: repo,
 		},
-		hub: hub,
+		hub:                 hub,
+		typing:              newTypingThrottle(typingInterval),
+		chatLimits:          newChatLimiter(loadChatRateLimitConfig()),
+		notificationService: services.NewNotificationService(repo),
+		ngWordFilter:        services.NewNGWordFilter(repo),
+		chatAttachments:     services.NewChatAttachmentService(repo, nil),
 	}
 }
 
+// SetChatAttachments は画像添付の保存先を設定したサービスを設定
+func (h *RoomMessageHandler) SetChatAttachments(chatAttachments *services.ChatAttachmentService) {
+	h.chatAttachments = chatAttachments
+}
+
 // SendMessage はメッセージを送信
 func (h *RoomMessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 	// URLパラメータから部屋IDを取得
@@ -55,22 +75,66 @@ func (h *RoomMessageHandler) SendMessage(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// フォームデータの取得
-	err = r.ParseForm()
-	if err != nil {
+	// フォームデータの取得（画像を添付する場合は multipart/form-data）
+	var imageFile multipart.File
+	var imageHeader *multipart.FileHeader
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+			return
+		}
+		imageFile, imageHeader, err = r.FormFile("image")
+		if err != nil && !errors.Is(err, http.ErrMissingFile) {
+			http.Error(w, "画像の取得に失敗しました", http.StatusBadRequest)
+			return
+		}
+		if imageFile != nil {
+			defer imageFile.Close()
+		}
+	} else if err := r.ParseForm(); err != nil {
 		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
 		return
 	}
 
+	// 画像付きの場合、本文はキャプションなので空でもよい
 	messageText := strings.TrimSpace(r.FormValue("message"))
-	if messageText == "" {
-		http.Error(w, "メッセージが空です", http.StatusBadRequest)
-		return
+	if imageFile == nil || messageText != "" {
+		if messageText, err = normalizeMessageText(messageText); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if messageText, err = h.ngWordFilter.Apply(user.ID, &roomID, models.NGWordTargetChat, messageText); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	}
 
-	// メッセージ長制限（1000文字）
-	if len(messageText) > 1000 {
-		http.Error(w, "メッセージは1000文字以内で入力してください", http.StatusBadRequest)
+	// 返信先の確認（同じ部屋の削除されていないチャットメッセージのみ）
+	var replyTo *models.RoomMessage
+	if replyToIDStr := strings.TrimSpace(r.FormValue("reply_to_id")); replyToIDStr != "" {
+		replyToID, err := uuid.Parse(replyToIDStr)
+		if err != nil {
+			http.Error(w, "無効な返信先です", http.StatusBadRequest)
+			return
+		}
+		replyTo, err = h.repo.RoomMessage.FindMessageByID(replyToID)
+		if err != nil || !canReplyTo(replyTo, roomID) {
+			http.Error(w, "返信先のメッセージが見つかりません", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// 送信頻度の制限（低速モードはホストには適用しない）。入力の誤りで断った送信は数えない
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	slowMode := room.SlowModeInterval()
+	if room.HostUserID == user.ID {
+		slowMode = 0
+	}
+	if !h.allowChat(w, roomID, user, h.chatLimits.check(roomID, user.ID, slowMode, time.Now())) {
 		return
 	}
 
@@ -79,18 +143,51 @@ func (h *RoomMessageHandler) SendMessage(w http.ResponseWriter, r *http.Request)
 		RoomID:      roomID,
 		UserID:      user.ID,
 		Message:     messageText,
-		MessageType: "chat",
+		MessageType: models.RoomMessageTypeChat,
+	}
+	if replyTo != nil {
+		message.ReplyToID = &replyTo.ID
+	}
+
+	// 画像を保存し、サムネイル情報と一緒にメッセージへ添付する
+	if imageFile != nil {
+		attachment, err := h.chatAttachments.Upload(r.Context(), roomID, imageFile, imageHeader)
+		if err != nil {
+			log.Printf("チャット画像のアップロードに失敗しました room_id=%s user_id=%s: %v", roomID, user.ID, err)
+			h.chatLimits.refund(roomID, user.ID, true)
+			status, msg := chatImageUploadError(err)
+			http.Error(w, msg, status)
+			return
+		}
+		message.MessageType = models.RoomMessageTypeImage
+		message.Attachment = attachment
 	}
 
 	// DBに保存
 	err = h.repo.RoomMessage.CreateMessage(message)
 	if err != nil {
+		if err := h.chatAttachments.Discard(r.Context(), message.Attachment); err != nil {
+			log.Printf("保存できなかったチャット画像の削除に失敗しました room_id=%s: %v", roomID, err)
+		}
+		h.chatLimits.refund(roomID, user.ID, true)
 		http.Error(w, "メッセージの送信に失敗しました", http.StatusInternalServerError)
 		return
 	}
 
-	// ユーザー情報を設定
+	// ユーザー情報と返信先の引用を設定
 	message.User = *user
+	if replyTo != nil {
+		message.ReplyTo = replyTo.Quote()
+	}
+
+	// メンションを部屋のメンバーと照合し、メンションされた人にお知らせを送る
+	members, err := h.repo.Room.GetRoomMembers(roomID)
+	if err != nil {
+		log.Printf("メンションの照合に失敗しました room_id=%s: %v", roomID, err)
+	}
+	mentions := resolveMentions(messageText, members)
+	message.Mentions = mentionNames(mentions)
+	h.notifyMentions(roomID, user, messageText, mentions)
 
 	// SSEでブロードキャスト
 	event := sse.Event{
@@ -154,11 +251,19 @@ func (h *RoomMessageHandler) StreamMessages(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 
 	// クライアントの作成
+	// 再接続時は最後に受け取ったイベントIDから取りこぼしを再送する。
+	// EventSource を作り直すクライアントはヘッダーを付けられないため、クエリパラメータでも受け付ける
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
 	client := &sse.Client{
-		ID:     uuid.New(),
-		UserID: user.ID,
-		RoomID: roomID,
-		Send:   make(chan sse.Event, 10),
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		RoomID:      roomID,
+		Send:        make(chan sse.Event, 10),
+		Waiting:     tokenData.Waiting,
+		LastEventID: lastEventID,
 	}
 
 	// Hubに登録
@@ -185,6 +290,30 @@ func (h *RoomMessageHandler) StreamMessages(w http.ResponseWriter, r *http.Reque
 	fmt.Fprintf(w, "event: connected\ndata: {\"status\":\"connected\"}\n\n")
 	flusher.Flush()
 
+	// 取りこぼしたイベントを再送する。再送できなければ再取得を促す
+	if client.ResyncReason != "" {
+		if data, err := sse.SerializeEvent(sse.Event{Type: "resync_required", Data: sse.ResyncData{Reason: client.ResyncReason}}); err == nil {
+			fmt.Fprint(w, data)
+		}
+	}
+	for _, event := range client.Replay {
+		data, err := sse.SerializeEvent(event)
+		if err != nil {
+			continue
+		}
+		fmt.Fprint(w, data)
+		h.markDelivered(client, event)
+	}
+	flusher.Flush()
+
+	// 接続中のメンバーの一覧を送る
+	if !client.Waiting {
+		if data, err := sse.SerializeEvent(presenceSnapshot(h.hub.OnlineUserIDs(roomID), user.ID)); err == nil {
+			fmt.Fprint(w, data)
+			flusher.Flush()
+		}
+	}
+
 	for {
 		select {
 		case event := <-client.Send:
@@ -195,6 +324,7 @@ func (h *RoomMessageHandler) StreamMessages(w http.ResponseWriter, r *http.Reque
 			}
 			fmt.Fprint(w, data)
 			flusher.Flush()
+			h.markDelivered(client, event)
 
 		case <-ticker.C:
 			// キープアライブ
@@ -258,7 +388,332 @@ func (h *RoomMessageHandler) GetMessages(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// 最新のページを取得したら、そこまでを既読にする
+	if beforeID == nil && len(messages) > 0 {
+		if err := h.repo.RoomMessage.MarkRead(roomID, user.ID, &messages[len(messages)-1]); err != nil {
+			log.Printf("既読位置の更新に失敗しました room_id=%s user_id=%s: %v", roomID, user.ID, err)
+		}
+	}
+
+	// リアクションの集計をまとめて付ける（メッセージごとに取得し直さなくてよいように）
+	attachReactions(h.repo, messages, user.ID)
+
+	// 削除済みメッセージは本文を伏せて墓標として返す
+	maskDeletedMessages(messages)
+
+	// メンションのハイライト用に、現在のメンバーと照合した名前を付ける
+	if members, err := h.repo.Room.GetRoomMembers(roomID); err == nil {
+		for i := range messages {
+			if messages[i].IsUserMessage() {
+				messages[i].Mentions = mentionNames(resolveMentions(messages[i].Message, members))
+			}
+		}
+	}
+
 	// JSON形式で返却
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(messages)
 }
+
+// EditMessage は自分のメッセージを編集する
+func (h *RoomMessageHandler) EditMessage(w http.ResponseWriter, r *http.Request) {
+	roomID, messageID, ok := parseRoomMessageParams(w, r)
+	if !ok {
+		return
+	}
+
+	user, ok := middleware.GetDBUserFromContext(r.Context())
+	if !ok || user == nil {
+		http.Error(w, "認証が必要です", http.StatusUnauthorized)
+		return
+	}
+
+	if _, ok := h.activeRoomForMember(w, roomID, user.ID); !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+	messageText, err := normalizeMessageText(r.FormValue("message"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	message, err := h.repo.RoomMessage.FindMessageByID(messageID)
+	if err != nil || message.RoomID != roomID {
+		http.Error(w, "メッセージが見つかりません", http.StatusNotFound)
+		return
+	}
+	if !canEditMessage(message, user.ID) {
+		http.Error(w, "このメッセージは編集できません", http.StatusForbidden)
+		return
+	}
+	if messageText, err = h.ngWordFilter.Apply(user.ID, &roomID, models.NGWordTargetChat, messageText); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// 本文が変わらない場合（伏せ字にした結果が保存済みの本文と同じ場合を含む）は履歴を残さず、配信もしない
+	if messageText == message.Message {
+		respondWithJSON(w, http.StatusOK, h.withMessageContext(roomID, user, message))
+		return
+	}
+
+	// 編集も部屋に配信されるため、送信と同じ枠で制限する（ミュート中は編集もできない）
+	if !h.allowChat(w, roomID, user, h.chatLimits.checkEdit(roomID, user.ID, time.Now())) {
+		return
+	}
+
+	updated, err := h.repo.RoomMessage.EditMessage(messageID, user.ID, messageText)
+	if err != nil {
+		h.chatLimits.refund(roomID, user.ID, false)
+		http.Error(w, "メッセージの編集に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	updated = h.withMessageContext(roomID, user, updated)
+
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   updated.ID.String(),
+		Type: "message_updated",
+		Data: updated,
+	})
+
+	respondWithJSON(w, http.StatusOK, updated)
+}
+
+// DeleteMessage は自分のメッセージ、またはホストが部屋内のメッセージを削除する
+func (h *RoomMessageHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	roomID, messageID, ok := parseRoomMessageParams(w, r)
+	if !ok {
+		return
+	}
+
+	user, ok := middleware.GetDBUserFromContext(r.Context())
+	if !ok || user == nil {
+		http.Error(w, "認証が必要です", http.StatusUnauthorized)
+		return
+	}
+
+	room, ok := h.activeRoomForMember(w, roomID, user.ID)
+	if !ok {
+		return
+	}
+
+	message, err := h.repo.RoomMessage.FindMessageByID(messageID)
+	if err != nil || message.RoomID != roomID {
+		http.Error(w, "メッセージが見つかりません", http.StatusNotFound)
+		return
+	}
+	if !canDeleteMessage(message, room, user.ID) {
+		http.Error(w, "このメッセージは削除できません", http.StatusForbidden)
+		return
+	}
+
+	if err := h.repo.RoomMessage.DeleteMessage(messageID, user.ID); err != nil {
+		http.Error(w, "メッセージの削除に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	// 添付画像は削除と同時に消す（失敗しても room-cleanup が拾い直す）
+	if err := h.chatAttachments.PurgeMessage(r.Context(), message); err != nil {
+		log.Printf("添付画像の削除に失敗しました message_id=%s: %v", message.ID, err)
+	}
+
+	// ホストが他人のメッセージを消した場合はモデレーション操作として記録する
+	if message.UserID != user.ID {
+		roomLog := &models.RoomLog{
+			RoomID: roomID,
+			UserID: &user.ID,
+			Action: "delete_message",
+			Details: models.JSONB{
+				Data: map[string]interface{}{
+					"message_id": message.ID,
+					"user_name":  adminUserName(&message.UserID, &message.User),
+				},
+			},
+		}
+		if err := h.repo.RoomLog.CreateLog(roomLog); err != nil {
+			log.Printf("メッセージ削除ログの記録に失敗しました message_id=%s: %v", message.ID, err)
+		}
+	}
+
+	data := map[string]interface{}{
+		"id":                 message.ID,
+		"room_id":            roomID,
+		"deleted_by_user_id": user.ID,
+	}
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   message.ID.String(),
+		Type: "message_deleted",
+		Data: data,
+	})
+
+	respondWithJSON(w, http.StatusOK, data)
+}
+
+// notifyMentions メンションされたメンバー（送信者本人と、送信者をブロックしている人を除く）にお知らせを送る
+func (h *RoomMessageHandler) notifyMentions(roomID uuid.UUID, sender *models.User, text string, mentions []mentionTarget) {
+	if len(mentions) == 0 {
+		return
+	}
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil || room == nil {
+		return
+	}
+	for _, mention := range mentions {
+		if mention.UserID == sender.ID {
+			continue
+		}
+		if blocked, err := h.repo.UserBlock.IsBlocked(mention.UserID, sender.ID); err == nil && blocked {
+			continue
+		}
+		if err := h.notificationService.NotifyMentioned(mention.UserID, room, sender, text); err != nil {
+			log.Printf("メンションのお知らせの作成に失敗しました user_id=%s room_id=%s: %v", mention.UserID, roomID, err)
+		}
+	}
+}
+
+// markDelivered SSEでメンバーに届けたメッセージまでを既読にする（キャンセル待ちの接続は対象外）
+func (h *RoomMessageHandler) markDelivered(client *sse.Client, event sse.Event) {
+	if client.Waiting || event.Type != "message" {
+		return
+	}
+	message, ok := event.Data.(*models.RoomMessage)
+	if !ok {
+		return
+	}
+	if err := h.repo.RoomMessage.MarkRead(client.RoomID, client.UserID, message); err != nil {
+		log.Printf("既読位置の更新に失敗しました room_id=%s user_id=%s: %v", client.RoomID, client.UserID, err)
+	}
+}
+
+// activeRoomForMember 参加中のメンバーがメッセージを操作できる部屋を取得する。
+// 退出・キックされた人や解散した部屋では、過去のメッセージを変更して部屋に配信できないようにする
+func (h *RoomMessageHandler) activeRoomForMember(w http.ResponseWriter, roomID, userID uuid.UUID) (*models.Room, bool) {
+	if !h.repo.Room.IsUserJoinedRoom(roomID, userID) {
+		http.Error(w, "部屋のメンバーではありません", http.StatusForbidden)
+		return nil, false
+	}
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil || room == nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return nil, false
+	}
+	if !room.IsActive {
+		http.Error(w, "解散した部屋のメッセージは変更できません", http.StatusForbidden)
+		return nil, false
+	}
+	return room, true
+}
+
+// withMessageContext 編集結果として返すメッセージに投稿者・返信先の引用・メンションを付ける
+func (h *RoomMessageHandler) withMessageContext(roomID uuid.UUID, user *models.User, message *models.RoomMessage) *models.RoomMessage {
+	message.User = *user
+	message.ReplyTo = h.replyQuote(message.ReplyToID)
+	if members, err := h.repo.Room.GetRoomMembers(roomID); err == nil {
+		message.Mentions = mentionNames(resolveMentions(message.Message, members))
+	}
+	return message
+}
+
+// replyQuote 返信先メッセージの引用を取得する。返信でない場合や取得できない場合は nil
+func (h *RoomMessageHandler) replyQuote(replyToID *uuid.UUID) *models.RoomMessageQuote {
+	if replyToID == nil {
+		return nil
+	}
+	parent, err := h.repo.RoomMessage.FindMessageByID(*replyToID)
+	if err != nil {
+		return nil
+	}
+	return parent.Quote()
+}
+
+// parseRoomMessageParams URLパラメータから部屋IDとメッセージIDを取得する
+func parseRoomMessageParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効な部屋IDです", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	messageID, err := uuid.Parse(chi.URLParam(r, "messageID"))
+	if err != nil {
+		http.Error(w, "無効なメッセージIDです", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return roomID, messageID, true
+}
+
+// normalizeMessageText 前後の空白を除き、空文字と長さ制限（1000文字）をチェックする
+func normalizeMessageText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("メッセージが空です")
+	}
+	if len(text) > 1000 {
+		return "", fmt.Errorf("メッセージは1000文字以内で入力してください")
+	}
+	return text, nil
+}
+
+// chatImageUploadError 画像アップロードのエラーをステータスコードと表示用メッセージに変換する
+func chatImageUploadError(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrChatImageUnavailable):
+		return http.StatusServiceUnavailable, err.Error()
+	case errors.Is(err, storage.ErrFileTooLarge), errors.Is(err, storage.ErrUnsupportedContentType):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, storage.ErrInvalidImage):
+		return http.StatusBadRequest, storage.ErrInvalidImage.Error()
+	default:
+		return http.StatusInternalServerError, "画像のアップロードに失敗しました"
+	}
+}
+
+// canEditMessage 編集できるのは投稿者本人のチャットメッセージのみ（画像メッセージのキャプションは編集できない）
+func canEditMessage(message *models.RoomMessage, userID uuid.UUID) bool {
+	return message.MessageType == models.RoomMessageTypeChat && !message.IsDeleted && message.UserID == userID
+}
+
+// canDeleteMessage 投稿者本人と部屋のホストがチャット・画像メッセージを削除できる
+func canDeleteMessage(message *models.RoomMessage, room *models.Room, userID uuid.UUID) bool {
+	if !message.IsUserMessage() || message.IsDeleted {
+		return false
+	}
+	return message.UserID == userID || room.HostUserID == userID
+}
+
+// canReplyTo 返信できるのは同じ部屋の削除されていないチャット・画像メッセージのみ
+func canReplyTo(parent *models.RoomMessage, roomID uuid.UUID) bool {
+	return parent.RoomID == roomID && parent.IsUserMessage() && !parent.IsDeleted
+}
+
+// attachReactions メッセージにリアクションの集計（viewerID のリアクション済みかを含む）を付ける
+func attachReactions(repo *repository.Repository, messages []models.RoomMessage, viewerID uuid.UUID) {
+	ids := make([]uuid.UUID, 0, len(messages))
+	for i := range messages {
+		if messages[i].IsUserMessage() && !messages[i].IsDeleted {
+			ids = append(ids, messages[i].ID)
+		}
+	}
+	summaries, err := repo.Reaction.GetReactionSummaries(ids, &viewerID)
+	if err != nil {
+		log.Printf("リアクションの集計に失敗しました: %v", err)
+		return
+	}
+	for i := range messages {
+		messages[i].Reactions = summaries[messages[i].ID]
+	}
+}
+
+// maskDeletedMessages 削除済みメッセージの本文と添付画像・リアクションを伏せる
+func maskDeletedMessages(messages []models.RoomMessage) {
+	for i := range messages {
+		if messages[i].IsDeleted {
+			messages[i].Message = ""
+			messages[i].Attachment = nil
+			messages[i].Reactions = nil
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/storage"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/services"
+)
+
+func TestNormalizeMessageText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "前後の空白を除く", text: "  よろしく  ", want: "よろしく"},
+		{name: "空白のみ", text: "   ", wantErr: true},
+		{name: "1000文字を超える", text: strings.Repeat("a", 1001), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeMessageText(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeMessageText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeMessageText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessagePermissions(t *testing.T) {
+	authorID, hostID, otherID := uuid.New(), uuid.New(), uuid.New()
+	room := &models.Room{HostUserID: hostID}
+	chat := &models.RoomMessage{UserID: authorID, MessageType: "chat"}
+	system := &models.RoomMessage{UserID: authorID, MessageType: "system"}
+	deleted := &models.RoomMessage{UserID: authorID, MessageType: "chat", IsDeleted: true}
+	image := &models.RoomMessage{UserID: authorID, MessageType: models.RoomMessageTypeImage}
+
+	tests := []struct {
+		name       string
+		message    *models.RoomMessage
+		userID     uuid.UUID
+		wantEdit   bool
+		wantDelete bool
+	}{
+		{name: "投稿者は編集・削除できる", message: chat, userID: authorID, wantEdit: true, wantDelete: true},
+		{name: "ホストは削除のみできる", message: chat, userID: hostID, wantDelete: true},
+		{name: "他のメンバーは何もできない", message: chat, userID: otherID},
+		{name: "システムメッセージは対象外", message: system, userID: authorID},
+		{name: "削除済みは対象外", message: deleted, userID: hostID},
+		{name: "画像は投稿者とホストが削除できるが編集はできない", message: image, userID: authorID, wantDelete: true},
+		{name: "ホストは画像も削除できる", message: image, userID: hostID, wantDelete: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canEditMessage(tt.message, tt.userID); got != tt.wantEdit {
+				t.Errorf("canEditMessage() = %v, want %v", got, tt.wantEdit)
+			}
+			if got := canDeleteMessage(tt.message, room, tt.userID); got != tt.wantDelete {
+				t.Errorf("canDeleteMessage() = %v, want %v", got, tt.wantDelete)
+			}
+		})
+	}
+}
+
+func TestCanReplyTo(t *testing.T) {
+	roomID := uuid.New()
+
+	tests := []struct {
+		name   string
+		parent *models.RoomMessage
+		want   bool
+	}{
+		{name: "同じ部屋のチャット", parent: &models.RoomMessage{RoomID: roomID, MessageType: "chat"}, want: true},
+		{name: "別の部屋のメッセージ", parent: &models.RoomMessage{RoomID: uuid.New(), MessageType: "chat"}},
+		{name: "同じ部屋の画像", parent: &models.RoomMessage{RoomID: roomID, MessageType: models.RoomMessageTypeImage}, want: true},
+		{name: "システムメッセージ", parent: &models.RoomMessage{RoomID: roomID, MessageType: "system"}},
+		{name: "削除済みのメッセージ", parent: &models.RoomMessage{RoomID: roomID, MessageType: "chat", IsDeleted: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canReplyTo(tt.parent, roomID); got != tt.want {
+				t.Errorf("canReplyTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatImageUploadError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "保存先なし", err: services.ErrChatImageUnavailable, wantStatus: http.StatusServiceUnavailable, wantMsg: services.ErrChatImageUnavailable.Error()},
+		{name: "サイズ超過", err: fmt.Errorf("%w（最大 10 MB）", storage.ErrFileTooLarge), wantStatus: http.StatusBadRequest, wantMsg: "ファイルサイズが制限を超えています（最大 10 MB）"},
+		{name: "形式違い", err: storage.ErrUnsupportedContentType, wantStatus: http.StatusBadRequest, wantMsg: storage.ErrUnsupportedContentType.Error()},
+		{name: "壊れた画像は詳細を返さない", err: fmt.Errorf("%w: unexpected EOF", storage.ErrInvalidImage), wantStatus: http.StatusBadRequest, wantMsg: storage.ErrInvalidImage.Error()},
+		{name: "それ以外は内部エラー", err: errors.New("GCS書き込みエラー"), wantStatus: http.StatusInternalServerError, wantMsg: "画像のアップロードに失敗しました"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, msg := chatImageUploadError(tt.err)
+			if status != tt.wantStatus || msg != tt.wantMsg {
+				t.Errorf("chatImageUploadError() = %d, %q, want %d, %q", status, msg, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRenderMessageItem(t *testing.T) {
+	chdirRepoRoot(t)
+	editedAt := time.Date(2026, 8, 20, 12, 5, 0, 0, time.UTC)
+	user := models.User{DisplayName: "ハンター花子"}
+
+	tests := []struct {
+		name    string
+		message models.RoomMessage
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "編集済みのメッセージ",
+			message: models.RoomMessage{Message: "集会所2です", MessageType: "chat", EditedAt: &editedAt, User: user},
+			want:    []string{"ハンター花子", "集会所2です", "(編集済み)"},
+		},
+		{
+			name:    "メンションを強調しつつ本文はエスケープする",
+			message: models.RoomMessage{Message: "@花子 <b>集合</b>", MessageType: "chat", Mentions: []string{"花子"}, User: user},
+			want:    []string{`<span class="text-blue-600 font-semibold">@花子</span>`, "&lt;b&gt;集合&lt;/b&gt;"},
+			notWant: []string{"<b>集合</b>"},
+		},
+		{
+			name: "返信先を引用する",
+			message: models.RoomMessage{Message: "了解です", MessageType: "chat", User: user,
+				ReplyTo: &models.RoomMessageQuote{ID: uuid.New(), UserName: "ハンター太郎", Message: "集会所2に集合"}},
+			want: []string{"data-reply-to-id", "ハンター太郎", "集会所2に集合", "了解です"},
+		},
+		{
+			name: "削除された返信先",
+			message: models.RoomMessage{Message: "了解です", MessageType: "chat", User: user,
+				ReplyTo: &models.RoomMessageQuote{ID: uuid.New(), UserName: "ハンター太郎", IsDeleted: true}},
+			want: []string{"削除されたメッセージへの返信"},
+		},
+		{
+			name: "添付画像はサムネイルから元画像にリンクする",
+			message: models.RoomMessage{MessageType: models.RoomMessageTypeImage, User: user,
+				Attachment: &models.RoomMessageAttachment{URL: "https://assets.example.test/chat/shot.png", ThumbnailURL: "https://assets.example.test/chat/thumbs/shot.jpg", ThumbnailWidth: 320, ThumbnailHeight: 180}},
+			want:    []string{`href="https://assets.example.test/chat/shot.png"`, `src="https://assets.example.test/chat/thumbs/shot.jpg"`, `width="320"`},
+			notWant: []string{"bg-gray-200 text-gray-800"},
+		},
+		{
+			name: "リアクションの集計を表示する",
+			message: models.RoomMessage{Message: "ナイス！", MessageType: "chat", User: user,
+				Reactions: []models.MessageReactionCount{{ReactionType: "like", Emoji: "👍", ReactionName: "いいね", ReactionCount: 3, HasReacted: true}}},
+			want: []string{`data-reaction-type="like"`, "👍 3", "bg-blue-50"},
+		},
+		{
+			name: "削除済みのメッセージのリアクションは表示しない",
+			message: models.RoomMessage{Message: "消した", MessageType: "chat", IsDeleted: true, User: user,
+				Reactions: []models.MessageReactionCount{{ReactionType: "like", Emoji: "👍", ReactionCount: 1}}},
+			want:    []string{"このメッセージは削除されました"},
+			notWant: []string{"data-reaction-type"},
+		},
+		{
+			name: "削除済みの画像は表示しない",
+			message: models.RoomMessage{MessageType: models.RoomMessageTypeImage, IsDeleted: true, User: user,
+				Attachment: &models.RoomMessageAttachment{URL: "https://assets.example.test/chat/shot.png", ThumbnailURL: "https://assets.example.test/chat/thumbs/shot.jpg"}},
+			want:    []string{"このメッセージは削除されました"},
+			notWant: []string{"shot.png", "shot.jpg"},
+		},
+		{
+			name:    "削除済みのメッセージは墓標になる",
+			message: models.RoomMessage{Message: "荒らし発言", MessageType: "chat", IsDeleted: true, User: user},
+			want:    []string{"このメッセージは削除されました"},
+			notWant: []string{"荒らし発言", "ハンター花子"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []models.RoomMessage{tt.message}
+			maskDeletedMessages(messages)
+
+			w := httptest.NewRecorder()
+			if err := renderPartialTemplate(w, "message_item", messages[0]); err != nil {
+				t.Fatalf("renderPartialTemplate() error = %v", err)
+			}
+			body := w.Body.String()
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("描画結果に %q が含まれていない", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("描画結果に %q が含まれている", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestEditAndDeleteMessageRequireActiveMember(t *testing.T) {
+	author := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+
+	tests := []struct {
+		name     string
+		left     bool
+		dismiss  bool
+		wantCode int
+	}{
+		{name: "参加中のメンバー", wantCode: http.StatusOK},
+		{name: "退出・キックされたメンバー", left: true, wantCode: http.StatusForbidden},
+		{name: "解散した部屋", dismiss: true, wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, HostUserID: uuid.New(), IsActive: !tt.dismiss}
+			message := &models.RoomMessage{
+				BaseModel:   models.BaseModel{ID: uuid.New()},
+				RoomID:      room.ID,
+				UserID:      author.ID,
+				Message:     "よろしく",
+				MessageType: models.RoomMessageTypeChat,
+			}
+			members := []uuid.UUID{author.ID}
+			if tt.left {
+				members = nil
+			}
+			h := newTestRoomMessageHandler(room, message, members...)
+			params := map[string]string{"id": room.ID.String(), "messageID": message.ID.String()}
+			target := "/rooms/" + room.ID.String() + "/messages/" + message.ID.String()
+
+			w := httptest.NewRecorder()
+			h.EditMessage(w, newTestFormRequest("PUT", target, url.Values{"message": {"編集後"}}, params, author))
+			if w.Code != tt.wantCode {
+				t.Errorf("編集: status = %d, want %d", w.Code, tt.wantCode)
+			}
+
+			w = httptest.NewRecorder()
+			h.DeleteMessage(w, newTestRequest("DELETE", target, nil, params, author))
+			if w.Code != tt.wantCode {
+				t.Errorf("削除: status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if message.IsDeleted != (tt.wantCode == http.StatusOK) {
+				t.Errorf("削除された = %v", message.IsDeleted)
+			}
+		})
+	}
+}
+
+func TestEditMessageUnchangedText(t *testing.T) {
+	author := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, HostUserID: uuid.New(), IsActive: true}
+	message := &models.RoomMessage{
+		BaseModel:   models.BaseModel{ID: uuid.New()},
+		RoomID:      room.ID,
+		UserID:      author.ID,
+		Message:     "よろしく",
+		MessageType: models.RoomMessageTypeChat,
+	}
+	h := newTestRoomMessageHandler(room, message, author.ID)
+	params := map[string]string{"id": room.ID.String(), "messageID": message.ID.String()}
+
+	// 前後の空白を除くと同じ本文なら、履歴を残さず送信枠も使わない
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.EditMessage(w, newTestFormRequest("PUT", "/rooms/"+room.ID.String()+"/messages/"+message.ID.String(), url.Values{"message": {" よろしく "}}, params, author))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+	}
+	if edits := h.repo.RoomMessage.(*fakeRoomMessageRepo).edits; edits != 0 {
+		t.Errorf("本文が変わらない編集が保存された: %d回", edits)
+	}
+	if result := h.chatLimits.checkEdit(room.ID, author.ID, time.Now()); !result.Allowed {
+		t.Errorf("本文が変わらない編集で送信枠が使われている: %+v", result)
+	}
+}
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// 準備確認の制限時間。過ぎると未完了のメンバーを結果に残して打ち切る
+const readyCheckTimeout = 60 * time.Second
+
+// 準備確認の終わり方
+const (
+	ReadyCheckResultCompleted = "completed" // 全員の準備ができた
+	ReadyCheckResultTimeout   = "timeout"   // 制限時間切れ
+	ReadyCheckResultCancelled = "cancelled" // ホストが取り消した
+)
+
+// ReadyCheckMember 準備確認の対象メンバーと準備状態
+type ReadyCheckMember struct {
+	UserID         uuid.UUID `json:"user_id"`
+	SupabaseUserID uuid.UUID `json:"supabase_user_id"`
+	DisplayName    string    `json:"display_name"`
+	Ready          bool      `json:"ready"`
+}
+
+// RoomReadyCheck 部屋で進行中の準備確認。開始時点で参加中のメンバーが対象
+type RoomReadyCheck struct {
+	ID        uuid.UUID          `json:"id"`
+	RoomID    uuid.UUID          `json:"room_id"`
+	StartedAt time.Time          `json:"started_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Members   []ReadyCheckMember `json:"members"`
+
+	starter *models.User // 開始したホスト（結果のシステムメッセージの送信者）
+}
+
+// ReadyCount 準備ができたメンバーの数
+func (c *RoomReadyCheck) ReadyCount() int {
+	count := 0
+	for _, member := range c.Members {
+		if member.Ready {
+			count++
+		}
+	}
+	return count
+}
+
+// AllReady 対象メンバー全員の準備ができたかどうか
+func (c *RoomReadyCheck) AllReady() bool {
+	return len(c.Members) > 0 && c.ReadyCount() == len(c.Members)
+}
+
+// setReady userID の準備状態を変える。対象メンバーでなければ false を返す
+func (c *RoomReadyCheck) setReady(userID uuid.UUID, ready bool) bool {
+	for i := range c.Members {
+		if c.Members[i].UserID == userID {
+			c.Members[i].Ready = ready
+			return true
+		}
+	}
+	return false
+}
+
+// removeMember 退出・キックされたメンバーを対象から外す。対象メンバーでなければ false を返す
+func (c *RoomReadyCheck) removeMember(userID uuid.UUID) bool {
+	for i := range c.Members {
+		if c.Members[i].UserID == userID {
+			c.Members = append(c.Members[:i], c.Members[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// resultText 準備確認の結果を知らせるシステムメッセージの本文
+func (c *RoomReadyCheck) resultText(result string) string {
+	switch result {
+	case ReadyCheckResultCompleted:
+		return fmt.Sprintf("準備確認: 全員（%d人）の準備ができました", len(c.Members))
+	case ReadyCheckResultCancelled:
+		return "準備確認が取り消されました"
+	}
+
+	var notReady []string
+	for _, member := range c.Members {
+		if !member.Ready {
+			notReady = append(notReady, member.DisplayName)
+		}
+	}
+	return fmt.Sprintf("準備確認: 時間切れ（準備完了 %d/%d人、未完了: %s）",
+		c.ReadyCount(), len(c.Members), strings.Join(notReady, "、"))
+}
+
+// clone ロックの外で JSON にするためのコピー
+func (c *RoomReadyCheck) clone() *RoomReadyCheck {
+	copied := *c
+	copied.Members = append([]ReadyCheckMember(nil), c.Members...)
+	return &copied
+}
+
+// readyCheckStore 部屋ごとに進行中の準備確認を保持する
+type readyCheckStore struct {
+	mu     sync.Mutex
+	checks map[uuid.UUID]*RoomReadyCheck
+}
+
+func newReadyCheckStore() *readyCheckStore {
+	return &readyCheckStore{checks: make(map[uuid.UUID]*RoomReadyCheck)}
+}
+
+// start 準備確認を登録する。既に進行中なら false を返す
+func (s *readyCheckStore) start(check *RoomReadyCheck) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.checks[check.RoomID]; exists {
+		return false
+	}
+	s.checks[check.RoomID] = check
+	return true
+}
+
+// get 進行中の準備確認のコピーを返す。なければ nil
+func (s *readyCheckStore) get(roomID uuid.UUID) *RoomReadyCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if check, exists := s.checks[roomID]; exists {
+		return check.clone()
+	}
+	return nil
+}
+
+// update 進行中の準備確認を fn で書き換え、書き換え後のコピーを返す。
+// 準備確認がない、または fn が false を返した場合は nil を返す
+func (s *readyCheckStore) update(roomID uuid.UUID, fn func(*RoomReadyCheck) bool) *RoomReadyCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	check, exists := s.checks[roomID]
+	if !exists || !fn(check) {
+		return nil
+	}
+	return check.clone()
+}
+
+// end 準備確認を終わらせて返す。checkID が uuid.Nil 以外なら、その準備確認が進行中の場合だけ終わらせる
+func (s *readyCheckStore) end(roomID, checkID uuid.UUID) *RoomReadyCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	check, exists := s.checks[roomID]
+	if !exists || (checkID != uuid.Nil && check.ID != checkID) {
+		return nil
+	}
+	delete(s.checks, roomID)
+	return check
+}
+
+// GetReadyCheck 進行中の準備確認を返す（なければ null）
+func (h *RoomHandler) GetReadyCheck(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+	if !h.isUserJoinedRoom(roomID, dbUser.ID) {
+		http.Error(w, "部屋のメンバーではありません", http.StatusForbidden)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"ready_check": h.readyChecks.get(roomID),
+	})
+}
+
+// StartReadyCheck ホストが参加中のメンバー全員に準備確認を始める
+func (h *RoomHandler) StartReadyCheck(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	if room.HostUserID != dbUser.ID {
+		http.Error(w, "部屋のホストのみが準備確認を始められます", http.StatusForbidden)
+		return
+	}
+
+	members, err := h.repo.Room.GetRoomMembers(roomID)
+	if err != nil {
+		http.Error(w, "メンバー情報の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if len(members) < 2 {
+		http.Error(w, "準備確認には2人以上のメンバーが必要です", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	check := &RoomReadyCheck{
+		ID:        uuid.New(),
+		RoomID:    roomID,
+		StartedAt: now,
+		ExpiresAt: now.Add(readyCheckTimeout),
+		Members:   make([]ReadyCheckMember, 0, len(members)),
+		starter:   dbUser,
+	}
+	for i := range members {
+		check.Members = append(check.Members, ReadyCheckMember{
+			UserID:         members[i].UserID,
+			SupabaseUserID: members[i].User.SupabaseUserID,
+			DisplayName:    h.getDisplayName(&members[i].User),
+			// 呼びかけたホスト自身は準備済みとして扱う
+			Ready: members[i].UserID == dbUser.ID,
+		})
+	}
+	if !h.readyChecks.start(check) {
+		respondWithJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":   "READY_CHECK_ACTIVE",
+			"message": "準備確認が進行中です",
+		})
+		return
+	}
+
+	time.AfterFunc(readyCheckTimeout, func() {
+		h.finishReadyCheck(roomID, check.ID, ReadyCheckResultTimeout)
+	})
+
+	snapshot := h.readyChecks.get(roomID)
+	h.broadcastReadyCheck(roomID, "ready_check_start", snapshot)
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":     "準備確認を始めました",
+		"ready_check": snapshot,
+	})
+}
+
+// SetReady 準備確認に対して自分の準備状態（準備OK / まだ）を切り替える
+func (h *RoomHandler) SetReady(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Ready bool `json:"ready"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	check := h.readyChecks.update(roomID, func(c *RoomReadyCheck) bool {
+		return c.setReady(dbUser.ID, req.Ready)
+	})
+	if check == nil {
+		if h.readyChecks.get(roomID) == nil {
+			http.Error(w, "進行中の準備確認はありません", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "この準備確認の対象ではありません", http.StatusForbidden)
+		return
+	}
+
+	h.broadcastReadyCheck(roomID, "ready_check_update", check)
+	if check.AllReady() {
+		h.finishReadyCheck(roomID, check.ID, ReadyCheckResultCompleted)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"ready_check": check,
+	})
+}
+
+// CancelReadyCheck ホストが進行中の準備確認を取り消す
+func (h *RoomHandler) CancelReadyCheck(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	if room.HostUserID != dbUser.ID {
+		http.Error(w, "部屋のホストのみが準備確認を取り消せます", http.StatusForbidden)
+		return
+	}
+
+	check := h.readyChecks.get(roomID)
+	if check == nil {
+		http.Error(w, "進行中の準備確認はありません", http.StatusNotFound)
+		return
+	}
+	h.finishReadyCheck(roomID, check.ID, ReadyCheckResultCancelled)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "準備確認を取り消しました",
+	})
+}
+
+// finishReadyCheck 準備確認を終わらせ、結果をシステムメッセージと ready_check_end イベントで部屋に知らせる。
+// 既に終わっている（全員準備完了の後にタイマーが動いたなど）場合は何もしない
+func (h *RoomHandler) finishReadyCheck(roomID, checkID uuid.UUID, result string) {
+	check := h.readyChecks.end(roomID, checkID)
+	if check == nil {
+		return
+	}
+
+	text := check.resultText(result)
+	h.broadcastSystemMessage(h.createSystemMessage(roomID, check.starter, text))
+
+	if h.hub == nil {
+		return
+	}
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "ready_check_end",
+		Data: map[string]interface{}{
+			"result":      result,
+			"message":     text,
+			"ready_check": check.clone(),
+		},
+	})
+}
+
+// dropReadyCheckMember 退出・キックされたメンバーを準備確認の対象から外す。
+// 残りのメンバー全員の準備ができていれば、その場で準備確認を終える
+func (h *RoomHandler) dropReadyCheckMember(roomID, userID uuid.UUID) {
+	check := h.readyChecks.update(roomID, func(c *RoomReadyCheck) bool {
+		return c.removeMember(userID)
+	})
+	if check == nil {
+		return
+	}
+
+	if len(check.Members) == 0 {
+		h.readyChecks.end(roomID, check.ID)
+		return
+	}
+	h.broadcastReadyCheck(roomID, "ready_check_update", check)
+	if check.AllReady() {
+		h.finishReadyCheck(roomID, check.ID, ReadyCheckResultCompleted)
+	}
+}
+
+// broadcastReadyCheck 準備確認の状態を部屋のメンバー全員に送る
+func (h *RoomHandler) broadcastReadyCheck(roomID uuid.UUID, eventType string, check *RoomReadyCheck) {
+	if h.hub == nil || check == nil {
+		return
+	}
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: eventType,
+		Data: check,
+	})
+}
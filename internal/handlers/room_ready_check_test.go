@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomReadyCheckResultText(t *testing.T) {
+	newCheck := func(ready ...bool) *RoomReadyCheck {
+		check := &RoomReadyCheck{ID: uuid.New(), RoomID: uuid.New()}
+		for i, r := range ready {
+			check.Members = append(check.Members, ReadyCheckMember{UserID: uuid.New(), DisplayName: []string{"ホスト", "太郎", "花子", "次郎"}[i], Ready: r})
+		}
+		return check
+	}
+
+	tests := []struct {
+		name   string
+		check  *RoomReadyCheck
+		result string
+		want   string
+	}{
+		{"全員準備完了", newCheck(true, true, true), ReadyCheckResultCompleted, "準備確認: 全員（3人）の準備ができました"},
+		{"時間切れ", newCheck(true, false, true, false), ReadyCheckResultTimeout, "準備確認: 時間切れ（準備完了 2/4人、未完了: 太郎、次郎）"},
+		{"取り消し", newCheck(true, false), ReadyCheckResultCancelled, "準備確認が取り消されました"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.resultText(tt.result); got != tt.want {
+				t.Errorf("resultText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadyCheckStore(t *testing.T) {
+	store := newReadyCheckStore()
+	roomID, host, guest := uuid.New(), uuid.New(), uuid.New()
+	check := &RoomReadyCheck{
+		ID:        uuid.New(),
+		RoomID:    roomID,
+		ExpiresAt: time.Now().Add(readyCheckTimeout),
+		Members:   []ReadyCheckMember{{UserID: host, Ready: true}, {UserID: guest}},
+	}
+
+	if !store.start(check) {
+		t.Fatal("準備確認を始められない")
+	}
+	if store.start(&RoomReadyCheck{ID: uuid.New(), RoomID: roomID}) {
+		t.Error("進行中の部屋で準備確認を二重に始められてしまう")
+	}
+
+	// 対象外のユーザーは準備状態を変えられない
+	if got := store.update(roomID, func(c *RoomReadyCheck) bool { return c.setReady(uuid.New(), true) }); got != nil {
+		t.Errorf("対象外のユーザーの準備状態が変わった: %+v", got)
+	}
+
+	// 返されるのはコピーで、書き換えても進行中の状態には影響しない
+	snapshot := store.update(roomID, func(c *RoomReadyCheck) bool { return c.setReady(guest, true) })
+	if snapshot == nil || !snapshot.AllReady() {
+		t.Fatalf("全員準備完了にならない: %+v", snapshot)
+	}
+	snapshot.Members[1].Ready = false
+	if !store.get(roomID).AllReady() {
+		t.Error("コピーの書き換えが進行中の準備確認に反映された")
+	}
+
+	// 退出したメンバーは対象から外れる
+	if got := store.update(roomID, func(c *RoomReadyCheck) bool { return c.removeMember(guest) }); got == nil || len(got.Members) != 1 {
+		t.Errorf("退出後の対象メンバー = %+v, want 1人", got)
+	}
+
+	// 別の準備確認のタイマーでは終わらせない
+	if store.end(roomID, uuid.New()) != nil {
+		t.Error("別の準備確認IDで終了できてしまう")
+	}
+	if ended := store.end(roomID, check.ID); ended == nil || ended.ID != check.ID {
+		t.Fatalf("準備確認を終了できない: %+v", ended)
+	}
+	if store.get(roomID) != nil || store.end(roomID, check.ID) != nil {
+		t.Error("終了した準備確認が残っている")
+	}
+}
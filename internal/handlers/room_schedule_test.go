@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRoomSchedule(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	format := func(t time.Time) string { return t.Format(time.RFC3339) }
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		current   *time.Time
+		wantStart bool
+		wantEnd   bool
+		wantErr   bool
+	}{
+		{name: "未指定は即時募集", start: "", end: ""},
+		{name: "開始のみ", start: format(now.Add(9 * time.Hour)), wantStart: true},
+		{name: "開始と終了", start: format(now.Add(9 * time.Hour)), end: format(now.Add(11 * time.Hour)), wantStart: true, wantEnd: true},
+		{name: "タイムゾーン付きの表記", start: "2026-10-17T23:00:00+09:00", wantStart: true},
+		{name: "終了のみは不正", end: format(now.Add(time.Hour)), wantErr: true},
+		{name: "形式不正", start: "2026-10-17 21:00", wantErr: true},
+		{name: "過去の開始は不正", start: format(past), wantErr: true},
+		{name: "変更していない過去の開始は許可", start: format(past), current: &past, wantStart: true},
+		{name: "7日より先は不正", start: format(now.Add(8 * 24 * time.Hour)), wantErr: true},
+		{name: "終了が開始以前は不正", start: format(now.Add(2 * time.Hour)), end: format(now.Add(2 * time.Hour)), wantErr: true},
+		{name: "24時間を超える募集は不正", start: format(now.Add(time.Hour)), end: format(now.Add(26 * time.Hour)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseRoomSchedule(tt.start, tt.end, tt.current, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRoomSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (start != nil) != tt.wantStart {
+				t.Errorf("start = %v, wantStart %v", start, tt.wantStart)
+			}
+			if (end != nil) != tt.wantEnd {
+				t.Errorf("end = %v, wantEnd %v", end, tt.wantEnd)
+			}
+		})
+	}
+}
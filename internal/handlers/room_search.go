@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// RoomSearchQuery 部屋一覧の検索条件（URL クエリパラメータ）。/rooms と /api/rooms で共通
+type RoomSearchQuery struct {
+	GameVersion string `json:"game_version"` // ゲームバージョンのコード（例: MHP2G）
+	Query       string `json:"q"`            // 部屋名・説明のフリーワード
+	Monster     string `json:"monster"`      // ターゲットモンスター
+	Rank        string `json:"rank"`         // ランク条件
+	Password    string `json:"password"`     // "with"（パスワード付き）/ "without"（パスワードなし）
+	Vacancy     bool   `json:"vacancy"`      // 空きのある部屋のみ
+	Weapon      string `json:"weapon"`       // 募集中でまだ埋まっていない武器種のコード
+	Sort        string `json:"sort"`         // "recent" / "players" / "vacancy"
+}
+
+// 検索文字列の最大長（これを超える分は切り捨てる）
+const maxRoomSearchTermLength = 50
+
+// parseRoomSearchQuery リクエストのクエリパラメータから検索条件を読み取り、不正な値は指定なしとして扱う
+func parseRoomSearchQuery(r *http.Request) RoomSearchQuery {
+	values := r.URL.Query()
+	q := RoomSearchQuery{
+		GameVersion: strings.TrimSpace(values.Get("game_version")),
+		Query:       normalizeRoomSearchTerm(values.Get("q")),
+		Monster:     normalizeRoomSearchTerm(values.Get("monster")),
+		Rank:        normalizeRoomSearchTerm(values.Get("rank")),
+		Password:    values.Get("password"),
+		Sort:        values.Get("sort"),
+		Weapon:      values.Get("weapon"),
+	}
+	if vacancy, err := strconv.ParseBool(values.Get("vacancy")); err == nil {
+		q.Vacancy = vacancy
+	}
+
+	if q.Password != repository.RoomPasswordWith && q.Password != repository.RoomPasswordWithout {
+		q.Password = repository.RoomPasswordAny
+	}
+	if !models.IsValidWeaponType(q.Weapon) {
+		q.Weapon = ""
+	}
+	if q.Sort != repository.RoomSortPlayers && q.Sort != repository.RoomSortVacancy {
+		q.Sort = repository.RoomSortRecent
+	}
+
+	return q
+}
+
+// normalizeRoomSearchTerm 前後の空白を除き、maxRoomSearchTermLength 文字までに切り詰める
+func normalizeRoomSearchTerm(value string) string {
+	runes := []rune(strings.TrimSpace(value))
+	if len(runes) > maxRoomSearchTermLength {
+		runes = runes[:maxRoomSearchTermLength]
+	}
+	return string(runes)
+}
+
+// IsFiltered ゲームバージョン以外の検索条件が指定されているか
+func (q RoomSearchQuery) IsFiltered() bool {
+	return q.Query != "" || q.Monster != "" || q.Rank != "" || q.Password != "" || q.Vacancy || q.Weapon != ""
+}
+
+// Values 検索条件をクエリパラメータに戻す（既定値は省略）
+func (q RoomSearchQuery) Values() url.Values {
+	values := url.Values{}
+	if q.GameVersion != "" {
+		values.Set("game_version", q.GameVersion)
+	}
+	if q.Query != "" {
+		values.Set("q", q.Query)
+	}
+	if q.Monster != "" {
+		values.Set("monster", q.Monster)
+	}
+	if q.Rank != "" {
+		values.Set("rank", q.Rank)
+	}
+	if q.Password != "" {
+		values.Set("password", q.Password)
+	}
+	if q.Vacancy {
+		values.Set("vacancy", "1")
+	}
+	if q.Weapon != "" {
+		values.Set("weapon", q.Weapon)
+	}
+	if q.Sort != "" && q.Sort != repository.RoomSortRecent {
+		values.Set("sort", q.Sort)
+	}
+	return values
+}
+
+// Params リポジトリ用の検索条件に変換する。gameVersionID は GameVersion のコードを解決したもの
+func (q RoomSearchQuery) Params(gameVersionID *uuid.UUID, limit, offset int) repository.RoomSearchParams {
+	return repository.RoomSearchParams{
+		GameVersionID:   gameVersionID,
+		TargetMonster:   q.Monster,
+		RankRequirement: q.Rank,
+		Password:        q.Password,
+		HasVacancy:      q.Vacancy,
+		OpenWeapon:      q.Weapon,
+		Query:           q.Query,
+		Sort:            q.Sort,
+		Limit:           limit,
+		Offset:          offset,
+	}
+}
+
+// resolveGameVersionID ゲームバージョンのコードから ID を引く。未指定・存在しないコードは nil（絞り込みなし）
+func (h *RoomHandler) resolveGameVersionID(code string) *uuid.UUID {
+	if code == "" {
+		return nil
+	}
+	gv, err := h.repo.GameVersion.FindGameVersionByCode(code)
+	if err != nil {
+		return nil
+	}
+	return &gv.ID
+}
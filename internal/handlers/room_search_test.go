@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRoomSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  RoomSearchQuery
+	}{
+		{
+			name:  "指定なし",
+			query: "",
+			want:  RoomSearchQuery{Sort: "recent"},
+		},
+		{
+			name:  "すべての条件",
+			query: "game_version=MHP2G&q=+%E5%88%9D%E5%BF%83%E8%80%85+&monster=%E3%83%86%E3%82%A3%E3%82%AC&rank=HR6&password=without&vacancy=1&sort=vacancy",
+			want: RoomSearchQuery{
+				GameVersion: "MHP2G",
+				Query:       "初心者",
+				Monster:     "ティガ",
+				Rank:        "HR6",
+				Password:    "without",
+				Vacancy:     true,
+				Sort:        "vacancy",
+			},
+		},
+		{
+			name:  "不正な値は指定なし扱い",
+			query: "password=maybe&vacancy=yes&sort=random&weapon=spear",
+			want:  RoomSearchQuery{Sort: "recent"},
+		},
+		{
+			name:  "武器種の空き枠",
+			query: "weapon=hunting_horn",
+			want:  RoomSearchQuery{Weapon: "hunting_horn", Sort: "recent"},
+		},
+		{
+			name:  "長すぎる検索語は切り詰める",
+			query: "q=" + strings.Repeat("あ", maxRoomSearchTermLength+10),
+			want:  RoomSearchQuery{Query: strings.Repeat("あ", maxRoomSearchTermLength), Sort: "recent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/rooms?"+tt.query, nil)
+			if got := parseRoomSearchQuery(r); got != tt.want {
+				t.Errorf("parseRoomSearchQuery() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoomsPageDataPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		data RoomsPageData
+		page int
+		want string
+	}{
+		{
+			name: "条件なしの1ページ目",
+			data: RoomsPageData{Search: RoomSearchQuery{Sort: "recent"}, PerPage: 20},
+			page: 1,
+			want: "/rooms",
+		},
+		{
+			name: "検索条件を引き継ぐ",
+			data: RoomsPageData{Search: RoomSearchQuery{GameVersion: "MHP3", Monster: "ジンオウガ", Vacancy: true, Sort: "players"}, PerPage: 20},
+			page: 2,
+			want: "/rooms?game_version=MHP3&monster=%E3%82%B8%E3%83%B3%E3%82%AA%E3%82%A6%E3%82%AC&page=2&sort=players&vacancy=1",
+		},
+		{
+			name: "表示件数を引き継ぐ",
+			data: RoomsPageData{Search: RoomSearchQuery{Sort: "recent"}, PerPage: 50},
+			page: 3,
+			want: "/rooms?page=3&per_page=50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.data.PageURL(tt.page); got != tt.want {
+				t.Errorf("PageURL(%d) = %q, want %q", tt.page, got, tt.want)
+			}
+		})
+	}
+}
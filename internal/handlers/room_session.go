@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// SessionSummaryView セッションのまとめの表示用データ
+type SessionSummaryView struct {
+	URL              string                      `json:"url"`
+	Duration         string                      `json:"duration"`
+	StartedAt        string                      `json:"startedAt"`
+	EndedAt          string                      `json:"endedAt"`
+	PeakPlayers      int                         `json:"peakPlayers"`
+	MessageCount     int                         `json:"messageCount"`
+	ParticipantCount int                         `json:"participantCount"`
+	Participants     []models.SessionParticipant `json:"participants"`
+}
+
+// RoomSessionSummaryPageData セッションのまとめページのデータ
+type RoomSessionSummaryPageData struct {
+	Room          *models.Room
+	Summary       SessionSummaryView
+	TranscriptURL string // チャット記録の書き出し先。書き出せない（キックされた・保存期間切れ）場合は空
+}
+
+// newSessionSummaryView まとめを表示用に変換する
+func newSessionSummaryView(summary *models.RoomSessionSummary) SessionSummaryView {
+	participants := summary.GetParticipants()
+	return SessionSummaryView{
+		URL:              fmt.Sprintf("/rooms/%s/summary", summary.RoomID),
+		Duration:         formatSessionDuration(summary.Duration()),
+		StartedAt:        formatAdminTime(summary.StartedAt),
+		EndedAt:          formatAdminTime(summary.EndedAt),
+		PeakPlayers:      summary.PeakPlayers,
+		MessageCount:     summary.MessageCount,
+		ParticipantCount: len(participants),
+		Participants:     participants,
+	}
+}
+
+// formatSessionDuration セッションの長さを「2時間15分」「45分」の形式で返す
+func formatSessionDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	switch {
+	case minutes < 1:
+		return "1分未満"
+	case minutes < 60:
+		return fmt.Sprintf("%d分", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d時間", minutes/60)
+	default:
+		return fmt.Sprintf("%d時間%d分", minutes/60, minutes%60)
+	}
+}
+
+// canViewSessionSummary まとめを見られるのは参加者（ホストを含む）と管理者のみ
+func canViewSessionSummary(summary *models.RoomSessionSummary, room *models.Room, user *models.User) bool {
+	if user == nil {
+		return false
+	}
+	return user.IsAdmin() || user.ID == room.HostUserID || summary.HasParticipant(user.ID)
+}
+
+// SessionSummary 解散した部屋のセッションのまとめページ
+func (h *RoomDetailHandler) SessionSummary(w http.ResponseWriter, r *http.Request) {
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		redirectURL := "/auth/login?redirect=" + url.QueryEscape(r.URL.RequestURI())
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+		return
+	}
+
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効な部屋IDです", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.repo.RoomSession.FindByRoomID(roomID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("セッションのまとめの取得に失敗: %v", err)
+		}
+		http.Error(w, "まとめが見つかりません", http.StatusNotFound)
+		return
+	}
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "まとめが見つかりません", http.StatusNotFound)
+		return
+	}
+
+	// 参加していない部屋のまとめは存在しないものとして扱う
+	if !canViewSessionSummary(summary, room, dbUser) {
+		http.Error(w, "まとめが見つかりません", http.StatusNotFound)
+		return
+	}
+
+	var transcriptURL string
+	if canExportTranscript(room, dbUser, h.repo.Room.HasParticipatedInRoom(roomID, dbUser.ID)) && !transcriptExpired(room, time.Now()) {
+		transcriptURL = fmt.Sprintf("/rooms/%s/transcript?format=txt", room.ID)
+	}
+
+	data := TemplateData{
+		Title:   room.Name + " - セッションのまとめ",
+		HasHero: false,
+		User:    r.Context().Value("user"),
+		PageData: RoomSessionSummaryPageData{
+			Room:          room,
+			Summary:       newSessionSummaryView(summary),
+			TranscriptURL: transcriptURL,
+		},
+	}
+	renderTemplate(w, r, "room_session_summary.tmpl", data)
+}
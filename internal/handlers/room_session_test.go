@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/view"
+)
+
+func TestFormatSessionDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "1分未満"},
+		{59 * time.Second, "1分未満"},
+		{45 * time.Minute, "45分"},
+		{2 * time.Hour, "2時間"},
+		{2*time.Hour + 15*time.Minute + 30*time.Second, "2時間15分"},
+	}
+	for _, tt := range tests {
+		if got := formatSessionDuration(tt.d); got != tt.want {
+			t.Errorf("formatSessionDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestCanViewSessionSummary(t *testing.T) {
+	host, participant, stranger := uuid.New(), uuid.New(), uuid.New()
+	room := &models.Room{HostUserID: host}
+	summary := &models.RoomSessionSummary{
+		Participants: models.JSONB{Data: []models.SessionParticipant{{UserID: host, IsHost: true}, {UserID: participant}}},
+	}
+
+	tests := []struct {
+		name string
+		user *models.User
+		want bool
+	}{
+		{"未ログイン", nil, false},
+		{"ホスト", &models.User{BaseModel: models.BaseModel{ID: host}}, true},
+		{"参加者", &models.User{BaseModel: models.BaseModel{ID: participant}}, true},
+		{"参加していないユーザー", &models.User{BaseModel: models.BaseModel{ID: stranger}}, false},
+		{"管理者", &models.User{BaseModel: models.BaseModel{ID: stranger}, Role: models.RoleAdmin}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canViewSessionSummary(summary, room, tt.user); got != tt.want {
+				t.Errorf("canViewSessionSummary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sampleSessionSummaryView(roomID uuid.UUID) SessionSummaryView {
+	started := time.Date(2026, 8, 20, 12, 0, 0, 0, time.UTC)
+	return newSessionSummaryView(&models.RoomSessionSummary{
+		RoomID:          roomID,
+		StartedAt:       started,
+		EndedAt:         started.Add(95 * time.Minute),
+		DurationSeconds: int64((95 * time.Minute).Seconds()),
+		PeakPlayers:     3,
+		MessageCount:    42,
+		Participants: models.JSONB{Data: []models.SessionParticipant{
+			{UserID: uuid.New(), DisplayName: "ホスト太郎", IsHost: true},
+			{UserID: uuid.New(), DisplayName: "参加者花子"},
+		}},
+	})
+}
+
+func TestRenderSessionSummaryViews(t *testing.T) {
+	chdirRepoRoot(t)
+
+	room := &models.Room{
+		BaseModel:   models.BaseModel{ID: uuid.New()},
+		Name:        "解散した部屋",
+		GameVersion: models.GameVersion{Code: "MHP3"},
+	}
+	session := sampleSessionSummaryView(room.ID)
+	wantCommon := []string{"1時間35分", "42件", "最大"}
+
+	t.Run("まとめページ", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		view.Template(w, "room_session_summary.tmpl", view.Data{
+			Title:    "まとめ",
+			PageData: RoomSessionSummaryPageData{Room: room, Summary: session},
+		})
+		body := w.Body.String()
+		for _, want := range []string{"1時間35分", "42件", "参加者花子", "最大同時人数"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("描画結果に %q が含まれていない", want)
+			}
+		}
+	})
+
+	t.Run("まとめページのチャット保存リンク", func(t *testing.T) {
+		transcriptURL := "/rooms/" + room.ID.String() + "/transcript?format=txt"
+		for _, tt := range []struct {
+			name          string
+			transcriptURL string
+			want          bool
+		}{
+			{"書き出せる", transcriptURL, true},
+			{"書き出せない", "", false},
+		} {
+			w := httptest.NewRecorder()
+			view.Template(w, "room_session_summary.tmpl", view.Data{
+				Title:    "まとめ",
+				PageData: RoomSessionSummaryPageData{Room: room, Summary: session, TranscriptURL: tt.transcriptURL},
+			})
+			if got := strings.Contains(w.Body.String(), "チャットを保存"); got != tt.want {
+				t.Errorf("%s: チャットを保存の表示 = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("プロフィールの部屋タブ", func(t *testing.T) {
+		rooms := sampleRooms(1)
+		rooms[0].Session = &session
+		w := httptest.NewRecorder()
+		if err := renderPartialTemplate(w, "profile_rooms", roomsTabData{Rooms: rooms}); err != nil {
+			t.Fatalf("renderPartialTemplate() error = %v", err)
+		}
+		body := w.Body.String()
+		for _, want := range append(wantCommon, session.URL, "まとめを見る") {
+			if !strings.Contains(body, want) {
+				t.Errorf("描画結果に %q が含まれていない", want)
+			}
+		}
+	})
+
+	t.Run("管理画面の部屋詳細", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		view.Template(w, "admin_room_detail.tmpl", view.Data{
+			Title:    "管理",
+			PageData: adminRoomDetailData{Room: room, Session: &session},
+		})
+		body := w.Body.String()
+		for _, want := range append(wantCommon, "セッションのまとめ", "参加者花子") {
+			if !strings.Contains(body, want) {
+				t.Errorf("描画結果に %q が含まれていない", want)
+			}
+		}
+	})
+}
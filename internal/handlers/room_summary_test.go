@@ -11,6 +11,7 @@ func TestRoomToSummaryStatus(t *testing.T) {
 	now := time.Now()
 	host := models.DismissReasonHost
 	inactive := models.DismissReasonInactive
+	later, earlier := now.Add(3*time.Hour), now.Add(-time.Hour)
 
 	tests := []struct {
 		name          string
@@ -25,6 +26,19 @@ func TestRoomToSummaryStatus(t *testing.T) {
 			wantStatus:    "アクティブ",
 			wantClickable: true,
 		},
+		{
+			name:          "開始前",
+			room:          models.Room{IsActive: true, ScheduledStartAt: &later},
+			wantStatus:    "開始前",
+			wantNote:      true,
+			wantClickable: true,
+		},
+		{
+			name:          "開始予定時刻を過ぎた部屋は募集中",
+			room:          models.Room{IsActive: true, ScheduledStartAt: &earlier},
+			wantStatus:    "アクティブ",
+			wantClickable: true,
+		},
 		{
 			name:       "締め切り",
 			room:       models.Room{IsActive: true, IsClosed: true},
@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+const (
+	// transcriptRetention 解散した部屋のチャット記録を書き出せる期間（解散日時から）
+	transcriptRetention = 30 * 24 * time.Hour
+	// transcriptBatchSize メッセージを一度に読み込む件数
+	transcriptBatchSize = 500
+)
+
+// transcriptLogActions チャット記録に含める入退室のログ
+var transcriptLogActions = []string{"join", "leave", "kick"}
+
+// チャット記録の書き出し形式（?format=）
+const (
+	transcriptFormatJSON = "json"
+	transcriptFormatText = "txt"
+	transcriptFormatCSV  = "csv"
+)
+
+// TranscriptRoom チャット記録の対象の部屋
+type TranscriptRoom struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	DismissedAt *time.Time `json:"dismissed_at,omitempty"`
+	ExportedAt  time.Time  `json:"exported_at"`
+}
+
+// TranscriptEntry チャット記録の1行（メッセージまたは入退室）
+type TranscriptEntry struct {
+	Kind        string     `json:"kind"` // "message" または "event"
+	ID          uuid.UUID  `json:"id"`
+	Time        time.Time  `json:"time"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	UserName    string     `json:"user_name"`
+	MessageType string     `json:"message_type,omitempty"` // chat / image / system
+	Action      string     `json:"action,omitempty"`       // join / leave / kick
+	Text        string     `json:"text"`
+	ImageURL    string     `json:"image_url,omitempty"`
+	ReplyToID   *uuid.UUID `json:"reply_to_id,omitempty"`
+	Edited      bool       `json:"edited,omitempty"`
+	Deleted     bool       `json:"deleted,omitempty"`
+}
+
+// transcriptDisplayName 表示名が未設定ならユーザー名を使う
+func transcriptDisplayName(user *models.User) string {
+	if user == nil {
+		return ""
+	}
+	if user.DisplayName != "" || user.Username == nil {
+		return user.DisplayName
+	}
+	return *user.Username
+}
+
+// newMessageTranscriptEntry メッセージを記録の1行に変換する。削除済みの本文・画像は含めない
+func newMessageTranscriptEntry(message *models.RoomMessage) TranscriptEntry {
+	userID := message.UserID
+	entry := TranscriptEntry{
+		Kind:        "message",
+		ID:          message.ID,
+		Time:        message.CreatedAt,
+		UserID:      &userID,
+		UserName:    transcriptDisplayName(&message.User),
+		MessageType: message.MessageType,
+		ReplyToID:   message.ReplyToID,
+		Edited:      message.EditedAt != nil,
+		Deleted:     message.IsDeleted,
+	}
+	if !message.IsDeleted {
+		entry.Text = message.Message
+		if message.Attachment != nil {
+			entry.ImageURL = message.Attachment.URL
+		}
+	}
+	return entry
+}
+
+// newLogTranscriptEntry 入退室のログを記録の1行に変換する
+func newLogTranscriptEntry(roomLog *models.RoomLog) TranscriptEntry {
+	entry := TranscriptEntry{
+		Kind:     "event",
+		ID:       roomLog.ID,
+		Time:     roomLog.CreatedAt,
+		UserID:   roomLog.UserID,
+		UserName: transcriptDisplayName(roomLog.User),
+		Action:   roomLog.Action,
+	}
+	switch roomLog.Action {
+	case "join":
+		entry.Text = entry.UserName + " さんが参加しました"
+	case "leave":
+		entry.Text = entry.UserName + " さんが退出しました"
+	case "kick":
+		entry.Text = entry.UserName + " さんがホストにより退出しました"
+	}
+	return entry
+}
+
+// transcriptWriter チャット記録を形式ごとに書き出す
+type transcriptWriter interface {
+	Begin(room TranscriptRoom) error
+	Write(entry TranscriptEntry) error
+	End() error
+}
+
+// newTranscriptWriter 形式に対応する writer と Content-Type を返す。未対応の形式なら nil
+func newTranscriptWriter(format string, w io.Writer) (transcriptWriter, string) {
+	switch format {
+	case "", transcriptFormatJSON:
+		return &jsonTranscriptWriter{w: w}, "application/json; charset=utf-8"
+	case transcriptFormatText:
+		return &textTranscriptWriter{w: w}, "text/plain; charset=utf-8"
+	case transcriptFormatCSV:
+		return &csvTranscriptWriter{w: w}, "text/csv; charset=utf-8"
+	}
+	return nil, ""
+}
+
+// jsonTranscriptWriter {"room": {...}, "entries": [...]} の形で1行ずつ書き出す
+type jsonTranscriptWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (t *jsonTranscriptWriter) Begin(room TranscriptRoom) error {
+	header, err := json.Marshal(room)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(t.w, "{\"room\":%s,\"entries\":[", header)
+	return err
+}
+
+func (t *jsonTranscriptWriter) Write(entry TranscriptEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if t.count == 0 {
+		sep = "\n"
+	}
+	t.count++
+	_, err = fmt.Fprintf(t.w, "%s%s", sep, data)
+	return err
+}
+
+func (t *jsonTranscriptWriter) End() error {
+	_, err := io.WriteString(t.w, "\n]}\n")
+	return err
+}
+
+// textTranscriptWriter 「[日時] 名前: 本文」の形のテキストで書き出す
+type textTranscriptWriter struct {
+	w io.Writer
+}
+
+func (t *textTranscriptWriter) Begin(room TranscriptRoom) error {
+	header := fmt.Sprintf("部屋: %s\n書き出し日時: %s\n", room.Name, formatTranscriptTime(room.ExportedAt))
+	if room.DismissedAt != nil {
+		header += fmt.Sprintf("解散日時: %s\n", formatTranscriptTime(*room.DismissedAt))
+	}
+	_, err := io.WriteString(t.w, header+"\n")
+	return err
+}
+
+func (t *textTranscriptWriter) Write(entry TranscriptEntry) error {
+	var line string
+	switch {
+	case entry.Kind == "event" || entry.MessageType == models.RoomMessageTypeSystem:
+		line = "--- " + entry.Text
+	case entry.Deleted:
+		line = entry.UserName + ": （削除されたメッセージ）"
+	default:
+		text := entry.Text
+		if entry.MessageType == models.RoomMessageTypeImage {
+			parts := []string{models.RoomMessageImageQuote}
+			for _, p := range []string{entry.ImageURL, entry.Text} {
+				if p != "" {
+					parts = append(parts, p)
+				}
+			}
+			text = strings.Join(parts, " ")
+		}
+		if entry.Edited {
+			text += "（編集済み）"
+		}
+		line = entry.UserName + ": " + text
+	}
+	_, err := fmt.Fprintf(t.w, "[%s] %s\n", formatTranscriptTime(entry.Time), line)
+	return err
+}
+
+func (t *textTranscriptWriter) End() error {
+	return nil
+}
+
+// csvTranscriptWriter 1行1件のCSVで書き出す。Excelで文字化けしないよう先頭にBOMを付ける
+type csvTranscriptWriter struct {
+	w  io.Writer
+	cw *csv.Writer
+}
+
+func (t *csvTranscriptWriter) Begin(TranscriptRoom) error {
+	if _, err := io.WriteString(t.w, "\uFEFF"); err != nil {
+		return err
+	}
+	t.cw = csv.NewWriter(t.w)
+	return t.cw.Write([]string{"time", "kind", "user_id", "user_name", "message_type", "action", "text", "image_url", "reply_to_id", "edited", "deleted"})
+}
+
+func (t *csvTranscriptWriter) Write(entry TranscriptEntry) error {
+	var userID, replyToID string
+	if entry.UserID != nil {
+		userID = entry.UserID.String()
+	}
+	if entry.ReplyToID != nil {
+		replyToID = entry.ReplyToID.String()
+	}
+	return t.cw.Write([]string{
+		entry.Time.In(adminJST).Format(time.RFC3339),
+		entry.Kind,
+		userID,
+		entry.UserName,
+		entry.MessageType,
+		entry.Action,
+		entry.Text,
+		entry.ImageURL,
+		replyToID,
+		strconv.FormatBool(entry.Edited),
+		strconv.FormatBool(entry.Deleted),
+	})
+}
+
+func (t *csvTranscriptWriter) End() error {
+	t.cw.Flush()
+	return t.cw.Error()
+}
+
+// formatTranscriptTime テキスト形式の日時表記（JST、秒まで）
+func formatTranscriptTime(t time.Time) string {
+	return t.In(adminJST).Format("2006-01-02 15:04:05")
+}
+
+// writeTranscript メッセージと入退室のログを時刻順に混ぜて書き出す。
+// logs は古い順に並んでいること。メッセージは eachBatch から古い順に受け取る
+func writeTranscript(tw transcriptWriter, room TranscriptRoom, logs []models.RoomLog, eachBatch func(func([]models.RoomMessage) error) error) error {
+	if err := tw.Begin(room); err != nil {
+		return err
+	}
+
+	next := 0
+	flushLogsBefore := func(t *time.Time) error {
+		for ; next < len(logs); next++ {
+			if t != nil && !logs[next].CreatedAt.Before(*t) {
+				return nil
+			}
+			if err := tw.Write(newLogTranscriptEntry(&logs[next])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := eachBatch(func(messages []models.RoomMessage) error {
+		for i := range messages {
+			if err := flushLogsBefore(&messages[i].CreatedAt); err != nil {
+				return err
+			}
+			if err := tw.Write(newMessageTranscriptEntry(&messages[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := flushLogsBefore(nil); err != nil {
+		return err
+	}
+	return tw.End()
+}
+
+// canExportTranscript チャット記録を書き出せるのはホスト・参加者（キックされた人を除く）・管理者のみ
+func canExportTranscript(room *models.Room, user *models.User, participated bool) bool {
+	if user == nil {
+		return false
+	}
+	return user.IsAdmin() || user.ID == room.HostUserID || participated
+}
+
+// transcriptExpired 解散から保持期間を過ぎた部屋か
+func transcriptExpired(room *models.Room, now time.Time) bool {
+	if room.IsActive || room.DismissedAt == nil {
+		return false
+	}
+	return now.Sub(*room.DismissedAt) > transcriptRetention
+}
+
+// ExportTranscript 部屋のチャット記録（メッセージと入退室）を JSON・テキスト・CSV で書き出す
+func (h *RoomMessageHandler) ExportTranscript(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効な部屋IDです", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := middleware.GetDBUserFromContext(r.Context())
+	if !ok || user == nil {
+		http.Error(w, "認証が必要です", http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	tw, contentType := newTranscriptWriter(format, w)
+	if tw == nil {
+		http.Error(w, "対応していない形式です（json, txt, csv のいずれかを指定してください）", http.StatusBadRequest)
+		return
+	}
+	if format == "" {
+		format = transcriptFormatJSON
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	// 参加していない部屋の記録は存在しないものとして扱う
+	if !canExportTranscript(room, user, h.repo.Room.HasParticipatedInRoom(roomID, user.ID)) {
+		http.Error(w, "部屋が見つかりません", http.StatusNotFound)
+		return
+	}
+	now := time.Now()
+	if transcriptExpired(room, now) {
+		http.Error(w, "保存期間を過ぎたため、チャット記録は書き出せません", http.StatusGone)
+		return
+	}
+
+	logs, err := h.repo.RoomLog.ListRoomLogs(roomID, transcriptLogActions)
+	if err != nil {
+		log.Printf("チャット記録の入退室ログ取得に失敗: %v", err)
+		http.Error(w, "チャット記録の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("room-%s-%s.%s", roomID.String()[:8], now.In(adminJST).Format("20060102-1504"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "no-store")
+
+	transcriptRoom := TranscriptRoom{ID: room.ID, Name: room.Name, DismissedAt: room.DismissedAt, ExportedAt: now}
+	err = writeTranscript(tw, transcriptRoom, logs, func(fn func([]models.RoomMessage) error) error {
+		return h.repo.RoomMessage.EachMessageBatch(roomID, transcriptBatchSize, fn)
+	})
+	if err != nil {
+		// ヘッダーは送信済みのため、ログに残すだけにする
+		log.Printf("チャット記録の書き出しに失敗: room=%s: %v", roomID, err)
+	}
+}
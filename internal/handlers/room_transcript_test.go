@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+// sampleTranscript 入退室とメッセージが交互に並ぶ記録
+func sampleTranscript() (TranscriptRoom, []models.RoomLog, [][]models.RoomMessage) {
+	base := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	username := "hunter_b"
+	alice := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, DisplayName: "ハンターA"}
+	bob := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: &username}
+	edited := base.Add(3 * time.Minute)
+
+	room := TranscriptRoom{ID: uuid.New(), Name: "定期狩猟会", ExportedAt: base.Add(time.Hour)}
+	logs := []models.RoomLog{
+		{BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: base.Add(time.Minute)}, UserID: &bob.ID, User: &bob, Action: "join"},
+		{BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: base.Add(5 * time.Minute)}, UserID: &bob.ID, User: &bob, Action: "leave"},
+	}
+	batches := [][]models.RoomMessage{
+		{
+			{BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: base}, UserID: alice.ID, User: alice, Message: "よろしく", MessageType: models.RoomMessageTypeChat},
+			{BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: base.Add(2 * time.Minute)}, UserID: bob.ID, User: bob, Message: "集会所2です", MessageType: models.RoomMessageTypeChat, EditedAt: &edited},
+		},
+		{
+			{BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: base.Add(3 * time.Minute)}, UserID: alice.ID, User: alice, Message: "荒らし", MessageType: models.RoomMessageTypeChat, IsDeleted: true},
+			{
+				BaseModel: models.BaseModel{ID: uuid.New(), CreatedAt: base.Add(4 * time.Minute)}, UserID: alice.ID, User: alice, Message: "素材", MessageType: models.RoomMessageTypeImage,
+				Attachment: &models.RoomMessageAttachment{URL: "https://example.test/chat/a.png"},
+			},
+		},
+	}
+	return room, logs, batches
+}
+
+func renderSampleTranscript(t *testing.T, format string) string {
+	t.Helper()
+	room, logs, batches := sampleTranscript()
+	buf := bytes.NewBuffer(nil)
+	tw, _ := newTranscriptWriter(format, buf)
+	if tw == nil {
+		t.Fatalf("newTranscriptWriter(%q) = nil", format)
+	}
+	err := writeTranscript(tw, room, logs, func(fn func([]models.RoomMessage) error) error {
+		for _, batch := range batches {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("writeTranscript() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriteTranscriptJSON(t *testing.T) {
+	var got struct {
+		Room    TranscriptRoom    `json:"room"`
+		Entries []TranscriptEntry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(renderSampleTranscript(t, "")), &got); err != nil {
+		t.Fatalf("JSONとして読めない: %v", err)
+	}
+	if got.Room.Name != "定期狩猟会" {
+		t.Errorf("room.name = %q", got.Room.Name)
+	}
+
+	want := []struct {
+		kind, action, userName, text, imageURL string
+		deleted                                bool
+	}{
+		{"message", "", "ハンターA", "よろしく", "", false},
+		{"event", "join", "hunter_b", "hunter_b さんが参加しました", "", false},
+		{"message", "", "hunter_b", "集会所2です", "", false},
+		{"message", "", "ハンターA", "", "", true},
+		{"message", "", "ハンターA", "素材", "https://example.test/chat/a.png", false},
+		{"event", "leave", "hunter_b", "hunter_b さんが退出しました", "", false},
+	}
+	if len(got.Entries) != len(want) {
+		t.Fatalf("件数 = %d, want %d: %+v", len(got.Entries), len(want), got.Entries)
+	}
+	for i, w := range want {
+		e := got.Entries[i]
+		if e.Kind != w.kind || e.Action != w.action || e.UserName != w.userName || e.Text != w.text || e.ImageURL != w.imageURL || e.Deleted != w.deleted {
+			t.Errorf("entries[%d] = %+v, want %+v", i, e, w)
+		}
+	}
+}
+
+func TestWriteTranscriptText(t *testing.T) {
+	body := renderSampleTranscript(t, transcriptFormatText)
+	for _, want := range []string{
+		"部屋: 定期狩猟会",
+		"[2026-09-01 21:00:00] ハンターA: よろしく",
+		"[2026-09-01 21:01:00] --- hunter_b さんが参加しました",
+		"hunter_b: 集会所2です（編集済み）",
+		"ハンターA: （削除されたメッセージ）",
+		"ハンターA: [画像] https://example.test/chat/a.png 素材",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("テキストに %q が含まれていない:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "荒らし") {
+		t.Error("削除済みメッセージの本文が書き出されている")
+	}
+}
+
+func TestWriteTranscriptCSV(t *testing.T) {
+	body := renderSampleTranscript(t, transcriptFormatCSV)
+	if !strings.HasPrefix(body, "\uFEFF") {
+		t.Error("先頭にBOMがない")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(body, "\uFEFF"))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSVとして読めない: %v", err)
+	}
+	if len(records) != 7 {
+		t.Fatalf("行数 = %d, want 7（見出し+6件）", len(records))
+	}
+	if records[0][0] != "time" || records[1][0] != "2026-09-01T21:00:00+09:00" {
+		t.Errorf("見出し・日時 = %v, %v", records[0], records[1])
+	}
+	if records[2][1] != "event" || records[2][5] != "join" {
+		t.Errorf("2件目が入室になっていない: %v", records[2])
+	}
+}
+
+func TestNewTranscriptWriterUnknownFormat(t *testing.T) {
+	if tw, _ := newTranscriptWriter("xml", bytes.NewBuffer(nil)); tw != nil {
+		t.Error("未対応の形式で writer が返った")
+	}
+}
+
+func TestCanExportTranscript(t *testing.T) {
+	host, member := uuid.New(), uuid.New()
+	room := &models.Room{HostUserID: host}
+
+	tests := []struct {
+		name         string
+		user         *models.User
+		participated bool
+		want         bool
+	}{
+		{"未ログイン", nil, false, false},
+		{"ホスト", &models.User{BaseModel: models.BaseModel{ID: host}}, false, true},
+		{"参加者", &models.User{BaseModel: models.BaseModel{ID: member}}, true, true},
+		{"参加していない（キックされた）ユーザー", &models.User{BaseModel: models.BaseModel{ID: member}}, false, false},
+		{"管理者", &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.RoleAdmin}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canExportTranscript(room, tt.user, tt.participated); got != tt.want {
+				t.Errorf("canExportTranscript() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranscriptExpired(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	dismissedAt := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	tests := []struct {
+		name string
+		room *models.Room
+		want bool
+	}{
+		{"募集中の部屋", &models.Room{IsActive: true}, false},
+		{"保存期間内", &models.Room{DismissedAt: dismissedAt(transcriptRetention - time.Hour)}, false},
+		{"保存期間切れ", &models.Room{DismissedAt: dismissedAt(transcriptRetention + time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transcriptExpired(tt.room, now); got != tt.want {
+				t.Errorf("transcriptExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
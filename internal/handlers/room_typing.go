@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+)
+
+// 入力中イベントを送る最短の間隔。クライアントは入力のたびに呼んでよく、間引きはサーバーで行う
+const typingInterval = 3 * time.Second
+
+// TypingData 入力中イベント（typing）のデータ
+type TypingData struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+}
+
+// typingThrottle 部屋×ユーザーごとに最後に入力中イベントを送った時刻を覚えておき、間引く
+type typingThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	lastSent map[typingKey]time.Time
+}
+
+type typingKey struct {
+	roomID uuid.UUID
+	userID uuid.UUID
+}
+
+func newTypingThrottle(interval time.Duration) *typingThrottle {
+	return &typingThrottle{interval: interval, lastSent: make(map[typingKey]time.Time)}
+}
+
+// allow now の時点で入力中イベントを送ってよいか。送ってよければ送信時刻として記録する
+func (t *typingThrottle) allow(roomID, userID uuid.UUID, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := typingKey{roomID: roomID, userID: userID}
+	if last, ok := t.lastSent[key]; ok && now.Sub(last) < t.interval {
+		return false
+	}
+	t.lastSent[key] = now
+
+	// 古い記録を掃除する（間隔を過ぎた記録は間引きに使わない）
+	for k, last := range t.lastSent {
+		if now.Sub(last) >= t.interval {
+			delete(t.lastSent, k)
+		}
+	}
+	return true
+}
+
+// presenceSnapshot 接続時に送る presence イベント。selfID が含まれていなければ加える
+func presenceSnapshot(online []uuid.UUID, selfID uuid.UUID) sse.Event {
+	userIDs := online
+	found := false
+	for _, id := range online {
+		if id == selfID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		userIDs = append(userIDs, selfID)
+	}
+	return sse.Event{Type: "presence", Data: sse.PresenceSnapshot{UserIDs: userIDs}}
+}
+
+// Typing はメッセージを入力中であることを、本人以外の部屋のメンバーに知らせる
+func (h *RoomMessageHandler) Typing(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効な部屋IDです", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := middleware.GetDBUserFromContext(r.Context())
+	if !ok || user == nil {
+		http.Error(w, "認証が必要です", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.repo.Room.IsUserJoinedRoom(roomID, user.ID) {
+		http.Error(w, "部屋のメンバーではありません", http.StatusForbidden)
+		return
+	}
+
+	if h.typing.allow(roomID, user.ID, time.Now()) {
+		displayName := user.DisplayName
+		if displayName == "" && user.Username != nil {
+			displayName = *user.Username
+		}
+		h.hub.BroadcastToOthers(roomID, user.ID, sse.Event{
+			Type: "typing",
+			Data: TypingData{UserID: user.ID, DisplayName: displayName},
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
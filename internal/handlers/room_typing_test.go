@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+)
+
+func TestTypingThrottle(t *testing.T) {
+	throttle := newTypingThrottle(3 * time.Second)
+	roomID, userID, otherID := uuid.New(), uuid.New(), uuid.New()
+	start := time.Now()
+
+	steps := []struct {
+		name   string
+		userID uuid.UUID
+		at     time.Duration
+		want   bool
+	}{
+		{name: "最初の入力は送る", userID: userID, at: 0, want: true},
+		{name: "間隔内の入力は間引く", userID: userID, at: time.Second, want: false},
+		{name: "別のユーザーは間引かない", userID: otherID, at: time.Second, want: true},
+		{name: "間隔を過ぎたら再び送る", userID: userID, at: 3 * time.Second, want: true},
+		{name: "送った時刻から数え直す", userID: userID, at: 5 * time.Second, want: false},
+	}
+	for _, step := range steps {
+		if got := throttle.allow(roomID, step.userID, start.Add(step.at)); got != step.want {
+			t.Errorf("%s: allow() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestPresenceSnapshot(t *testing.T) {
+	selfID, otherID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name   string
+		online []uuid.UUID
+		want   int
+	}{
+		{name: "自分が未登録なら加える", online: []uuid.UUID{otherID}, want: 2},
+		{name: "自分が登録済みなら重複させない", online: []uuid.UUID{otherID, selfID}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := presenceSnapshot(tt.online, selfID)
+			data, ok := event.Data.(sse.PresenceSnapshot)
+			if event.Type != "presence" || !ok {
+				t.Fatalf("presenceSnapshot() = %+v", event)
+			}
+			if len(data.UserIDs) != tt.want {
+				t.Errorf("UserIDs = %v, want %d 人", data.UserIDs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+)
+
+// viewerUserID ログイン中のユーザーのIDを返す。未ログインなら nil
+func viewerUserID(r *http.Request) *uuid.UUID {
+	if dbUser, ok := middleware.GetDBUserFromContext(r.Context()); ok && dbUser != nil {
+		return &dbUser.ID
+	}
+	return nil
+}
+
+// canViewRoom 部屋の公開範囲に照らして viewer が部屋を開けるかどうか。
+// 公開・限定公開の部屋は誰でも開ける。フォロワー限定の部屋はホスト・参加中のメンバー・フォロー承認済みのユーザーのみ
+func (b *BaseHandler) canViewRoom(room *models.Room, viewer *models.User) bool {
+	if !room.IsFollowersOnly() {
+		return true
+	}
+	if viewer == nil {
+		return false
+	}
+	if viewer.ID == room.HostUserID || b.repo.Room.IsUserJoinedRoom(room.ID, viewer.ID) {
+		return true
+	}
+
+	follow, err := b.repo.UserFollow.GetFollow(viewer.ID, room.HostUserID)
+	if err != nil {
+		log.Printf("フォロー関係の確認エラー: %v", err)
+		return false
+	}
+	return follow != nil && follow.Status == models.FollowStatusAccepted
+}
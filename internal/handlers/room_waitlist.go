@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/sse"
+	"mhp-rooms/internal/middleware"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// 空きが出たときにキャンセル待ちの先頭へ席を確保しておく時間
+const waitlistReservationDuration = 5 * time.Minute
+
+// RoomWaitlistStatus 閲覧中のユーザーから見たキャンセル待ちの状況
+type RoomWaitlistStatus struct {
+	Status         string     `json:"status"`           // none / waiting / offered
+	Position       int        `json:"position"`         // 待ち順（1始まり。待機中のみ）
+	OfferExpiresAt *time.Time `json:"offer_expires_at"` // 席の確保期限（確保中のみ）
+	WaitingCount   int        `json:"waiting_count"`    // 空き待ちの人数
+	Full           bool       `json:"full"`             // 満員、または残りの席が他の待機者に確保されている
+}
+
+// loadRoomWaitlistStatus userID のキャンセル待ちの状況を取得する。取得に失敗した項目は空のまま返す
+func loadRoomWaitlistStatus(repo *repository.Repository, room *models.Room, userID uuid.UUID) RoomWaitlistStatus {
+	status := RoomWaitlistStatus{Status: "none", Full: room.IsFull()}
+
+	if waiting, err := repo.RoomWaitlist.GetWaitingEntries(room.ID); err == nil {
+		status.WaitingCount = len(waiting)
+	}
+	if reserved, err := repo.RoomWaitlist.CountReservedSeats(room.ID, time.Now()); err == nil {
+		status.Full = room.CurrentPlayers+reserved >= room.MaxPlayers
+	}
+
+	if userID == uuid.Nil {
+		return status
+	}
+	entry, position, err := repo.RoomWaitlist.FindActiveEntry(room.ID, userID)
+	if err != nil || entry == nil {
+		return status
+	}
+	status.Status = entry.Status
+	status.Position = position
+	status.OfferExpiresAt = entry.OfferExpiresAt
+	return status
+}
+
+// JoinWaitlist 満員の部屋のキャンセル待ちに登録する
+func (h *RoomHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "ルームが見つかりません", http.StatusNotFound)
+		return
+	}
+
+	if !h.canViewRoom(room, dbUser) {
+		respondWithJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error":   "FOLLOWERS_ONLY",
+			"message": "この部屋はホストのフォロワーのみ参加できます",
+		})
+		return
+	}
+
+	// ホストにブロックされているユーザーは参加できないため、キャンセル待ちもできない
+	isBlockedByHost, _, blockErr := h.repo.UserBlock.CheckBlockRelationship(dbUser.ID, room.HostUserID)
+	if blockErr != nil {
+		log.Printf("ブロック関係の確認エラー: %v", blockErr)
+		http.Error(w, "ブロック関係の確認に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if isBlockedByHost {
+		respondWithJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error":   "BLOCKED_BY_HOST",
+			"message": "このルームには参加できません",
+		})
+		return
+	}
+
+	if _, err := h.repo.RoomWaitlist.JoinWaitlist(roomID, dbUser.ID); err != nil {
+		for code, status := range map[string]int{
+			"NOT_FULL":       http.StatusConflict,
+			"ALREADY_JOINED": http.StatusConflict,
+			"KICKED":         http.StatusForbidden,
+			"HOST_BANNED":    http.StatusForbidden,
+		} {
+			if strings.HasPrefix(err.Error(), code+":") {
+				respondWithJSON(w, status, map[string]interface{}{
+					"error":   code,
+					"message": strings.TrimPrefix(err.Error(), code+":"),
+				})
+				return
+			}
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 確保期限切れなどで既に空きがあれば、その場で先頭に案内する
+	h.promoteWaitlist(roomID)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message":  "キャンセル待ちに登録しました",
+		"waitlist": loadRoomWaitlistStatus(h.repo, room, dbUser.ID),
+	})
+}
+
+// LeaveWaitlist キャンセル待ちを取り消す。確保中の席は次の待機者に回す
+func (h *RoomHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.repo.RoomWaitlist.LeaveWaitlist(roomID, dbUser.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.promoteWaitlist(roomID)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "キャンセル待ちを取り消しました",
+	})
+}
+
+// GetWaitlistStatus 自分のキャンセル待ちの状況（待ち順・確保期限）を返す
+func (h *RoomHandler) GetWaitlistStatus(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "無効なルームIDです", http.StatusBadRequest)
+		return
+	}
+
+	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
+	if !exists || dbUser == nil {
+		http.Error(w, "認証されていないか、ユーザー情報が見つかりません", http.StatusUnauthorized)
+		return
+	}
+
+	// タイマーが動かなかった場合に備え、期限切れの確保はここでも次の待機者に回す
+	h.promoteWaitlist(roomID)
+
+	room, err := h.repo.Room.FindRoomByID(roomID)
+	if err != nil {
+		http.Error(w, "ルームが見つかりません", http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, loadRoomWaitlistStatus(h.repo, room, dbUser.ID))
+}
+
+// promoteWaitlist 空いた席をキャンセル待ちの先頭に確保し、本人にお知らせと SSE イベントで知らせる。
+// 退出・キック・定員変更など席が空く可能性がある操作の後に呼ぶ。失敗してもメイン処理には影響させない
+func (h *RoomHandler) promoteWaitlist(roomID uuid.UUID) {
+	offered, expired, err := h.repo.RoomWaitlist.PromoteWaitlist(roomID, time.Now(), waitlistReservationDuration)
+	if err != nil {
+		log.Printf("キャンセル待ちの繰り上げに失敗: %v", err)
+		return
+	}
+
+	if len(offered) > 0 {
+		room, err := h.repo.Room.FindRoomByID(roomID)
+		if err != nil {
+			log.Printf("部屋情報の取得に失敗: %v", err)
+		} else {
+			for _, entry := range offered {
+				if err := h.notificationService.NotifyWaitlistSeatOffered(entry.UserID, room, *entry.OfferExpiresAt); err != nil {
+					log.Printf("キャンセル待ちのお知らせ作成に失敗: %v", err)
+				}
+			}
+		}
+
+		// 確保期限までに参加しなければ次の待機者に回す
+		time.AfterFunc(waitlistReservationDuration+time.Second, func() {
+			h.promoteWaitlist(roomID)
+		})
+	}
+
+	if h.hub == nil {
+		return
+	}
+
+	for _, entry := range expired {
+		h.hub.SendToUser(roomID, entry.UserID, sse.Event{
+			ID:   uuid.New().String(),
+			Type: "waitlist_offer_expired",
+			Data: map[string]interface{}{
+				"message": "確保期限が過ぎたため、キャンセル待ちを解除しました",
+			},
+		})
+	}
+	for _, entry := range offered {
+		h.hub.SendToUser(roomID, entry.UserID, sse.Event{
+			ID:   uuid.New().String(),
+			Type: "waitlist_offer",
+			Data: map[string]interface{}{
+				"offer_expires_at": entry.OfferExpiresAt,
+				"message":          "空きが出ました。時間内に参加してください",
+			},
+		})
+	}
+
+	// 待ち順は繰り上がるたびに変わるため、待機者それぞれに最新の順番を送る
+	waiting, err := h.repo.RoomWaitlist.GetWaitingEntries(roomID)
+	if err != nil {
+		log.Printf("キャンセル待ちの取得に失敗: %v", err)
+		return
+	}
+	for i, entry := range waiting {
+		h.hub.SendToUser(roomID, entry.UserID, sse.Event{
+			ID:   uuid.New().String(),
+			Type: "waitlist_update",
+			Data: map[string]interface{}{
+				"status":        models.WaitlistStatusWaiting,
+				"position":      i + 1,
+				"waiting_count": len(waiting),
+			},
+		})
+	}
+	h.hub.BroadcastToRoom(roomID, sse.Event{
+		ID:   uuid.New().String(),
+		Type: "waitlist_update",
+		Data: map[string]interface{}{
+			"waiting_count": len(waiting),
+		},
+	})
+}
@@ -27,6 +27,9 @@ type RoomHandler struct {
 	hub                 *sse.Hub
 	activityService     *services.ActivityService
 	notificationService *services.NotificationService
+	ngWordFilter        *services.NGWordFilter
+	chatAttachments     *services.ChatAttachmentService
+	readyChecks         *readyCheckStore
 }
 
 func NewRoomHandler(repo *repository.Repository, hub *sse.Hub) *RoomHandler {
@@ -37,19 +40,43 @@ func NewRoomHandler(repo *repository.Repository, hub *sse.Hub) *RoomHandler {
 		hub:                 hub,
 		activityService:     services.NewActivityService(repo),
 		notificationService: services.NewNotificationService(repo),
+		ngWordFilter:        services.NewNGWordFilter(repo),
+		chatAttachments:     services.NewChatAttachmentService(repo, nil),
+		readyChecks:         newReadyCheckStore(),
 	}
 }
 
+// SetChatAttachments は画像添付の保存先を設定したサービスを設定
+func (h *RoomHandler) SetChatAttachments(chatAttachments *services.ChatAttachmentService) {
+	h.chatAttachments = chatAttachments
+}
+
 type RoomsPageData struct {
 	Rooms        []interface{}        `json:"rooms"`
 	GameVersions []models.GameVersion `json:"game_versions"`
 	Filter       string               `json:"filter"`
+	Search       RoomSearchQuery      `json:"search"`
 	Total        int64                `json:"total"`
 	CurrentPage  int                  `json:"current_page"`
 	TotalPages   int                  `json:"total_pages"`
 	PerPage      int                  `json:"per_page"`
 }
 
+// PageURL 検索条件を保ったまま指定ページを表示するURL
+func (d RoomsPageData) PageURL(page int) string {
+	values := d.Search.Values()
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if d.PerPage != 20 {
+		values.Set("per_page", strconv.Itoa(d.PerPage))
+	}
+	if len(values) == 0 {
+		return "/rooms"
+	}
+	return "/rooms?" + values.Encode()
+}
+
 // RecentActivityFeedData は部屋一覧に遅延表示する公開活動フィードです。
 type RecentActivityFeedData struct {
 	Activities      []RecentActivityItem
@@ -104,7 +131,7 @@ func startOfCurrentWeek(now time.Time) time.Time {
 }
 
 func (h *RoomHandler) Rooms(w http.ResponseWriter, r *http.Request) {
-	filter := r.URL.Query().Get("game_version")
+	search := parseRoomSearchQuery(r)
 	page := parsePageParam(r)
 	perPage := 20
 
@@ -116,6 +143,7 @@ func (h *RoomHandler) Rooms(w http.ResponseWriter, r *http.Request) {
 	}
 
 	offset := (page - 1) * perPage
+	now := time.Now()
 
 	gameVersions, err := h.repo.GetActiveGameVersions()
 	if err != nil {
@@ -123,85 +151,106 @@ func (h *RoomHandler) Rooms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// ゲームバージョンフィルタの処理
-	var gameVersionID *uuid.UUID
-	if filter != "" {
-		gv, err := h.repo.GameVersion.FindGameVersionByCode(filter)
-		if err == nil {
-			gameVersionID = &gv.ID
-		}
-	}
+	params := search.Params(h.resolveGameVersionID(search.GameVersion), perPage, offset)
 
 	// 認証されたユーザーの場合、最適化されたメソッドを使用
 	var enhancedRooms []interface{}
 	dbUser, isAuthenticated := middleware.GetDBUserFromContext(r.Context())
+	// フォロワー限定の部屋は閲覧者に応じて掲載する
+	params.ViewerID = viewerUserID(r)
 
 	if isAuthenticated && dbUser != nil {
 		// パフォーマンス最適化: 1つのクエリで参加状態を取得
-		roomsWithJoinStatus, err := h.repo.Room.GetActiveRoomsWithJoinStatus(&dbUser.ID, gameVersionID, perPage, offset)
+		roomsWithJoinStatus, err := h.repo.Room.GetActiveRoomsWithJoinStatus(&dbUser.ID, params)
 		if err != nil {
 			http.Error(w, "ルーム一覧の取得に失敗しました", http.StatusInternalServerError)
 			return
 		}
 
+		roomIDs := make([]uuid.UUID, 0, len(roomsWithJoinStatus))
+		for _, roomWithStatus := range roomsWithJoinStatus {
+			roomIDs = append(roomIDs, roomWithStatus.Room.ID)
+		}
+		memberWeapons := h.loadMemberWeapons(roomIDs)
+
 		for _, roomWithStatus := range roomsWithJoinStatus {
 			roomData := map[string]interface{}{
-				"id":               roomWithStatus.Room.ID,
-				"room_code":        roomWithStatus.Room.RoomCode,
-				"name":             roomWithStatus.Room.Name,
-				"description":      roomWithStatus.Room.GetDescription(),
-				"game_version_id":  roomWithStatus.Room.GameVersionID,
-				"game_version":     roomWithStatus.Room.GameVersion,
-				"host_user_id":     roomWithStatus.Room.HostUserID,
-				"host":             roomWithStatus.Room.Host,
-				"max_players":      roomWithStatus.Room.MaxPlayers,
-				"current_players":  roomWithStatus.Room.CurrentPlayers,
-				"target_monster":   roomWithStatus.Room.GetTargetMonster(),
-				"rank_requirement": roomWithStatus.Room.GetRankRequirement(),
-				"is_active":        roomWithStatus.Room.IsActive,
-				"is_closed":        roomWithStatus.Room.IsClosed,
-				"created_at":       roomWithStatus.Room.CreatedAt,
-				"updated_at":       roomWithStatus.Room.UpdatedAt,
-				"has_password":     roomWithStatus.Room.HasPassword(),
-				"is_joined":        roomWithStatus.IsJoined,
+				"id":                 roomWithStatus.Room.ID,
+				"room_code":          roomWithStatus.Room.RoomCode,
+				"name":               roomWithStatus.Room.Name,
+				"description":        roomWithStatus.Room.GetDescription(),
+				"game_version_id":    roomWithStatus.Room.GameVersionID,
+				"game_version":       roomWithStatus.Room.GameVersion,
+				"host_user_id":       roomWithStatus.Room.HostUserID,
+				"host":               roomWithStatus.Room.Host,
+				"max_players":        roomWithStatus.Room.MaxPlayers,
+				"current_players":    roomWithStatus.Room.CurrentPlayers,
+				"target_monster":     roomWithStatus.Room.GetTargetMonster(),
+				"rank_requirement":   roomWithStatus.Room.GetRankRequirement(),
+				"is_active":          roomWithStatus.Room.IsActive,
+				"is_closed":          roomWithStatus.Room.IsClosed,
+				"created_at":         roomWithStatus.Room.CreatedAt,
+				"updated_at":         roomWithStatus.Room.UpdatedAt,
+				"has_password":       roomWithStatus.Room.HasPassword(),
+				"scheduled_start_at": roomWithStatus.Room.ScheduledStartAt,
+				"scheduled_end_at":   roomWithStatus.Room.ScheduledEndAt,
+				"is_upcoming":        roomWithStatus.Room.IsUpcoming(now),
+				"visibility":         roomWithStatus.Room.GetVisibility(),
+				"requires_approval":  roomWithStatus.Room.RequiresApproval,
+				"wanted_weapons":     roomWithStatus.Room.GetWantedWeapons(),
+				"weapon_slots":       roomWithStatus.Room.WeaponSlots(memberWeapons[roomWithStatus.Room.ID]),
+				"is_joined":          roomWithStatus.IsJoined,
 			}
 			enhancedRooms = append(enhancedRooms, roomData)
 		}
 	} else {
 		// 未認証ユーザーの場合は従来の方法
-		rooms, err := h.repo.Room.GetActiveRooms(gameVersionID, perPage, offset)
+		rooms, err := h.repo.Room.GetActiveRooms(params)
 		if err != nil {
 			http.Error(w, "ルーム一覧の取得に失敗しました", http.StatusInternalServerError)
 			return
 		}
 
+		roomIDs := make([]uuid.UUID, 0, len(rooms))
+		for _, room := range rooms {
+			roomIDs = append(roomIDs, room.ID)
+		}
+		memberWeapons := h.loadMemberWeapons(roomIDs)
+
 		for _, room := range rooms {
 			roomData := map[string]interface{}{
-				"id":               room.ID,
-				"room_code":        room.RoomCode,
-				"name":             room.Name,
-				"description":      room.GetDescription(),
-				"game_version_id":  room.GameVersionID,
-				"game_version":     room.GameVersion,
-				"host_user_id":     room.HostUserID,
-				"host":             room.Host,
-				"max_players":      room.MaxPlayers,
-				"current_players":  room.CurrentPlayers,
-				"target_monster":   room.GetTargetMonster(),
-				"rank_requirement": room.GetRankRequirement(),
-				"is_active":        room.IsActive,
-				"is_closed":        room.IsClosed,
-				"created_at":       room.CreatedAt,
-				"updated_at":       room.UpdatedAt,
-				"has_password":     room.HasPassword(),
-				"is_joined":        false,
+				"id":                 room.ID,
+				"room_code":          room.RoomCode,
+				"name":               room.Name,
+				"description":        room.GetDescription(),
+				"game_version_id":    room.GameVersionID,
+				"game_version":       room.GameVersion,
+				"host_user_id":       room.HostUserID,
+				"host":               room.Host,
+				"max_players":        room.MaxPlayers,
+				"current_players":    room.CurrentPlayers,
+				"target_monster":     room.GetTargetMonster(),
+				"rank_requirement":   room.GetRankRequirement(),
+				"is_active":          room.IsActive,
+				"is_closed":          room.IsClosed,
+				"created_at":         room.CreatedAt,
+				"updated_at":         room.UpdatedAt,
+				"has_password":       room.HasPassword(),
+				"scheduled_start_at": room.ScheduledStartAt,
+				"scheduled_end_at":   room.ScheduledEndAt,
+				"is_upcoming":        room.IsUpcoming(now),
+				"visibility":         room.GetVisibility(),
+				"requires_approval":  room.RequiresApproval,
+				"wanted_weapons":     room.GetWantedWeapons(),
+				"weapon_slots":       room.WeaponSlots(memberWeapons[room.ID]),
+				"is_joined":          false,
 			}
 			enhancedRooms = append(enhancedRooms, roomData)
 		}
 	}
 
 	// 総件数を取得
-	total, err := h.repo.Room.CountActiveRooms(gameVersionID)
+	total, err := h.repo.Room.CountActiveRooms(params)
 	if err != nil {
 		http.Error(w, "部屋数の取得に失敗しました", http.StatusInternalServerError)
 		return
@@ -216,7 +265,8 @@ func (h *RoomHandler) Rooms(w http.ResponseWriter, r *http.Request) {
 	pageData := RoomsPageData{
 		Rooms:        enhancedRooms,
 		GameVersions: gameVersions,
-		Filter:       filter,
+		Filter:       search.GameVersion,
+		Search:       search,
 		Total:        total,
 		CurrentPage:  page,
 		TotalPages:   totalPages,
@@ -232,13 +282,74 @@ func (h *RoomHandler) Rooms(w http.ResponseWriter, r *http.Request) {
 }
 
 type CreateRoomRequest struct {
-	Name            string `json:"name"`
-	Description     string `json:"description"`
-	GameVersionID   string `json:"game_version_id"`
-	MaxPlayers      int    `json:"max_players"`
-	Password        string `json:"password"`
-	TargetMonster   string `json:"target_monster"`
-	RankRequirement string `json:"rank_requirement"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	GameVersionID    string   `json:"game_version_id"`
+	MaxPlayers       int      `json:"max_players"`
+	Password         string   `json:"password"`
+	TargetMonster    string   `json:"target_monster"` // 図鑑に無いモンスターは自由入力のまま保存する
+	MonsterID        string   `json:"monster_id"`     // 図鑑から選んだ場合のモンスターID（任意）
+	QuestID          string   `json:"quest_id"`       // 図鑑から選んだ場合のクエストID（任意）
+	RankRequirement  string   `json:"rank_requirement"`
+	ScheduledStartAt string   `json:"scheduled_start_at"` // RFC3339。空の場合は作成直後から募集中
+	ScheduledEndAt   string   `json:"scheduled_end_at"`   // RFC3339。開始予定時刻の指定時のみ有効
+	Visibility       string   `json:"visibility"`         // public / followers / unlisted。空の場合は作成時は公開、更新時は変更しない
+	RequiresApproval *bool    `json:"requires_approval"`  // 参加をホストの承認制にする（承認制ではパスワードを使わない）。nil の場合は更新時に変更しない
+	WantedWeapons    []string `json:"wanted_weapons"`     // 募集する武器種のコード。nil の場合は更新時に変更しない
+	SlowModeSeconds  *int     `json:"slow_mode_seconds"`  // 低速モードの間隔（秒。models.RoomSlowModeOptions のいずれか）。nil の場合は更新時に変更しない
+}
+
+// passwordFor 部屋に設定するパスワード。承認制の部屋はパスワードの代わりにホストが参加申請を審査するため設定しない
+func (req CreateRoomRequest) passwordFor(room *models.Room) string {
+	if room.RequiresApproval {
+		return ""
+	}
+	return req.Password
+}
+
+const (
+	maxScheduleAhead    = 7 * 24 * time.Hour // 開始予定時刻は7日先まで
+	maxScheduleDuration = 24 * time.Hour     // 開始から終了までは24時間以内
+)
+
+// parseRoomSchedule 開始・終了予定時刻を検証して返す。開始予定時刻が未指定なら両方 nil。
+// current には更新前の開始予定時刻を渡し、変更していなければ過去の時刻でも受け付ける
+func parseRoomSchedule(startStr, endStr string, current *time.Time, now time.Time) (*time.Time, *time.Time, error) {
+	startStr, endStr = strings.TrimSpace(startStr), strings.TrimSpace(endStr)
+	if startStr == "" {
+		if endStr != "" {
+			return nil, nil, fmt.Errorf("終了予定時刻を設定する場合は開始予定時刻も設定してください")
+		}
+		return nil, nil, nil
+	}
+
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("開始予定時刻の形式が正しくありません")
+	}
+	unchanged := current != nil && current.Equal(start)
+	if !unchanged && !start.After(now) {
+		return nil, nil, fmt.Errorf("開始予定時刻には現在より後の時刻を指定してください")
+	}
+	if start.After(now.Add(maxScheduleAhead)) {
+		return nil, nil, fmt.Errorf("開始予定時刻は7日以内で設定してください")
+	}
+
+	if endStr == "" {
+		return &start, nil, nil
+	}
+	end, err := time.Parse(time.RFC3339, endStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("終了予定時刻の形式が正しくありません")
+	}
+	if !end.After(start) {
+		return nil, nil, fmt.Errorf("終了予定時刻は開始予定時刻より後にしてください")
+	}
+	if end.Sub(start) > maxScheduleDuration {
+		return nil, nil, fmt.Errorf("終了予定時刻は開始予定時刻から24時間以内で設定してください")
+	}
+
+	return &start, &end, nil
 }
 
 func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
@@ -273,6 +384,30 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	scheduledStartAt, scheduledEndAt, err := parseRoomSchedule(req.ScheduledStartAt, req.ScheduledEndAt, nil, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Visibility != "" && !models.IsValidRoomVisibility(req.Visibility) {
+		http.Error(w, "無効な公開範囲です", http.StatusBadRequest)
+		return
+	}
+	if err := validateWantedWeapons(req.WantedWeapons, req.MaxPlayers); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.SlowModeSeconds != nil && !models.IsValidSlowModeSeconds(*req.SlowModeSeconds) {
+		http.Error(w, "無効な低速モードの間隔です", http.StatusBadRequest)
+		return
+	}
+
+	catalogSelection, err := resolveRoomCatalog(h.repo.Catalog, gameVersionID, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// 認証情報からユーザーIDを取得
 	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
 	if !exists || dbUser == nil {
@@ -282,6 +417,16 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 
 	hostUserID := dbUser.ID
 
+	// NGワードのチェック（block は拒否、mask は伏せ字にして受け付ける）
+	if req.Name, err = h.ngWordFilter.Apply(hostUserID, nil, models.NGWordTargetRoomName, req.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Description, err = h.ngWordFilter.Apply(hostUserID, nil, models.NGWordTargetRoomDescription, req.Description); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// ユーザーの部屋状態をチェック
 	status, activeRoom, err := h.repo.Room.GetUserRoomStatus(hostUserID)
 	if err != nil {
@@ -315,6 +460,8 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "現在の部屋からの退出に失敗しました", http.StatusInternalServerError)
 			return
 		}
+		h.dropReadyCheckMember(activeRoom.ID, hostUserID)
+		h.promoteWaitlist(activeRoom.ID)
 	}
 
 	// 一意な部屋コードを生成
@@ -335,19 +482,31 @@ func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		IsActive:       true,
 		CurrentPlayers: 0, // 初期人数（メンバー追加処理で更新される）
 		OGVersion:      1, // OGP画像のバージョン初期値
+
+		ScheduledStartAt: scheduledStartAt,
+		ScheduledEndAt:   scheduledEndAt,
+		Visibility:       models.RoomVisibilityPublic,
+	}
+	if req.Visibility != "" {
+		room.Visibility = req.Visibility
+	}
+	if req.RequiresApproval != nil {
+		room.RequiresApproval = *req.RequiresApproval
 	}
+	if req.SlowModeSeconds != nil {
+		room.SlowModeSeconds = *req.SlowModeSeconds
+	}
+	room.SetWantedWeapons(req.WantedWeapons)
 
 	if req.Description != "" {
 		room.Description = &req.Description
 	}
-	if req.TargetMonster != "" {
-		room.TargetMonster = &req.TargetMonster
-	}
+	catalogSelection.apply(room)
 	if req.RankRequirement != "" {
 		room.RankRequirement = &req.RankRequirement
 	}
 
-	if err := room.SetPassword(req.Password); err != nil {
+	if err := room.SetPassword(req.passwordFor(room)); err != nil {
 		http.Error(w, "パスワードの設定に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -393,6 +552,38 @@ type JoinRoomRequest struct {
 	Password    string `json:"password"`
 	ForceJoin   bool   `json:"forceJoin"`   // 強制参加フラグ（他の部屋から退出して参加）
 	ConfirmJoin bool   `json:"confirmJoin"` // ブロック警告を確認済みで参加
+	Invite      string `json:"invite"`      // 招待リンクのトークン（指定時はパスワードを確認しない）
+	// 参加時に申告する武器種・ハンターランク（任意）
+	MemberLoadoutRequest
+}
+
+// joinBlockResponse ホストにブロックされている、または参加中のメンバーとブロック関係がある場合に返す 403 のレスポンス。
+// 参加を妨げるブロック関係がなければ nil を返す
+func (h *RoomHandler) joinBlockResponse(userID uuid.UUID, room *models.Room) (map[string]interface{}, error) {
+	isBlockedByHost, _, err := h.repo.UserBlock.CheckBlockRelationship(userID, room.HostUserID)
+	if err != nil {
+		return nil, fmt.Errorf("ブロック関係の確認エラー: %w", err)
+	}
+	if isBlockedByHost {
+		return map[string]interface{}{
+			"error":     "BLOCKED_BY_HOST",
+			"message":   "このルームには参加できません",
+			"blockType": "host_block",
+		}, nil
+	}
+
+	blockedMembers, err := h.repo.UserBlock.CheckRoomMemberBlocks(userID, room.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ルームメンバーとのブロック関係確認エラー: %w", err)
+	}
+	if len(blockedMembers) > 0 {
+		return map[string]interface{}{
+			"error":     "BLOCKED_BY_MEMBER",
+			"message":   "ブロック関係により参加できません",
+			"blockType": "member_block",
+		}, nil
+	}
+	return nil, nil
 }
 
 func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
@@ -407,6 +598,11 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
 		return
 	}
+	weaponType, hunterRank, err := req.loadout()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// 認証情報からユーザーIDを取得
 	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
@@ -449,19 +645,11 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 1. ホストがユーザーをブロックしているかチェック
-	isBlockedByHost, _, blockErr := h.repo.UserBlock.CheckBlockRelationship(userID, room.HostUserID)
-	if blockErr != nil {
-		log.Printf("ブロック関係の確認エラー: %v", blockErr)
-		http.Error(w, "ブロック関係の確認に失敗しました", http.StatusInternalServerError)
-		return
-	}
-
-	if isBlockedByHost {
+	// フォロワー限定の部屋は、ホストのフォロワーか招待リンクからのみ参加できる（招待リンクはリポジトリで検証する）
+	if req.Invite == "" && !h.canViewRoom(room, dbUser) {
 		response := map[string]interface{}{
-			"error":     "BLOCKED_BY_HOST",
-			"message":   "このルームには参加できません",
-			"blockType": "host_block",
+			"error":   "FOLLOWERS_ONLY",
+			"message": "この部屋はホストのフォロワーのみ参加できます",
 		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusForbidden)
@@ -469,23 +657,17 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. 既存メンバーとのブロック関係をチェック
-	blockedMembers, blockErr := h.repo.UserBlock.CheckRoomMemberBlocks(userID, roomID)
+	// 1. ホストにブロックされているか、2. 既存メンバーとのブロック関係があれば参加できない
+	blockResponse, blockErr := h.joinBlockResponse(userID, room)
 	if blockErr != nil {
-		log.Printf("ルームメンバーとのブロック関係確認エラー: %v", blockErr)
+		log.Printf("%v", blockErr)
 		http.Error(w, "ブロック関係の確認に失敗しました", http.StatusInternalServerError)
 		return
 	}
-
-	if len(blockedMembers) > 0 {
-		response := map[string]interface{}{
-			"error":     "BLOCKED_BY_MEMBER",
-			"message":   "ブロック関係により参加できません",
-			"blockType": "member_block",
-		}
+	if blockResponse != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(response)
+		json.NewEncoder(w).Encode(blockResponse)
 		return
 	}
 
@@ -521,10 +703,18 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, "現在の部屋からの退出に失敗しました", http.StatusInternalServerError)
 				return
 			}
+			h.dropReadyCheckMember(activeRoom.ID, userID)
+			h.promoteWaitlist(activeRoom.ID)
 		}
 	}
 
-	if err := h.repo.Room.JoinRoom(roomID, userID, req.Password); err != nil {
+	// 招待リンクからの参加はパスワードの代わりに招待トークンを確認する
+	if req.Invite != "" {
+		err = h.repo.Room.JoinRoomWithInvite(roomID, userID, req.Invite)
+	} else {
+		err = h.repo.Room.JoinRoom(roomID, userID, req.Password)
+	}
+	if err != nil {
 		// 既に参加している場合は部屋詳細に遷移
 		if strings.HasPrefix(err.Error(), "ALREADY_JOINED:") {
 			response := map[string]interface{}{
@@ -548,29 +738,98 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 			json.NewEncoder(w).Encode(response)
 			return
 		}
-		// キックされたユーザーは再参加できない
-		if strings.HasPrefix(err.Error(), "KICKED:") {
+		// 満員の場合はキャンセル待ちを案内する
+		if strings.HasPrefix(err.Error(), "ROOM_FULL:") {
+			response := map[string]interface{}{
+				"error":    "ROOM_FULL",
+				"message":  strings.TrimPrefix(err.Error(), "ROOM_FULL:"),
+				"can_wait": true,
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		// 期限切れ・無効化・上限到達の招待リンク
+		if strings.HasPrefix(err.Error(), "INVITE_INVALID:") {
 			response := map[string]interface{}{
-				"error":   "KICKED",
-				"message": strings.TrimPrefix(err.Error(), "KICKED:"),
+				"error":   "INVITE_INVALID",
+				"message": strings.TrimPrefix(err.Error(), "INVITE_INVALID:"),
 			}
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusForbidden)
 			json.NewEncoder(w).Encode(response)
 			return
 		}
+		// 承認制の部屋は参加申請を作成し、ホストの承認を待つ
+		if strings.HasPrefix(err.Error(), "APPROVAL_REQUIRED:") {
+			request, reqErr := h.repo.JoinRequest.CreateJoinRequest(roomID, userID)
+			if reqErr != nil {
+				log.Printf("参加申請の作成に失敗: %v", reqErr)
+				http.Error(w, "参加申請の送信に失敗しました", http.StatusInternalServerError)
+				return
+			}
+			h.broadcastJoinRequests(room)
+
+			response := map[string]interface{}{
+				"status":     "pending",
+				"message":    "参加申請を送りました。ホストが承認すると参加できます",
+				"request_id": request.ID,
+				"redirect":   fmt.Sprintf("/rooms/%s", roomID.String()),
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		// キック中のユーザーと、ホストのBANリストに含まれるユーザーは参加できない
+		for _, code := range []string{"KICKED", "HOST_BANNED"} {
+			if strings.HasPrefix(err.Error(), code+":") {
+				response := map[string]interface{}{
+					"error":   code,
+					"message": strings.TrimPrefix(err.Error(), code+":"),
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// 申告した装備は参加した後に保存する（失敗しても参加は完了している）
+	if !req.isEmpty() {
+		if err := h.repo.Room.UpdateMemberLoadout(roomID, userID, weaponType, hunterRank); err != nil {
+			log.Printf("装備の保存に失敗: %v", err)
+		}
+	}
+
+	h.announceJoin(room, dbUser)
+
+	// 参加成功時には部屋詳細URLを返す
+	response := map[string]interface{}{
+		"message":  "ルームに参加しました",
+		"roomId":   roomID.String(),
+		"redirect": fmt.Sprintf("/rooms/%s", roomID.String()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// announceJoin 参加したユーザーを入室メッセージと member_update イベントで部屋に知らせ、参加アクティビティを記録する
+func (h *RoomHandler) announceJoin(room *models.Room, user *models.User) {
 	// 入室メッセージをroom_messagesに保存（SSE有無を問わず）
-	joinMessageText := fmt.Sprintf("%sさんが入室しました", h.getDisplayName(dbUser))
-	systemJoinMessage := h.createSystemMessage(roomID, dbUser, joinMessageText)
+	joinMessageText := fmt.Sprintf("%sさんが入室しました", h.getDisplayName(user))
+	systemJoinMessage := h.createSystemMessage(room.ID, user, joinMessageText)
 	h.broadcastSystemMessage(systemJoinMessage)
 
 	if h.hub != nil {
 		// メンバー更新イベント（ユーザーパネル用）
-		members, err := h.repo.Room.GetRoomMembers(roomID)
+		members, err := h.repo.Room.GetRoomMembers(room.ID)
 		if err != nil {
 			log.Printf("メンバー情報取得エラー: %v", err)
 			members = []models.RoomMember{} // エラー時は空配列
@@ -585,7 +844,7 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 				"count":   len(members),
 			},
 		}
-		h.hub.BroadcastToRoom(roomID, memberUpdateEvent)
+		h.hub.BroadcastToRoom(room.ID, memberUpdateEvent)
 	}
 
 	// アクティビティを記録（失敗してもメイン処理は続行）
@@ -593,22 +852,11 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 	if hostErr != nil {
 		log.Printf("ホストユーザー情報の取得に失敗: %v", hostErr)
 	} else {
-		if err := h.activityService.RecordRoomJoin(userID, room, hostUser); err != nil {
+		if err := h.activityService.RecordRoomJoin(user.ID, room, hostUser); err != nil {
 			log.Printf("部屋参加アクティビティの記録に失敗: %v", err)
 			// アクティビティ記録失敗はメイン処理に影響させない
 		}
 	}
-
-	// 参加成功時には部屋詳細URLを返す
-	response := map[string]interface{}{
-		"message":  "ルームに参加しました",
-		"roomId":   roomID.String(),
-		"redirect": fmt.Sprintf("/rooms/%s", roomID.String()),
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
 }
 
 func (h *RoomHandler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
@@ -634,6 +882,14 @@ func (h *RoomHandler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.LeaveRoom(roomID, userID); err != nil {
+		// 他にメンバーがいないホストは退出できない（解散してもらう）
+		if strings.HasPrefix(err.Error(), "HOST_ALONE:") {
+			respondWithJSON(w, http.StatusConflict, map[string]interface{}{
+				"error":   "HOST_ALONE",
+				"message": strings.TrimPrefix(err.Error(), "HOST_ALONE:"),
+			})
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -662,6 +918,16 @@ func (h *RoomHandler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
 		h.hub.BroadcastToRoom(roomID, memberUpdateEvent)
 	}
 
+	// ホストが退出した場合は、引き継いだメンバーを部屋に知らせる
+	if room != nil && room.HostUserID == userID {
+		h.broadcastHostChange(roomID, dbUser, models.HostChangeReasonSuccession)
+	}
+
+	h.dropReadyCheckMember(roomID, userID)
+
+	// 空いた席をキャンセル待ちの先頭に確保する
+	h.promoteWaitlist(roomID)
+
 	// アクティビティを記録（失敗してもメイン処理は続行）
 	if room != nil {
 		if err := h.activityService.RecordRoomLeave(userID, room); err != nil {
@@ -674,7 +940,8 @@ func (h *RoomHandler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"message": "ルームから退室しました"}`))
 }
 
-// KickMember ホストがメンバーを部屋から退出させる。退出させられたユーザーは同じ部屋に再参加できない
+// KickMember ホストがメンバーを部屋から退出させる。退出させられたユーザーは期間中（指定がなければ無期限）同じ部屋に再参加できない。
+// ban を指定すると、ホストのBANリストにも追加してホストの部屋すべてに参加できなくする
 func (h *RoomHandler) KickMember(w http.ResponseWriter, r *http.Request) {
 	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -683,7 +950,10 @@ func (h *RoomHandler) KickMember(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		UserID string `json:"user_id"`
+		UserID        string `json:"user_id"`
+		Reason        string `json:"reason"`
+		DurationHours int    `json:"duration_hours"` // 0 は無期限
+		Ban           bool   `json:"ban"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
@@ -694,6 +964,16 @@ func (h *RoomHandler) KickMember(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "無効なユーザーIDです", http.StatusBadRequest)
 		return
 	}
+	reason, err := normalizeBanReason(req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	until, err := kickUntil(req.DurationHours, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
 	if !exists || dbUser == nil {
@@ -721,17 +1001,28 @@ func (h *RoomHandler) KickMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.repo.Room.KickMember(roomID, targetUserID); err != nil {
+	if err := h.repo.Room.KickMember(roomID, targetUserID, reason, until); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	targetName := h.getDisplayName(targetUser)
+	message := fmt.Sprintf("%sさんを退出させました", targetName)
+	if req.Ban {
+		if _, err := h.repo.HostBan.BanUser(dbUser.ID, targetUser.ID, reason); err != nil {
+			log.Printf("BANリストへの追加に失敗: %v", err)
+			message += "（BANリストへの追加には失敗しました）"
+		} else {
+			message = fmt.Sprintf("%sさんを退出させ、BANリストに追加しました", targetName)
+		}
+	}
+
+	// 理由はチャットには流さず、本人へのお知らせにだけ添える
 	kickText := fmt.Sprintf("%sさんはホストにより退出となりました", targetName)
 	h.broadcastSystemMessage(h.createSystemMessage(roomID, dbUser, kickText))
 
 	// お知らせは失敗してもキック処理には影響させない
-	if err := h.notificationService.NotifyRoomKicked(targetUser.ID, room); err != nil {
+	if err := h.notificationService.NotifyRoomKicked(targetUser.ID, room, reason, until); err != nil {
 		log.Printf("キックのお知らせ作成に失敗: %v", err)
 	}
 
@@ -765,8 +1056,13 @@ func (h *RoomHandler) KickMember(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	h.dropReadyCheckMember(roomID, targetUser.ID)
+
+	// 空いた席をキャンセル待ちの先頭に確保する
+	h.promoteWaitlist(roomID)
+
 	respondWithJSON(w, http.StatusOK, map[string]interface{}{
-		"message": fmt.Sprintf("%sさんを退出させました", targetName),
+		"message": message,
 	})
 }
 
@@ -789,12 +1085,24 @@ func (h *RoomHandler) LeaveCurrentRoom(w http.ResponseWriter, r *http.Request) {
 
 	// 部屋から退出
 	if err := h.repo.Room.LeaveRoom(activeRoom.ID, userID); err != nil {
+		if strings.HasPrefix(err.Error(), "HOST_ALONE:") {
+			respondWithJSON(w, http.StatusConflict, map[string]interface{}{
+				"error":   "HOST_ALONE",
+				"message": strings.TrimPrefix(err.Error(), "HOST_ALONE:"),
+			})
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	leaveMessageText := fmt.Sprintf("%sさんが退室しました", h.getDisplayName(dbUser))
 	h.broadcastSystemMessage(h.createSystemMessage(activeRoom.ID, dbUser, leaveMessageText))
+	if activeRoom.HostUserID == userID {
+		h.broadcastHostChange(activeRoom.ID, dbUser, models.HostChangeReasonSuccession)
+	}
+	h.dropReadyCheckMember(activeRoom.ID, userID)
+	h.promoteWaitlist(activeRoom.ID)
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"message": "現在の部屋から退室しました"}`))
@@ -847,78 +1155,112 @@ func (h *RoomHandler) ToggleRoomClosed(w http.ResponseWriter, r *http.Request) {
 	status := "開いた"
 	if req.IsClosed {
 		status = "閉じた"
+	} else {
+		// 募集を再開したら、空いている席をキャンセル待ちに回す
+		h.promoteWaitlist(roomID)
 	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(fmt.Sprintf(`{"message": "ルームを%s状態にしました"}`, status)))
 }
 
-// GetAllRoomsAPIHandler APIエンドポイント：常に全データを返す
+// GetAllRoomsAPIHandler APIエンドポイント：検索条件（/rooms と同じクエリパラメータ）に一致する部屋を最大100件返す
 func (h *RoomHandler) GetAllRoomsAPI(w http.ResponseWriter, r *http.Request) {
 	// Note: GameVersionsはHTMLレンダリング時に既に取得済み
+	now := time.Now()
+	search := parseRoomSearchQuery(r)
+	params := search.Params(h.resolveGameVersionID(search.GameVersion), 100, 0)
 	// 認証されたユーザーの場合、最適化されたメソッドを使用
 	var enhancedRooms []interface{}
 	dbUser, isAuthenticated := middleware.GetDBUserFromContext(r.Context())
+	// フォロワー限定の部屋は閲覧者に応じて掲載する
+	params.ViewerID = viewerUserID(r)
 
 	if isAuthenticated && dbUser != nil {
 		// パフォーマンス最適化: 1つのクエリで参加状態を取得
-		roomsWithJoinStatus, err := h.repo.Room.GetActiveRoomsWithJoinStatus(&dbUser.ID, nil, 100, 0)
+		roomsWithJoinStatus, err := h.repo.Room.GetActiveRoomsWithJoinStatus(&dbUser.ID, params)
 		if err != nil {
 			http.Error(w, "ルーム一覧の取得に失敗しました", http.StatusInternalServerError)
 			return
 		}
 
+		roomIDs := make([]uuid.UUID, 0, len(roomsWithJoinStatus))
+		for _, roomWithStatus := range roomsWithJoinStatus {
+			roomIDs = append(roomIDs, roomWithStatus.Room.ID)
+		}
+		memberWeapons := h.loadMemberWeapons(roomIDs)
+
 		for _, roomWithStatus := range roomsWithJoinStatus {
 			roomData := map[string]interface{}{
-				"id":               roomWithStatus.Room.ID,
-				"room_code":        roomWithStatus.Room.RoomCode,
-				"name":             roomWithStatus.Room.Name,
-				"description":      roomWithStatus.Room.GetDescription(),
-				"game_version_id":  roomWithStatus.Room.GameVersionID,
-				"host_user_id":     roomWithStatus.Room.HostUserID,
-				"max_players":      roomWithStatus.Room.MaxPlayers,
-				"current_players":  roomWithStatus.Room.CurrentPlayers,
-				"target_monster":   roomWithStatus.Room.GetTargetMonster(),
-				"rank_requirement": roomWithStatus.Room.GetRankRequirement(),
-				"is_active":        roomWithStatus.Room.IsActive,
-				"is_closed":        roomWithStatus.Room.IsClosed,
-				"created_at":       roomWithStatus.Room.CreatedAt,
-				"updated_at":       roomWithStatus.Room.UpdatedAt,
-				"game_version":     roomWithStatus.Room.GameVersion,
-				"host":             roomWithStatus.Room.Host,
-				"has_password":     roomWithStatus.Room.HasPassword(),
-				"is_joined":        roomWithStatus.IsJoined,
+				"id":                 roomWithStatus.Room.ID,
+				"room_code":          roomWithStatus.Room.RoomCode,
+				"name":               roomWithStatus.Room.Name,
+				"description":        roomWithStatus.Room.GetDescription(),
+				"game_version_id":    roomWithStatus.Room.GameVersionID,
+				"host_user_id":       roomWithStatus.Room.HostUserID,
+				"max_players":        roomWithStatus.Room.MaxPlayers,
+				"current_players":    roomWithStatus.Room.CurrentPlayers,
+				"target_monster":     roomWithStatus.Room.GetTargetMonster(),
+				"rank_requirement":   roomWithStatus.Room.GetRankRequirement(),
+				"is_active":          roomWithStatus.Room.IsActive,
+				"is_closed":          roomWithStatus.Room.IsClosed,
+				"created_at":         roomWithStatus.Room.CreatedAt,
+				"updated_at":         roomWithStatus.Room.UpdatedAt,
+				"game_version":       roomWithStatus.Room.GameVersion,
+				"host":               roomWithStatus.Room.Host,
+				"has_password":       roomWithStatus.Room.HasPassword(),
+				"scheduled_start_at": roomWithStatus.Room.ScheduledStartAt,
+				"scheduled_end_at":   roomWithStatus.Room.ScheduledEndAt,
+				"is_upcoming":        roomWithStatus.Room.IsUpcoming(now),
+				"visibility":         roomWithStatus.Room.GetVisibility(),
+				"requires_approval":  roomWithStatus.Room.RequiresApproval,
+				"wanted_weapons":     roomWithStatus.Room.GetWantedWeapons(),
+				"weapon_slots":       roomWithStatus.Room.WeaponSlots(memberWeapons[roomWithStatus.Room.ID]),
+				"is_joined":          roomWithStatus.IsJoined,
 			}
 			enhancedRooms = append(enhancedRooms, roomData)
 		}
 	} else {
 		// 未認証ユーザーの場合は従来の方法
-		rooms, err := h.repo.Room.GetActiveRooms(nil, 100, 0)
+		rooms, err := h.repo.Room.GetActiveRooms(params)
 		if err != nil {
 			http.Error(w, "ルーム一覧の取得に失敗しました", http.StatusInternalServerError)
 			return
 		}
 
+		roomIDs := make([]uuid.UUID, 0, len(rooms))
+		for _, room := range rooms {
+			roomIDs = append(roomIDs, room.ID)
+		}
+		memberWeapons := h.loadMemberWeapons(roomIDs)
+
 		for _, room := range rooms {
 			roomData := map[string]interface{}{
-				"id":               room.ID,
-				"room_code":        room.RoomCode,
-				"name":             room.Name,
-				"description":      room.GetDescription(),
-				"game_version_id":  room.GameVersionID,
-				"host_user_id":     room.HostUserID,
-				"max_players":      room.MaxPlayers,
-				"current_players":  room.CurrentPlayers,
-				"target_monster":   room.GetTargetMonster(),
-				"rank_requirement": room.GetRankRequirement(),
-				"is_active":        room.IsActive,
-				"is_closed":        room.IsClosed,
-				"created_at":       room.CreatedAt,
-				"updated_at":       room.UpdatedAt,
-				"game_version":     room.GameVersion,
-				"host":             room.Host,
-				"has_password":     room.HasPassword(),
-				"is_joined":        false,
+				"id":                 room.ID,
+				"room_code":          room.RoomCode,
+				"name":               room.Name,
+				"description":        room.GetDescription(),
+				"game_version_id":    room.GameVersionID,
+				"host_user_id":       room.HostUserID,
+				"max_players":        room.MaxPlayers,
+				"current_players":    room.CurrentPlayers,
+				"target_monster":     room.GetTargetMonster(),
+				"rank_requirement":   room.GetRankRequirement(),
+				"is_active":          room.IsActive,
+				"is_closed":          room.IsClosed,
+				"created_at":         room.CreatedAt,
+				"updated_at":         room.UpdatedAt,
+				"game_version":       room.GameVersion,
+				"host":               room.Host,
+				"has_password":       room.HasPassword(),
+				"scheduled_start_at": room.ScheduledStartAt,
+				"scheduled_end_at":   room.ScheduledEndAt,
+				"is_upcoming":        room.IsUpcoming(now),
+				"visibility":         room.GetVisibility(),
+				"requires_approval":  room.RequiresApproval,
+				"wanted_weapons":     room.GetWantedWeapons(),
+				"weapon_slots":       room.WeaponSlots(memberWeapons[room.ID]),
+				"is_joined":          false,
 			}
 			enhancedRooms = append(enhancedRooms, roomData)
 		}
@@ -926,8 +1268,9 @@ func (h *RoomHandler) GetAllRoomsAPI(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"rooms": enhancedRooms,
-		"total": len(enhancedRooms),
+		"rooms":  enhancedRooms,
+		"total":  len(enhancedRooms),
+		"search": search,
 	})
 }
 
@@ -957,25 +1300,36 @@ func (h *RoomHandler) GetCurrentRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	now := time.Now()
 	roomData := map[string]interface{}{
-		"id":               activeRoom.ID,
-		"room_code":        activeRoom.RoomCode,
-		"name":             activeRoom.Name,
-		"description":      activeRoom.GetDescription(),
-		"game_version_id":  activeRoom.GameVersionID,
-		"game_version":     activeRoom.GameVersion,
-		"host_user_id":     activeRoom.HostUserID,
-		"host":             activeRoom.Host,
-		"max_players":      activeRoom.MaxPlayers,
-		"current_players":  activeRoom.CurrentPlayers,
-		"target_monster":   activeRoom.GetTargetMonster(),
-		"rank_requirement": activeRoom.GetRankRequirement(),
-		"is_active":        activeRoom.IsActive,
-		"is_closed":        activeRoom.IsClosed,
-		"created_at":       activeRoom.CreatedAt,
-		"updated_at":       activeRoom.UpdatedAt,
-		"has_password":     activeRoom.HasPassword(),
+		"id":                 activeRoom.ID,
+		"room_code":          activeRoom.RoomCode,
+		"name":               activeRoom.Name,
+		"description":        activeRoom.GetDescription(),
+		"game_version_id":    activeRoom.GameVersionID,
+		"game_version":       activeRoom.GameVersion,
+		"host_user_id":       activeRoom.HostUserID,
+		"host":               activeRoom.Host,
+		"max_players":        activeRoom.MaxPlayers,
+		"current_players":    activeRoom.CurrentPlayers,
+		"target_monster":     activeRoom.GetTargetMonster(),
+		"rank_requirement":   activeRoom.GetRankRequirement(),
+		"is_active":          activeRoom.IsActive,
+		"is_closed":          activeRoom.IsClosed,
+		"created_at":         activeRoom.CreatedAt,
+		"updated_at":         activeRoom.UpdatedAt,
+		"has_password":       activeRoom.HasPassword(),
+		"scheduled_start_at": activeRoom.ScheduledStartAt,
+		"scheduled_end_at":   activeRoom.ScheduledEndAt,
+		"is_upcoming":        activeRoom.IsUpcoming(now),
+	}
+
+	// 未読のチャットメッセージ数（取得できなければ0として扱う）
+	unreadCount, err := h.repo.RoomMessage.CountUnread(activeRoom.ID, userID)
+	if err != nil {
+		log.Printf("未読メッセージ数の取得に失敗しました room_id=%s user_id=%s: %v", activeRoom.ID, userID, err)
 	}
+	roomData["unread_count"] = unreadCount
 
 	response := map[string]interface{}{
 		"current_room": roomData,
@@ -1025,6 +1379,14 @@ func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "無効なゲームバージョンIDです", http.StatusBadRequest)
 		return
 	}
+	if req.Visibility != "" && !models.IsValidRoomVisibility(req.Visibility) {
+		http.Error(w, "無効な公開範囲です", http.StatusBadRequest)
+		return
+	}
+	if req.SlowModeSeconds != nil && !models.IsValidSlowModeSeconds(*req.SlowModeSeconds) {
+		http.Error(w, "無効な低速モードの間隔です", http.StatusBadRequest)
+		return
+	}
 
 	// 認証情報からユーザーIDを取得
 	dbUser, exists := middleware.GetDBUserFromContext(r.Context())
@@ -1047,10 +1409,56 @@ func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	scheduledStartAt, scheduledEndAt, err := parseRoomSchedule(req.ScheduledStartAt, req.ScheduledEndAt, room.ScheduledStartAt, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	catalogSelection, err := resolveRoomCatalog(h.repo.Catalog, gameVersionID, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// 開始予定時刻を変更した場合は開始前のお知らせを送り直す
+	if !sameTime(room.ScheduledStartAt, scheduledStartAt) {
+		room.StartReminderSentAt = nil
+	}
+	room.ScheduledStartAt = scheduledStartAt
+	room.ScheduledEndAt = scheduledEndAt
+
 	// 部屋情報の更新
-	room.Name = req.Name
 	room.GameVersionID = gameVersionID
 	room.MaxPlayers = req.MaxPlayers
+	if req.Visibility != "" {
+		room.Visibility = req.Visibility
+	}
+	if req.RequiresApproval != nil {
+		room.RequiresApproval = *req.RequiresApproval
+	}
+	if req.WantedWeapons != nil {
+		room.SetWantedWeapons(req.WantedWeapons)
+	}
+	previousSlowMode := room.SlowModeSeconds
+	if req.SlowModeSeconds != nil {
+		room.SlowModeSeconds = *req.SlowModeSeconds
+	}
+	// 定員を減らした場合も募集する武器種の枠が定員を超えないようにする
+	if err := validateWantedWeapons(room.GetWantedWeapons(), room.MaxPlayers); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// NGワードのチェック（block は拒否、mask は伏せ字にして受け付ける）。
+	// 一致は記録に残るため、ほかの入力チェックをすべて通ってから行う
+	if req.Name, err = h.ngWordFilter.Apply(userID, &roomID, models.NGWordTargetRoomName, req.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Description, err = h.ngWordFilter.Apply(userID, &roomID, models.NGWordTargetRoomDescription, req.Description); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	room.Name = req.Name
 	room.OGVersion++ // OGP画像バージョンをインクリメント
 
 	if req.Description != "" {
@@ -1058,18 +1466,14 @@ func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 	} else {
 		room.Description = nil
 	}
-	if req.TargetMonster != "" {
-		room.TargetMonster = &req.TargetMonster
-	} else {
-		room.TargetMonster = nil
-	}
+	catalogSelection.apply(room)
 	if req.RankRequirement != "" {
 		room.RankRequirement = &req.RankRequirement
 	} else {
 		room.RankRequirement = nil
 	}
 
-	if err := room.SetPassword(req.Password); err != nil {
+	if err := room.SetPassword(req.passwordFor(room)); err != nil {
 		http.Error(w, "パスワードの設定に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -1079,6 +1483,13 @@ func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 定員が増えた場合は増えた席をキャンセル待ちに回す
+	h.promoteWaitlist(room.ID)
+
+	if room.SlowModeSeconds != previousSlowMode {
+		h.notifySlowModeChanged(room, dbUser)
+	}
+
 	// OGP画像生成ジョブを非同期実行（失敗してもメイン処理は続行）
 	go func() {
 		ogpService := services.NewOGPJobService()
@@ -1097,6 +1508,14 @@ func (h *RoomHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// sameTime nil を含めて2つの時刻が同じかどうか
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 func (h *RoomHandler) DismissRoom(w http.ResponseWriter, r *http.Request) {
 	roomID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -1137,10 +1556,23 @@ func (h *RoomHandler) DismissRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 進行中の準備確認は結果を知らせずに打ち切る
+	h.readyChecks.end(roomID, uuid.Nil)
+
+	// チャットの添付画像を削除（失敗しても room-cleanup が拾い直す）
+	if err := h.chatAttachments.PurgeRoom(r.Context(), roomID); err != nil {
+		log.Printf("添付画像の削除に失敗: room_id=%s: %v", roomID, err)
+	}
+
 	// 参加していたメンバーへのお知らせ（失敗しても解散処理には影響させない）
 	if err := h.notificationService.NotifyRoomDismissedToMembers(room, membersBeforeDismiss); err != nil {
 		log.Printf("解散のお知らせ作成に失敗: %v", err)
 	}
+	if summary, err := h.repo.RoomSession.FindByRoomID(roomID); err != nil {
+		log.Printf("セッションのまとめの取得に失敗: %v", err)
+	} else if err := h.notificationService.NotifySessionSummaryToPastMembers(room, summary, membersBeforeDismiss); err != nil {
+		log.Printf("セッションのまとめのお知らせ作成に失敗: %v", err)
+	}
 
 	dismissText := fmt.Sprintf("ルームがホスト（%s）によって解散されました", h.getDisplayName(dbUser))
 	dismissMessage := h.createSystemMessage(roomID, dbUser, dismissText)
@@ -1202,6 +1634,27 @@ func (h *RoomHandler) createSystemMessage(roomID uuid.UUID, user *models.User, m
 	return roomMessage
 }
 
+// SlowModeUpdateData 低速モードの変更イベント（slow_mode_update）のデータ
+type SlowModeUpdateData struct {
+	SlowModeSeconds int `json:"slow_mode_seconds"`
+}
+
+// notifySlowModeChanged 低速モードの変更をチャットに流し、メンバーの画面の送信間隔を切り替える
+func (h *RoomHandler) notifySlowModeChanged(room *models.Room, host *models.User) {
+	text := "ホストが低速モードを解除しました"
+	if room.SlowModeSeconds > 0 {
+		text = fmt.Sprintf("ホストが低速モードを%s間隔に設定しました", formatChatWait(room.SlowModeSeconds))
+	}
+	h.broadcastSystemMessage(h.createSystemMessage(room.ID, host, text))
+
+	if h.hub != nil {
+		h.hub.BroadcastToRoom(room.ID, sse.Event{
+			Type: "slow_mode_update",
+			Data: SlowModeUpdateData{SlowModeSeconds: room.SlowModeSeconds},
+		})
+	}
+}
+
 func (h *RoomHandler) broadcastSystemMessage(message *models.RoomMessage) {
 	if message == nil || h.hub == nil {
 		return
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+func TestUpdateRoomValidatesBeforeNGWordFilter(t *testing.T) {
+	host := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, HostUserID: host.ID, MaxPlayers: 4, IsActive: true}
+	slowMode := 7
+
+	// どのリクエストも部屋名に NG ワードを含むが、ほかの入力チェックで断られる
+	tests := []struct {
+		name string
+		req  CreateRoomRequest
+	}{
+		{name: "終了予定時刻だけの指定", req: CreateRoomRequest{ScheduledEndAt: "2026-10-17T22:00:00+09:00"}},
+		{name: "定員を超える募集武器種", req: CreateRoomRequest{MaxPlayers: 1, WantedWeapons: []string{models.WeaponBow, models.WeaponLance}}},
+		{name: "無効な低速モードの間隔", req: CreateRoomRequest{SlowModeSeconds: &slowMode}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ngWords := &fakeNGWordRepo{words: []models.NGWord{
+				{Pattern: "spam", MatchType: models.NGWordMatchSubstring, Action: models.NGWordActionMask, IsActive: true},
+			}}
+			h := NewRoomHandler(&repository.Repository{
+				Room:   &fakeRoomRepo{room: room},
+				NGWord: ngWords,
+			}, nil)
+
+			req := tt.req
+			req.Name = "spam歓迎"
+			req.GameVersionID = uuid.New().String()
+			if req.MaxPlayers == 0 {
+				req.MaxPlayers = 4
+			}
+			body, err := json.Marshal(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			h.UpdateRoom(w, newTestRequest("PUT", "/rooms/"+room.ID.String(), bytes.NewReader(body), map[string]string{"id": room.ID.String()}, host))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+			if len(ngWords.hits) != 0 {
+				t.Errorf("NGワードの一致記録 = %d 件, want 0", len(ngWords.hits))
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"mhp-rooms/internal/config"
 	"mhp-rooms/internal/info"
+	"mhp-rooms/internal/repository"
 )
 
 const (
@@ -96,6 +97,12 @@ func defaultSitemapEntries(baseURL string, now time.Time) []URL {
 			ChangeFreq: "daily",
 			Priority:   0.8,
 		},
+		{
+			Loc:        baseURL + "/leaderboard",
+			LastMod:    lastMod,
+			ChangeFreq: "daily",
+			Priority:   0.7,
+		},
 		{
 			Loc:        baseURL + "/contact",
 			LastMod:    lastMod,
@@ -228,7 +235,7 @@ func isBlogArticle(category info.ArticleType) bool {
 }
 
 func (h *PageHandler) buildRoomURLs(baseURL string) ([]URL, error) {
-	rooms, err := h.repo.Room.GetActiveRooms(nil, sitemapRoomLimit, 0)
+	rooms, err := h.repo.Room.GetActiveRooms(repository.RoomSearchParams{Limit: sitemapRoomLimit})
 	if err != nil {
 		return nil, err
 	}
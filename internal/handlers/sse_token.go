@@ -21,6 +21,7 @@ type SSEToken struct {
 	UserID    uuid.UUID `json:"user_id"`
 	RoomID    uuid.UUID `json:"room_id"`
 	ExpiresAt time.Time `json:"expires_at"`
+	Waiting   bool      `json:"waiting"` // キャンセル待ちとしての接続（本人宛てのイベントだけを受け取る）
 }
 
 // SSETokenManager はSSE用の一時トークンを管理する
@@ -40,7 +41,7 @@ func NewSSETokenManager() *SSETokenManager {
 	return manager
 }
 
-func (m *SSETokenManager) GenerateToken(userID, roomID uuid.UUID) string {
+func (m *SSETokenManager) GenerateToken(userID, roomID uuid.UUID, waiting bool) string {
 	// 32バイトのランダムトークンを生成
 	bytes := make([]byte, 32)
 	rand.Read(bytes)
@@ -54,6 +55,7 @@ func (m *SSETokenManager) GenerateToken(userID, roomID uuid.UUID) string {
 		UserID:    userID,
 		RoomID:    roomID,
 		ExpiresAt: time.Now().Add(5 * time.Minute), // 5分間有効
+		Waiting:   waiting,
 	}
 
 	return token
@@ -137,14 +139,22 @@ func (h *SSETokenHandler) GenerateSSEToken(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// 部屋のメンバーチェック
+	// 部屋のメンバーチェック。メンバーでなくてもキャンセル待ち中・参加申請の審査待ちなら本人宛てのイベントだけを受け取れる
+	waiting := false
 	if !h.repo.Room.IsUserJoinedRoom(roomID, user.ID) {
-		http.Error(w, "部屋のメンバーではありません", http.StatusForbidden)
-		return
+		entry, _, err := h.repo.RoomWaitlist.FindActiveEntry(roomID, user.ID)
+		if err != nil || entry == nil {
+			request, reqErr := h.repo.JoinRequest.FindPendingRequest(roomID, user.ID)
+			if reqErr != nil || request == nil {
+				http.Error(w, "部屋のメンバーではありません", http.StatusForbidden)
+				return
+			}
+		}
+		waiting = true
 	}
 
 	// 一時トークンを生成
-	token := globalSSETokenManager.GenerateToken(user.ID, roomID)
+	token := globalSSETokenManager.GenerateToken(user.ID, roomID, waiting)
 
 	response := map[string]string{
 		"token": token,
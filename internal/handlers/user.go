@@ -187,7 +187,7 @@ func (uh *UserHandler) Show(w http.ResponseWriter, r *http.Request) {
 	playTimes, _ := user.GetPlayTimes()
 
 	// 作成した部屋の1ページ目を取得（タブ初期表示用）
-	rooms, roomsPagination, err := uh.hostedRoomsPage(user.ID, 1, fmt.Sprintf("/api/users/%s/rooms", user.ID))
+	rooms, roomsPagination, err := uh.hostedRoomsPage(user.ID, viewerUserID(r), 1, fmt.Sprintf("/api/users/%s/rooms", user.ID))
 	if err != nil {
 		log.Printf("部屋取得エラー: %v", err)
 	}
@@ -257,7 +257,7 @@ func (uh *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	playTimes, _ := user.GetPlayTimes()
 
 	// 作成した部屋の1ページ目を取得（タブ初期表示用）
-	rooms, roomsPagination, err := uh.hostedRoomsPage(user.ID, 1, fmt.Sprintf("/api/users/%s/rooms", user.ID))
+	rooms, roomsPagination, err := uh.hostedRoomsPage(user.ID, viewerUserID(r), 1, fmt.Sprintf("/api/users/%s/rooms", user.ID))
 	if err != nil {
 		log.Printf("部屋取得エラー: %v", err)
 	}
@@ -430,7 +430,7 @@ func (uh *UserHandler) Rooms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rooms, pagination, err := uh.hostedRoomsPage(targetUserID, parsePageParam(r), r.URL.Path)
+	rooms, pagination, err := uh.hostedRoomsPage(targetUserID, viewerUserID(r), parsePageParam(r), r.URL.Path)
 	if err != nil {
 		log.Printf("部屋取得エラー: %v", err)
 		http.Error(w, "部屋データの取得に失敗しました", http.StatusInternalServerError)
@@ -0,0 +1,158 @@
+package sse
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// eventLogSize 部屋ごとに再送用に残すイベントの数。これより多く取りこぼした接続には resync_required を送る
+	eventLogSize = 256
+	// eventLogRetention 接続がなく、この時間イベントもない部屋のログは捨てる
+	eventLogRetention = 10 * time.Minute
+	// maxPrunedLogs ログを捨てた部屋の記録（prunedLog）を残す数。超えたら古いものから Hub 全体の forgottenSeq にまとめる
+	maxPrunedLogs = 10000
+)
+
+// ResyncData は再送できない場合に送る resync_required のデータ
+type ResyncData struct {
+	Reason string `json:"reason"`
+}
+
+// 再送できない理由
+const (
+	ResyncReasonUnknownID = "unknown_id"  // 別のサーバープロセスのイベントID、または解釈できないID
+	ResyncReasonGapTooBig = "gap_too_big" // 取りこぼしたイベントが既にログから溢れている
+)
+
+type loggedEvent struct {
+	seq   uint64
+	event Event
+}
+
+// eventLog 部屋ごとの再送用のイベントログ（古い順、最大 eventLogSize 件）
+type eventLog struct {
+	events     []loggedEvent
+	droppedSeq uint64 // ログから溢れて捨てた最新のイベントの番号
+	lastAt     time.Time
+}
+
+// prunedLog ログを捨てた部屋の記録。これより前のイベントまでしか受け取っていない接続は取りこぼしがありうる
+type prunedLog struct {
+	seq uint64
+	at  time.Time
+}
+
+func (l *eventLog) append(seq uint64, event Event, now time.Time) {
+	if len(l.events) >= eventLogSize {
+		l.droppedSeq = l.events[0].seq
+		l.events = l.events[1:]
+	}
+	l.events = append(l.events, loggedEvent{seq: seq, event: event})
+	l.lastAt = now
+}
+
+func (l *eventLog) lastSeq() uint64 {
+	if len(l.events) == 0 {
+		return l.droppedSeq
+	}
+	return l.events[len(l.events)-1].seq
+}
+
+// eventID イベントIDは「Hub の世代-通し番号」。サーバーの再起動をまたいだ ID を見分けるために世代を付ける
+func (h *Hub) eventID(seq uint64) string {
+	return h.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// parseEventID この Hub が発行したイベントIDから通し番号を取り出す
+func (h *Hub) parseEventID(id string) (uint64, bool) {
+	epoch, seqText, found := strings.Cut(id, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil || seq > h.seq {
+		return 0, false
+	}
+	return seq, true
+}
+
+// record 部屋全体へのイベントに通し番号を振ってログに残す（呼び出し側で mu をロックしておく）
+func (h *Hub) record(roomID uuid.UUID, event Event, now time.Time) Event {
+	h.seq++
+	event.ID = h.eventID(h.seq)
+	log, ok := h.logs[roomID]
+	if !ok {
+		// ログを捨てた部屋なら、捨てたイベントより前の ID での再接続を取りこぼしとして扱えるよう引き継ぐ
+		log = &eventLog{droppedSeq: h.prunedSeq(roomID)}
+		delete(h.pruned, roomID)
+		h.logs[roomID] = log
+	}
+	log.append(h.seq, event, now)
+	return event
+}
+
+// eventsSince lastEventID より後の部屋のイベントを返す。再送できなければ resync_required の理由を返す
+// （呼び出し側で mu をロックしておく）
+func (h *Hub) eventsSince(roomID uuid.UUID, lastEventID string) ([]Event, string) {
+	seq, ok := h.parseEventID(lastEventID)
+	if !ok {
+		return nil, ResyncReasonUnknownID
+	}
+	log, ok := h.logs[roomID]
+	if !ok {
+		// ログを捨てた部屋で、捨てる前のイベントまでしか受け取っていなければ取りこぼしがありうる
+		if seq < h.prunedSeq(roomID) {
+			return nil, ResyncReasonGapTooBig
+		}
+		return nil, ""
+	}
+	if seq < log.droppedSeq {
+		return nil, ResyncReasonGapTooBig
+	}
+	var events []Event
+	for _, logged := range log.events {
+		if logged.seq > seq {
+			events = append(events, logged.event)
+		}
+	}
+	return events, ""
+}
+
+// prunedSeq 部屋の捨てたログに含まれていた最新のイベントの番号。記録も消した部屋は forgottenSeq で代用する
+// （呼び出し側で mu をロックしておく）
+func (h *Hub) prunedSeq(roomID uuid.UUID) uint64 {
+	if pruned, ok := h.pruned[roomID]; ok {
+		return pruned.seq
+	}
+	return h.forgottenSeq
+}
+
+// pruneLogs 接続がなく、しばらくイベントもない部屋のログを捨てる（呼び出し側で mu をロックしておく）
+func (h *Hub) pruneLogs(now time.Time) {
+	for roomID, log := range h.logs {
+		if _, connected := h.rooms[roomID]; connected || now.Sub(log.lastAt) < eventLogRetention {
+			continue
+		}
+		h.pruned[roomID] = prunedLog{seq: log.lastSeq(), at: now}
+		delete(h.logs, roomID)
+	}
+	if len(h.pruned) <= maxPrunedLogs {
+		return
+	}
+	roomIDs := make([]uuid.UUID, 0, len(h.pruned))
+	for roomID := range h.pruned {
+		roomIDs = append(roomIDs, roomID)
+	}
+	sort.Slice(roomIDs, func(i, j int) bool { return h.pruned[roomIDs[i]].at.Before(h.pruned[roomIDs[j]].at) })
+	for _, roomID := range roomIDs[:len(roomIDs)-maxPrunedLogs] {
+		if seq := h.pruned[roomID].seq; seq > h.forgottenSeq {
+			h.forgottenSeq = seq
+		}
+		delete(h.pruned, roomID)
+	}
+}
@@ -3,7 +3,9 @@ package sse
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,7 +13,7 @@ import (
 // Event はSSEで送信するイベント
 type Event struct {
 	ID   string      `json:"id"`
-	Type string      `json:"type"` // message, member_join, member_leave, room_update
+	Type string      `json:"type"` // message, member_join, member_leave, room_update, member_online, member_offline, typing
 	Data interface{} `json:"data"`
 }
 
@@ -21,6 +23,16 @@ type Client struct {
 	UserID uuid.UUID
 	RoomID uuid.UUID
 	Send   chan Event
+	// Waiting キャンセル待ちの接続。部屋全体へのブロードキャストは届かず、本人宛てのイベントだけを受け取る
+	Waiting bool
+	// LastEventID 再接続時に受け取った最後のイベントID。指定すると登録時に取りこぼしたイベントを Replay に詰める
+	LastEventID string
+	// Replay 登録時に再送するイベント（Send より先に送る）
+	Replay []Event
+	// ResyncReason 再送できない場合の理由。空でなければ resync_required を送ってクライアントに再取得させる
+	ResyncReason string
+
+	registered chan struct{}
 }
 
 // Hub は部屋ごとのSSE接続を管理
@@ -30,12 +42,31 @@ type Hub struct {
 	unregister chan *Client
 	broadcast  chan BroadcastMessage
 	mu         sync.RWMutex
+
+	// 部屋全体へのイベントの再送用ログ（Last-Event-ID での再接続用）
+	epoch        string
+	seq          uint64
+	logs         map[uuid.UUID]*eventLog
+	pruned       map[uuid.UUID]prunedLog // ログを捨てた部屋ごとの、捨てたログの最新のイベントの番号
+	forgottenSeq uint64                  // pruned からも消した部屋の番号のうち最新のもの
 }
 
 // BroadcastMessage はブロードキャストするメッセージ
 type BroadcastMessage struct {
-	RoomID uuid.UUID
-	Event  Event
+	RoomID        uuid.UUID
+	Event         Event
+	UserID        *uuid.UUID // 指定時はそのユーザーの接続にだけ送る
+	ExcludeUserID *uuid.UUID // 指定時はそのユーザー以外の接続に送る
+}
+
+// PresenceData はメンバーの接続状態の変化（member_online / member_offline）
+type PresenceData struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// PresenceSnapshot は接続時に送る、部屋に接続中のメンバーの一覧（presence）
+type PresenceSnapshot struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
 }
 
 // NewHub は新しいHubを作成
@@ -45,46 +76,109 @@ func NewHub() *Hub {
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan BroadcastMessage),
+		epoch:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		logs:       make(map[uuid.UUID]*eventLog),
+		pruned:     make(map[uuid.UUID]prunedLog),
 	}
 }
 
 // Run はHubのメインループ
 func (h *Hub) Run() {
+	pruneTicker := time.NewTicker(time.Minute)
+	defer pruneTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
 			h.mu.Lock()
+			// 取りこぼしたイベントは登録と同時に取り出す（以降のイベントは Send に届くので漏れも重複もない）
+			if client.LastEventID != "" && !client.Waiting {
+				client.Replay, client.ResyncReason = h.eventsSince(client.RoomID, client.LastEventID)
+			}
 			if _, ok := h.rooms[client.RoomID]; !ok {
 				h.rooms[client.RoomID] = make(map[uuid.UUID]*Client)
 			}
+			// 再読み込みなどで同じユーザーの接続が入れ替わる場合はオンラインのまま
+			previous, reconnected := h.rooms[client.RoomID][client.UserID]
 			h.rooms[client.RoomID][client.UserID] = client
+			wasOnline := reconnected && !previous.Waiting
+			if !client.Waiting && !wasOnline {
+				h.deliver(BroadcastMessage{
+					RoomID:        client.RoomID,
+					Event:         Event{Type: "member_online", Data: PresenceData{UserID: client.UserID}},
+					ExcludeUserID: &client.UserID,
+				})
+			} else if client.Waiting && wasOnline {
+				h.deliver(BroadcastMessage{
+					RoomID: client.RoomID,
+					Event:  Event{Type: "member_offline", Data: PresenceData{UserID: client.UserID}},
+				})
+			}
 			h.mu.Unlock()
+			if client.registered != nil {
+				close(client.registered)
+			}
 
 		case client := <-h.unregister:
 			h.mu.Lock()
 			if room, ok := h.rooms[client.RoomID]; ok {
-				if _, ok := room[client.UserID]; ok {
+				// 入れ替わった後の古い接続の登録解除では、新しい接続を消さない
+				if current, ok := room[client.UserID]; ok && current == client {
 					delete(room, client.UserID)
 					close(client.Send)
 					if len(room) == 0 {
 						delete(h.rooms, client.RoomID)
+					} else if !client.Waiting {
+						h.deliver(BroadcastMessage{
+							RoomID: client.RoomID,
+							Event:  Event{Type: "member_offline", Data: PresenceData{UserID: client.UserID}},
+						})
 					}
 				}
 			}
 			h.mu.Unlock()
 
 		case message := <-h.broadcast:
-			h.mu.RLock()
-			if room, ok := h.rooms[message.RoomID]; ok {
-				for _, client := range room {
-					select {
-					case client.Send <- message.Event:
-					default:
-						// クライアントのバッファがいっぱいの場合はスキップ
-					}
-				}
+			h.mu.Lock()
+			if message.UserID == nil && message.ExcludeUserID == nil {
+				// 部屋全体へのイベントは再送用に通し番号を振って残す
+				message.Event = h.record(message.RoomID, message.Event, time.Now())
+			} else {
+				// 本人宛て・入力中などの一時的なイベントは ID を付けない（クライアントの Last-Event-ID を進めない）
+				message.Event.ID = ""
+			}
+			h.deliver(message)
+			h.mu.Unlock()
+
+		case now := <-pruneTicker.C:
+			h.mu.Lock()
+			h.pruneLogs(now)
+			h.mu.Unlock()
+		}
+	}
+}
+
+// deliver は部屋の接続にイベントを送る（呼び出し側で mu をロックしておく）
+func (h *Hub) deliver(message BroadcastMessage) {
+	room, ok := h.rooms[message.RoomID]
+	if !ok {
+		return
+	}
+	for _, client := range room {
+		if message.UserID != nil {
+			if client.UserID != *message.UserID {
+				continue
 			}
-			h.mu.RUnlock()
+		} else if client.Waiting {
+			continue
+		}
+		if message.ExcludeUserID != nil && client.UserID == *message.ExcludeUserID {
+			continue
+		}
+		select {
+		case client.Send <- message.Event:
+		default:
+			// クライアントのバッファがいっぱいの場合はスキップ
 		}
 	}
 }
@@ -97,9 +191,42 @@ func (h *Hub) BroadcastToRoom(roomID uuid.UUID, event Event) {
 	}
 }
 
-// Register はクライアントを登録
+// SendToUser は部屋に接続している特定のユーザーにだけイベントを送る（キャンセル待ちの接続にも届く）
+func (h *Hub) SendToUser(roomID, userID uuid.UUID, event Event) {
+	h.broadcast <- BroadcastMessage{
+		RoomID: roomID,
+		Event:  event,
+		UserID: &userID,
+	}
+}
+
+// BroadcastToOthers は送信者本人以外の部屋のメンバーにイベントを送る（入力中の表示など）
+func (h *Hub) BroadcastToOthers(roomID, senderID uuid.UUID, event Event) {
+	h.broadcast <- BroadcastMessage{
+		RoomID:        roomID,
+		Event:         event,
+		ExcludeUserID: &senderID,
+	}
+}
+
+// OnlineUserIDs は部屋に接続中のメンバー（キャンセル待ちを除く）のユーザーIDを返す
+func (h *Hub) OnlineUserIDs(roomID uuid.UUID) []uuid.UUID {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	userIDs := make([]uuid.UUID, 0, len(h.rooms[roomID]))
+	for userID, client := range h.rooms[roomID] {
+		if !client.Waiting {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// Register はクライアントを登録する。登録が済んで Replay / ResyncReason が決まるまで待つ
 func (h *Hub) Register(client *Client) {
+	client.registered = make(chan struct{})
 	h.register <- client
+	<-client.registered
 }
 
 // Unregister はクライアントを登録解除
@@ -115,7 +242,11 @@ func SerializeEvent(event Event) (string, error) {
 	}
 
 	// すべてのイベントを "message" イベントとして送信し、
-	// データ内の type フィールドで区別する
+	// データ内の type フィールドで区別する。
+	// ID のないイベントは id 行を省く（空の id 行はクライアントの Last-Event-ID を消してしまう）
+	if event.ID == "" {
+		return fmt.Sprintf("event: message\ndata: %s\n\n", string(data)), nil
+	}
 	return fmt.Sprintf("id: %s\nevent: message\ndata: %s\n\n",
 		event.ID, string(data)), nil
 }
@@ -0,0 +1,304 @@
+package sse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHubWaitingClientReceivesOnlyOwnEvents(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	roomID := uuid.New()
+	member := &Client{ID: uuid.New(), UserID: uuid.New(), RoomID: roomID, Send: make(chan Event, 10)}
+	waiter := &Client{ID: uuid.New(), UserID: uuid.New(), RoomID: roomID, Send: make(chan Event, 10), Waiting: true}
+	hub.Register(member)
+	hub.Register(waiter)
+
+	hub.BroadcastToRoom(roomID, Event{Type: "message"})
+	hub.SendToUser(roomID, waiter.UserID, Event{Type: "waitlist_offer"})
+
+	receive := func(client *Client) []string {
+		var types []string
+		timeout := time.After(100 * time.Millisecond)
+		for {
+			select {
+			case event := <-client.Send:
+				types = append(types, event.Type)
+			case <-timeout:
+				return types
+			}
+		}
+	}
+	if got := receive(member); len(got) != 1 || got[0] != "message" {
+		t.Errorf("メンバーが受け取ったイベント = %v, want [message]", got)
+	}
+	if got := receive(waiter); len(got) != 1 || got[0] != "waitlist_offer" {
+		t.Errorf("キャンセル待ちが受け取ったイベント = %v, want [waitlist_offer]", got)
+	}
+}
+
+func TestHubPresence(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	roomID := uuid.New()
+	newClient := func(userID uuid.UUID, waiting bool) *Client {
+		return &Client{ID: uuid.New(), UserID: userID, RoomID: roomID, Send: make(chan Event, 10), Waiting: waiting}
+	}
+	receive := func(client *Client) []Event {
+		var events []Event
+		timeout := time.After(100 * time.Millisecond)
+		for {
+			select {
+			case event, ok := <-client.Send:
+				if !ok {
+					return events
+				}
+				events = append(events, event)
+			case <-timeout:
+				return events
+			}
+		}
+	}
+	assertPresence := func(client *Client, wantType string, wantUserID uuid.UUID) {
+		t.Helper()
+		events := receive(client)
+		if len(events) != 1 || events[0].Type != wantType {
+			t.Fatalf("受け取ったイベント = %+v, want [%s]", events, wantType)
+		}
+		if data, ok := events[0].Data.(PresenceData); !ok || data.UserID != wantUserID {
+			t.Errorf("%s のデータ = %+v, want user_id %v", wantType, events[0].Data, wantUserID)
+		}
+	}
+
+	hostID, guestID := uuid.New(), uuid.New()
+	host := newClient(hostID, false)
+	hub.Register(host)
+	guest := newClient(guestID, false)
+	hub.Register(guest)
+	assertPresence(host, "member_online", guestID)
+	if events := receive(guest); len(events) != 0 {
+		t.Errorf("本人に member_online が届いている: %+v", events)
+	}
+
+	// キャンセル待ちの接続はオンライン扱いにしない
+	waiter := newClient(uuid.New(), true)
+	hub.Register(waiter)
+	if events := receive(host); len(events) != 0 {
+		t.Errorf("キャンセル待ちの接続でイベントが届いている: %+v", events)
+	}
+
+	online := hub.OnlineUserIDs(roomID)
+	if len(online) != 2 {
+		t.Errorf("OnlineUserIDs() = %v, want ホストと参加者の2人", online)
+	}
+
+	// 再読み込みで接続が入れ替わってもオンラインのまま
+	reloaded := newClient(guestID, false)
+	hub.Register(reloaded)
+	hub.Unregister(guest)
+	if events := receive(host); len(events) != 0 {
+		t.Errorf("接続の入れ替えでイベントが届いている: %+v", events)
+	}
+
+	// 入力中の表示は送信者本人には届かない
+	hub.BroadcastToOthers(roomID, guestID, Event{Type: "typing"})
+	if events := receive(host); len(events) != 1 || events[0].Type != "typing" {
+		t.Errorf("ホストが受け取ったイベント = %+v, want [typing]", events)
+	}
+	if events := receive(reloaded); len(events) != 0 {
+		t.Errorf("送信者本人に typing が届いている: %+v", events)
+	}
+
+	hub.Unregister(reloaded)
+	assertPresence(host, "member_offline", guestID)
+}
+
+func TestHubReplayAfterReconnect(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	roomID, userID := uuid.New(), uuid.New()
+	first := &Client{ID: uuid.New(), UserID: userID, RoomID: roomID, Send: make(chan Event, 10)}
+	hub.Register(first)
+
+	hub.BroadcastToRoom(roomID, Event{Type: "message", Data: 1})
+	hub.SendToUser(roomID, userID, Event{ID: "personal", Type: "waitlist_update"})
+	var lastEventID string
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-first.Send:
+			if event.Type == "message" {
+				lastEventID = event.ID
+			} else if event.ID != "" {
+				t.Errorf("本人宛てのイベントに ID が付いている: %q", event.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("イベントが届かない")
+		}
+	}
+	if lastEventID == "" {
+		t.Fatal("部屋全体へのイベントに ID が付いていない")
+	}
+	hub.Unregister(first)
+
+	// 切断中のイベントは再接続時に Replay で受け取る
+	hub.BroadcastToRoom(roomID, Event{Type: "message", Data: 2})
+	hub.BroadcastToRoom(roomID, Event{Type: "member_update", Data: 3})
+	hub.BroadcastToRoom(uuid.New(), Event{Type: "message", Data: 4})
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantReplay  []interface{}
+		wantResync  string
+	}{
+		{name: "最後のイベントより後を再送する", lastEventID: lastEventID, wantReplay: []interface{}{2, 3}},
+		{name: "別のサーバープロセスのID", lastEventID: "other-1", wantResync: ResyncReasonUnknownID},
+		{name: "解釈できないID", lastEventID: "xxx", wantResync: ResyncReasonUnknownID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{ID: uuid.New(), UserID: userID, RoomID: roomID, Send: make(chan Event, 10), LastEventID: tt.lastEventID}
+			hub.Register(client)
+			defer hub.Unregister(client)
+
+			if client.ResyncReason != tt.wantResync {
+				t.Errorf("ResyncReason = %q, want %q", client.ResyncReason, tt.wantResync)
+			}
+			if len(client.Replay) != len(tt.wantReplay) {
+				t.Fatalf("Replay = %+v, want %v", client.Replay, tt.wantReplay)
+			}
+			for i, want := range tt.wantReplay {
+				if client.Replay[i].Data != want {
+					t.Errorf("Replay[%d] = %+v, want data %v", i, client.Replay[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHubReplayGapTooBig(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	roomID := uuid.New()
+	listener := &Client{ID: uuid.New(), UserID: uuid.New(), RoomID: roomID, Send: make(chan Event, 1)}
+	hub.Register(listener)
+	hub.BroadcastToRoom(roomID, Event{Type: "message"})
+	lastEventID := (<-listener.Send).ID
+	hub.Unregister(listener)
+
+	for i := 0; i < eventLogSize+1; i++ {
+		hub.BroadcastToRoom(roomID, Event{Type: "message"})
+	}
+
+	client := &Client{ID: uuid.New(), UserID: uuid.New(), RoomID: roomID, Send: make(chan Event, 10), LastEventID: lastEventID}
+	hub.Register(client)
+	if client.ResyncReason != ResyncReasonGapTooBig || len(client.Replay) != 0 {
+		t.Errorf("ResyncReason = %q, Replay = %d 件, want %q と0件", client.ResyncReason, len(client.Replay), ResyncReasonGapTooBig)
+	}
+}
+
+func TestSerializeEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{name: "IDあり", event: Event{ID: "e-1", Type: "message"}, want: "id: e-1\nevent: message\ndata: {\"id\":\"e-1\",\"type\":\"message\",\"data\":null}\n\n"},
+		{name: "IDなしは id 行を省く", event: Event{Type: "typing"}, want: "event: message\ndata: {\"id\":\"\",\"type\":\"typing\",\"data\":null}\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SerializeEvent(tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("SerializeEvent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHubPruneLogs(t *testing.T) {
+	hub := NewHub()
+	roomID := uuid.New()
+	start := time.Now()
+	event := hub.record(roomID, Event{Type: "message"}, start)
+	hub.record(roomID, Event{Type: "message"}, start)
+
+	hub.pruneLogs(start.Add(eventLogRetention - time.Second))
+	if _, ok := hub.logs[roomID]; !ok {
+		t.Fatal("保持期間内のログが捨てられた")
+	}
+
+	hub.pruneLogs(start.Add(eventLogRetention))
+	if _, ok := hub.logs[roomID]; ok {
+		t.Fatal("保持期間を過ぎたログが残っている")
+	}
+
+	// 捨てたログのイベントを取りこぼしている接続には再取得を促す
+	if _, reason := hub.eventsSince(roomID, event.ID); reason != ResyncReasonGapTooBig {
+		t.Errorf("eventsSince() reason = %q, want %q", reason, ResyncReasonGapTooBig)
+	}
+	if _, reason := hub.eventsSince(roomID, hub.eventID(hub.seq)); reason != "" {
+		t.Errorf("最新まで受け取っていれば再送は不要: reason = %q", reason)
+	}
+}
+
+func TestHubPruneLogsPerRoom(t *testing.T) {
+	hub := NewHub()
+	roomA, roomB := uuid.New(), uuid.New()
+	start := time.Now()
+	lastA := hub.record(roomA, Event{Type: "message"}, start)
+	seenB := hub.record(roomB, Event{Type: "message"}, start)
+	hub.record(roomB, Event{Type: "message"}, start)
+
+	hub.pruneLogs(start.Add(eventLogRetention))
+	if len(hub.logs) != 0 {
+		t.Fatalf("保持期間を過ぎたログが残っている: %d 件", len(hub.logs))
+	}
+
+	// 別の部屋のログを捨てたことで、取りこぼしのない部屋の再接続に再取得を促さない
+	if _, reason := hub.eventsSince(roomA, lastA.ID); reason != "" {
+		t.Errorf("部屋A: reason = %q, want 再送不要", reason)
+	}
+	if _, reason := hub.eventsSince(roomB, seenB.ID); reason != ResyncReasonGapTooBig {
+		t.Errorf("部屋B: reason = %q, want %q", reason, ResyncReasonGapTooBig)
+	}
+
+	// ログを捨てた後に新しいイベントがあっても、捨てたイベントの取りこぼしは見逃さない
+	hub.record(roomB, Event{Type: "message"}, start.Add(eventLogRetention+time.Second))
+	if _, reason := hub.eventsSince(roomB, seenB.ID); reason != ResyncReasonGapTooBig {
+		t.Errorf("ログを作り直した部屋B: reason = %q, want %q", reason, ResyncReasonGapTooBig)
+	}
+	if events, reason := hub.eventsSince(roomB, hub.eventID(hub.seq-1)); reason != "" || len(events) != 1 {
+		t.Errorf("ログを作り直した部屋B: reason = %q, events = %d 件, want 再送1件", reason, len(events))
+	}
+}
+
+func TestHubPrunedLogsLimit(t *testing.T) {
+	hub := NewHub()
+	start := time.Now()
+	oldest := uuid.New()
+	hub.record(oldest, Event{Type: "message"}, start)
+	hub.pruneLogs(start.Add(eventLogRetention))
+
+	for i := 0; i < maxPrunedLogs; i++ {
+		hub.record(uuid.New(), Event{Type: "message"}, start.Add(time.Minute))
+	}
+	hub.pruneLogs(start.Add(time.Minute + eventLogRetention))
+
+	// 記録の上限を超えたら古い部屋から Hub 全体の番号にまとめる
+	if len(hub.pruned) != maxPrunedLogs {
+		t.Errorf("記録 = %d 件, want %d", len(hub.pruned), maxPrunedLogs)
+	}
+	if _, ok := hub.pruned[oldest]; ok || hub.forgottenSeq != 1 {
+		t.Errorf("最も古い記録がまとめられていない: forgottenSeq = %d", hub.forgottenSeq)
+	}
+}
@@ -25,8 +25,21 @@ type GCSUploader struct {
 	config *config.GCSConfig
 }
 
+var (
+	// ErrFileTooLarge アップロードサイズが上限を超えている
+	ErrFileTooLarge = errors.New("ファイルサイズが制限を超えています")
+	// ErrUnsupportedContentType 許可されていないMIMEタイプ
+	ErrUnsupportedContentType = errors.New("許可されていないファイル形式です")
+)
+
 // NewGCSUploader 新しいGCSアップローダーを作成
 func NewGCSUploader(ctx context.Context) (*GCSUploader, error) {
+	return NewGCSUploaderWithConfig(ctx, &config.AppConfig.GCS)
+}
+
+// NewGCSUploaderWithConfig 設定を指定してGCSアップローダーを作成
+// config.Init を経由しないバッチ処理（room-cleanupなど）から利用する
+func NewGCSUploaderWithConfig(ctx context.Context, cfg *config.GCSConfig) (*GCSUploader, error) {
 	client, err := storage.NewClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("GCSクライアントの初期化に失敗しました: %w", err)
@@ -34,7 +47,7 @@ func NewGCSUploader(ctx context.Context) (*GCSUploader, error) {
 
 	return &GCSUploader{
 		client: client,
-		config: &config.AppConfig.GCS,
+		config: cfg,
 	}, nil
 }
 
@@ -47,15 +60,116 @@ type UploadResult struct {
 
 // UploadAvatar アバター画像をアップロード
 func (u *GCSUploader) UploadAvatar(ctx context.Context, userID string, file multipart.File, header *multipart.FileHeader) (*UploadResult, error) {
+	data, contentType, err := u.readPublicImage(file, header)
+	if err != nil {
+		return nil, err
+	}
+
+	// オブジェクトパス生成（環境別フォルダ付き）
+	objectPath := path.Join(
+		u.config.AssetPrefix, // dev/prod などを先頭に
+		"avatars",
+		userID,
+		objectFileName(header.Filename, "avatar", contentType, data),
+	)
+
+	if err := u.writePublicObject(ctx, objectPath, contentType, data); err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		URL:         u.PublicURL(objectPath),
+		ObjectPath:  objectPath,
+		ContentType: contentType,
+	}, nil
+}
+
+// ChatImageUploadResult チャット画像のアップロード結果
+type ChatImageUploadResult struct {
+	UploadResult
+	SizeBytes           int64  `json:"size_bytes"`
+	Width               int    `json:"width"`
+	Height              int    `json:"height"`
+	ThumbnailURL        string `json:"thumbnail_url"`
+	ThumbnailObjectPath string `json:"thumbnail_object_path"`
+	ThumbnailWidth      int    `json:"thumbnail_width"`
+	ThumbnailHeight     int    `json:"thumbnail_height"`
+}
+
+// UploadChatImage チャットに添付する画像とサムネイルをアップロード
+// サイズ・MIMEタイプの検証はアバターと同じ設定を使う
+func (u *GCSUploader) UploadChatImage(ctx context.Context, roomID string, file multipart.File, header *multipart.FileHeader) (*ChatImageUploadResult, error) {
+	data, contentType, err := u.readPublicImage(file, header)
+	if err != nil {
+		return nil, err
+	}
+
+	thumb, err := makeThumbnail(data, chatThumbnailMaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	name := objectFileName(header.Filename, "image", contentType, data)
+	objectPath := path.Join(u.config.AssetPrefix, "chat", roomID, name)
+	thumbnailPath := path.Join(u.config.AssetPrefix, "chat", roomID, "thumbs", baseNameSansExt(name)+".jpg")
+
+	if err := u.writePublicObject(ctx, objectPath, contentType, data); err != nil {
+		return nil, err
+	}
+	if err := u.writePublicObject(ctx, thumbnailPath, "image/jpeg", thumb.Data); err != nil {
+		// 本体だけ残らないように削除しておく
+		_ = u.DeleteObjects(ctx, []string{objectPath})
+		return nil, err
+	}
+
+	return &ChatImageUploadResult{
+		UploadResult: UploadResult{
+			URL:         u.PublicURL(objectPath),
+			ObjectPath:  objectPath,
+			ContentType: contentType,
+		},
+		SizeBytes:           int64(len(data)),
+		Width:               thumb.SourceWidth,
+		Height:              thumb.SourceHeight,
+		ThumbnailURL:        u.PublicURL(thumbnailPath),
+		ThumbnailObjectPath: thumbnailPath,
+		ThumbnailWidth:      thumb.Width,
+		ThumbnailHeight:     thumb.Height,
+	}, nil
+}
+
+// DeleteObjects 公開バケットのオブジェクトを削除
+// 既に存在しないオブジェクトは削除済みとして扱う
+func (u *GCSUploader) DeleteObjects(ctx context.Context, objectPaths []string) error {
+	bucket := u.client.Bucket(u.config.Bucket)
+
+	var errs []error
+	for _, objectPath := range objectPaths {
+		if objectPath == "" {
+			continue
+		}
+		if err := bucket.Object(objectPath).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			errs = append(errs, fmt.Errorf("GCS削除エラー（%s）: %w", objectPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readPublicImage 公開バケット向けの画像を読み込み、サイズとMIMEタイプを検証する
+func (u *GCSUploader) readPublicImage(file multipart.File, header *multipart.FileHeader) ([]byte, string, error) {
 	// ファイルサイズチェック
+	tooLarge := fmt.Errorf("%w（最大 %d MB）", ErrFileTooLarge, u.config.MaxUploadBytes/(1<<20))
 	if header.Size > u.config.MaxUploadBytes {
-		return nil, fmt.Errorf("ファイルサイズが制限を超えています（最大 %d MB）", u.config.MaxUploadBytes/(1<<20))
+		return nil, "", tooLarge
 	}
 
 	// ファイルを読み込み
 	buf := bytes.NewBuffer(nil)
 	if _, err := io.CopyN(buf, file, u.config.MaxUploadBytes+1); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("ファイル読み込みエラー: %w", err)
+		return nil, "", fmt.Errorf("ファイル読み込みエラー: %w", err)
+	}
+	if int64(buf.Len()) > u.config.MaxUploadBytes {
+		return nil, "", tooLarge
 	}
 
 	// Content-Type判定
@@ -71,58 +185,46 @@ func (u *GCSUploader) UploadAvatar(ctx context.Context, userID string, file mult
 
 	// MIMEタイプチェック
 	if _, ok := u.config.AllowedMIMEs[contentType]; !ok {
-		return nil, errors.New("許可されていないファイル形式です")
-	}
-
-	// 拡張子取得
-	ext := getExtension(header.Filename, contentType)
-
-	// ハッシュ計算（重複防止）
-	h := md5.New()
-	h.Write(buf.Bytes())
-	hash12 := hex.EncodeToString(h.Sum(nil))[:12]
-
-	// ベースネーム取得
-	base := baseNameSansExt(header.Filename)
-	if base == "" {
-		base = "avatar"
+		return nil, "", ErrUnsupportedContentType
 	}
-	base = sanitizeName(base)
 
-	// オブジェクトパス生成（環境別フォルダ付き）
-	objectPath := path.Join(
-		u.config.AssetPrefix, // dev/prod などを先頭に
-		"avatars",
-		userID,
-		fmt.Sprintf("%s-%s%s", base, hash12, ext),
-	)
+	return buf.Bytes(), contentType, nil
+}
 
-	// GCSにアップロード
-	bucket := u.client.Bucket(u.config.Bucket)
-	obj := bucket.Object(objectPath)
-	writer := obj.NewWriter(ctx)
+// writePublicObject 公開バケットにオブジェクトを書き込む
+func (u *GCSUploader) writePublicObject(ctx context.Context, objectPath, contentType string, data []byte) error {
+	writer := u.client.Bucket(u.config.Bucket).Object(objectPath).NewWriter(ctx)
 
 	// メタデータ設定
 	writer.CacheControl = "public, max-age=31536000, immutable"
 	writer.ContentType = contentType
 
 	// 書き込み
-	if _, err := writer.Write(buf.Bytes()); err != nil {
-		return nil, fmt.Errorf("GCS書き込みエラー: %w", err)
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("GCS書き込みエラー: %w", err)
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("GCSクローズエラー: %w", err)
+		return fmt.Errorf("GCSクローズエラー: %w", err)
 	}
+	return nil
+}
 
-	// 公開URL生成
-	publicURL := u.PublicURL(objectPath)
+// objectFileName 内容のハッシュ付きのオブジェクト名を生成（重複防止）
+func objectFileName(filename, fallbackBase, contentType string, data []byte) string {
+	ext := getExtension(filename, contentType)
 
-	return &UploadResult{
-		URL:         publicURL,
-		ObjectPath:  objectPath,
-		ContentType: contentType,
-	}, nil
+	h := md5.New()
+	h.Write(data)
+	hash12 := hex.EncodeToString(h.Sum(nil))[:12]
+
+	base := baseNameSansExt(filename)
+	if base == "" {
+		base = fallbackBase
+	}
+	base = sanitizeName(base)
+
+	return fmt.Sprintf("%s-%s%s", base, hash12, ext)
 }
 
 // UploadReportAttachment 通報添付ファイルをアップロード
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/nfnt/resize"
+	_ "golang.org/x/image/webp"
+)
+
+// chatThumbnailMaxSize チャット画像サムネイルの長辺の最大ピクセル数
+const chatThumbnailMaxSize = 320
+
+// ErrInvalidImage 画像としてデコードできない
+var ErrInvalidImage = errors.New("画像を読み込めませんでした")
+
+// thumbnail 生成したサムネイルと元画像の寸法
+type thumbnail struct {
+	Data         []byte
+	Width        int
+	Height       int
+	SourceWidth  int
+	SourceHeight int
+}
+
+// makeThumbnail 長辺がmaxSize以下になるよう縮小したJPEGサムネイルを生成
+// 元画像がmaxSize以下の場合は拡大せずそのままの寸法で再エンコードする
+func makeThumbnail(data []byte, maxSize uint) (*thumbnail, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	bounds := src.Bounds()
+	resized := resize.Thumbnail(maxSize, maxSize, src, resize.Lanczos3)
+
+	// JPEGは透過を持てないため白背景に合成する
+	canvas := image.NewRGBA(resized.Bounds())
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), resized, resized.Bounds().Min, draw.Over)
+
+	buf := bytes.NewBuffer(nil)
+	if err := jpeg.Encode(buf, canvas, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("サムネイル生成エラー: %w", err)
+	}
+
+	return &thumbnail{
+		Data:         buf.Bytes(),
+		Width:        canvas.Bounds().Dx(),
+		Height:       canvas.Bounds().Dy(),
+		SourceWidth:  bounds.Dx(),
+		SourceHeight: bounds.Dy(),
+	}, nil
+}
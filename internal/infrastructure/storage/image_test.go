@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 200})
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatalf("PNGエンコードに失敗: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		wantWidth, wantHeight int
+	}{
+		{name: "横長は長辺を上限に縮小", width: 1280, height: 720, wantWidth: 320, wantHeight: 180},
+		{name: "縦長は長辺を上限に縮小", width: 400, height: 800, wantWidth: 160, wantHeight: 320},
+		{name: "上限以下は拡大しない", width: 100, height: 50, wantWidth: 100, wantHeight: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, err := makeThumbnail(encodePNG(t, tt.width, tt.height), chatThumbnailMaxSize)
+			if err != nil {
+				t.Fatalf("サムネイル生成に失敗: %v", err)
+			}
+			if thumb.SourceWidth != tt.width || thumb.SourceHeight != tt.height {
+				t.Errorf("元画像サイズ = %dx%d, want %dx%d", thumb.SourceWidth, thumb.SourceHeight, tt.width, tt.height)
+			}
+			if thumb.Width != tt.wantWidth || thumb.Height != tt.wantHeight {
+				t.Errorf("サムネイルサイズ = %dx%d, want %dx%d", thumb.Width, thumb.Height, tt.wantWidth, tt.wantHeight)
+			}
+
+			decoded, err := jpeg.Decode(bytes.NewReader(thumb.Data))
+			if err != nil {
+				t.Fatalf("サムネイルがJPEGとしてデコードできない: %v", err)
+			}
+			if b := decoded.Bounds(); b.Dx() != tt.wantWidth || b.Dy() != tt.wantHeight {
+				t.Errorf("デコード結果のサイズ = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestMakeThumbnailInvalidImage(t *testing.T) {
+	_, err := makeThumbnail([]byte("not an image"), chatThumbnailMaxSize)
+	if !errors.Is(err, ErrInvalidImage) {
+		t.Fatalf("err = %v, want ErrInvalidImage", err)
+	}
+}
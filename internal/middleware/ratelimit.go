@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -38,44 +39,71 @@ func NewRateLimiter(requestsPerMinute int) *RateLimiter {
 
 // 指定されたIPアドレスのリクエストを許可するかチェック
 func (rl *RateLimiter) Allow(ip string) bool {
-	rl.mu.RLock()
-	visitor, exists := rl.visitors[ip]
-	rl.mu.RUnlock()
+	return rl.AllowAll(ip)
+}
 
-	if !exists {
-		rl.mu.Lock()
-		visitor = &Visitor{
-			requests: make([]time.Time, 0),
+// AllowAll すべてのキーが上限に達していないときだけ、各キーにリクエストを記録して許可する。
+// どれか1つでも上限に達していれば、ほかのキーの回数も使わない
+func (rl *RateLimiter) AllowAll(keys ...string) bool {
+	// 同じキーを二重にロックしないよう重複を除き、ロックの順序を揃える
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	visitors := make([]*Visitor, 0, len(sorted))
+	for i, key := range sorted {
+		if i > 0 && key == sorted[i-1] {
+			continue
 		}
-		rl.visitors[ip] = visitor
-		rl.mu.Unlock()
+		visitor := rl.getVisitor(key)
+		visitor.mu.Lock()
+		defer visitor.mu.Unlock()
+		visitors = append(visitors, visitor)
 	}
 
-	visitor.mu.Lock()
-	defer visitor.mu.Unlock()
-
 	now := time.Now()
 	cutoff := now.Add(-rl.window)
 
-	// 古いリクエストを削除
-	validRequests := make([]time.Time, 0)
-	for _, requestTime := range visitor.requests {
-		if requestTime.After(cutoff) {
-			validRequests = append(validRequests, requestTime)
+	// 古いリクエストを削除し、レート制限をチェック
+	for _, visitor := range visitors {
+		validRequests := make([]time.Time, 0)
+		for _, requestTime := range visitor.requests {
+			if requestTime.After(cutoff) {
+				validRequests = append(validRequests, requestTime)
+			}
 		}
-	}
-	visitor.requests = validRequests
+		visitor.requests = validRequests
 
-	// レート制限チェック
-	if len(visitor.requests) >= rl.rate {
-		return false
+		if len(visitor.requests) >= rl.rate {
+			return false
+		}
 	}
 
 	// リクエストを記録
-	visitor.requests = append(visitor.requests, now)
+	for _, visitor := range visitors {
+		visitor.requests = append(visitor.requests, now)
+	}
 	return true
 }
 
+// getVisitor キーの訪問者データを取得し、無ければ作る
+func (rl *RateLimiter) getVisitor(key string) *Visitor {
+	rl.mu.RLock()
+	visitor, exists := rl.visitors[key]
+	rl.mu.RUnlock()
+	if exists {
+		return visitor
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if visitor, exists = rl.visitors[key]; !exists {
+		visitor = &Visitor{
+			requests: make([]time.Time, 0),
+		}
+		rl.visitors[key] = visitor
+	}
+	return visitor
+}
+
 // cleanupVisitors 古い訪問者データを定期的にクリーンアップ
 func (rl *RateLimiter) cleanupVisitors() {
 	ticker := time.NewTicker(time.Minute * 5)
@@ -110,17 +138,19 @@ func (rl *RateLimiter) cleanupVisitors() {
 
 // RateLimitConfig レート制限の設定
 type RateLimitConfig struct {
-	General int // 一般的なエンドポイントのレート制限（分間）
-	Auth    int // 認証関連エンドポイントのレート制限（分間）
-	Contact int // お問合せエンドポイントのレート制限（分間）
+	General  int // 一般的なエンドポイントのレート制限（分間）
+	Auth     int // 認証関連エンドポイントのレート制限（分間）
+	Contact  int // お問合せエンドポイントのレート制限（分間）
+	RoomCode int // ルームコードの照会のレート制限（分間）。総当たりでコードを探られないよう厳しめにする
 }
 
 // DefaultRateLimitConfig 環境変数からレート制限設定を取得
 func DefaultRateLimitConfig() *RateLimitConfig {
 	config := &RateLimitConfig{
-		General: 120, // デフォルト: 120req/min
-		Auth:    20,  // デフォルト: 20req/min
-		Contact: 3,   // デフォルト: 3req/min（お問合せは厳しめ）
+		General:  120, // デフォルト: 120req/min
+		Auth:     20,  // デフォルト: 20req/min
+		Contact:  3,   // デフォルト: 3req/min（お問合せは厳しめ）
+		RoomCode: 10,  // デフォルト: 10req/min（コードの総当たり対策）
 	}
 
 	// 環境変数から設定を取得
@@ -142,6 +172,12 @@ func DefaultRateLimitConfig() *RateLimitConfig {
 		}
 	}
 
+	if roomCodeStr := os.Getenv("RATE_LIMIT_ROOM_CODE"); roomCodeStr != "" {
+		if roomCode, err := strconv.Atoi(roomCodeStr); err == nil && roomCode > 0 {
+			config.RoomCode = roomCode
+		}
+	}
+
 	return config
 }
 
@@ -202,6 +238,31 @@ func ContactRateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Ha
 	}
 }
 
+// RoomCodeRateLimitMiddleware ルームコード照会用のレート制限ミドルウェア。
+// X-Forwarded-For はクライアントが書き換えられるため、認証ミドルウェアの後に置き、
+// ログインユーザーごとの上限を主にして IP アドレスごとの上限も併せて掛ける
+func RoomCodeRateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 片方の上限で断ったリクエストで、もう片方の回数を使わないよう両方まとめて判定する
+			keys := []string{"ip:" + getClientIP(r)}
+			if dbUser, ok := GetDBUserFromContext(r.Context()); ok && dbUser != nil {
+				keys = append(keys, "user:"+dbUser.ID.String())
+			}
+
+			if !limiter.AllowAll(keys...) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", "60")
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"error":"RATE_LIMITED","message":"ルームコードの入力回数が上限に達しました。しばらく待ってから再試行してください。"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // getClientIP クライアントのIPアドレスを取得
 func getClientIP(r *http.Request) string {
 	// プロキシヘッダーをチェック
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+func TestRoomCodeRateLimitMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RoomCodeRateLimitMiddleware(NewRateLimiter(3))(next)
+
+	request := func(ip string) int {
+		r := httptest.NewRequest("POST", "/api/rooms/code", nil)
+		r.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := request("192.0.2.1"); got != http.StatusOK {
+			t.Fatalf("%d回目: status = %d, want %d", i+1, got, http.StatusOK)
+		}
+	}
+	if got := request("192.0.2.1"); got != http.StatusTooManyRequests {
+		t.Errorf("上限超過: status = %d, want %d", got, http.StatusTooManyRequests)
+	}
+	// 上限は IP アドレスごと
+	if got := request("192.0.2.2"); got != http.StatusOK {
+		t.Errorf("別の IP: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestRoomCodeRateLimitMiddlewarePerUser(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RoomCodeRateLimitMiddleware(NewRateLimiter(3))(next)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+
+	request := func(user *models.User, forwardedFor string) int {
+		r := httptest.NewRequest("POST", "/api/rooms/code", nil)
+		r.RemoteAddr = "192.0.2.1:12345"
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+		r = r.WithContext(context.WithValue(r.Context(), DBUserContextKey, user))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// X-Forwarded-For を毎回変えても、同じユーザーの上限はリセットされない
+	for i := 0; i < 3; i++ {
+		if got := request(user, fmt.Sprintf("198.51.100.%d", i+1)); got != http.StatusOK {
+			t.Fatalf("%d回目: status = %d, want %d", i+1, got, http.StatusOK)
+		}
+	}
+	if got := request(user, "198.51.100.99"); got != http.StatusTooManyRequests {
+		t.Errorf("X-Forwarded-For を変えた上限超過: status = %d, want %d", got, http.StatusTooManyRequests)
+	}
+	// 別のユーザーは別の IP からなら照会できる
+	other := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	if got := request(other, "203.0.113.1"); got != http.StatusOK {
+		t.Errorf("別のユーザー: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestRoomCodeRateLimitMiddlewareIPLimitKeepsUserQuota(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RoomCodeRateLimitMiddleware(NewRateLimiter(3))(next)
+
+	request := func(user *models.User, forwardedFor string) int {
+		r := httptest.NewRequest("POST", "/api/rooms/code", nil)
+		r.RemoteAddr = "192.0.2.1:12345"
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+		r = r.WithContext(context.WithValue(r.Context(), DBUserContextKey, user))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// 同じ IP の別のユーザーが IP の上限を使い切る
+	for i := 0; i < 3; i++ {
+		if got := request(&models.User{BaseModel: models.BaseModel{ID: uuid.New()}}, "198.51.100.1"); got != http.StatusOK {
+			t.Fatalf("%d回目: status = %d, want %d", i+1, got, http.StatusOK)
+		}
+	}
+
+	// IP の上限で断られた照会は、ユーザーの回数を使わない
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+	for i := 0; i < 3; i++ {
+		if got := request(user, "198.51.100.1"); got != http.StatusTooManyRequests {
+			t.Fatalf("IP の上限超過 %d回目: status = %d, want %d", i+1, got, http.StatusTooManyRequests)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if got := request(user, "203.0.113.1"); got != http.StatusOK {
+			t.Errorf("別の IP からの %d回目: status = %d, want %d", i+1, got, http.StatusOK)
+		}
+	}
+}
@@ -6,17 +6,31 @@ func AllModels() []interface{} {
 	return []interface{}{
 		&Platform{},
 		&GameVersion{},
+		&Monster{},
+		&Quest{},
 		&User{},
 		&Room{},
 		&RoomMember{},
+		&RoomWaitlistEntry{},
+		&RoomInvite{},
+		&RoomJoinRequest{},
 		&RoomMessage{},
+		&RoomMessageEdit{},
+		&RoomMessageAttachment{},
+		&RoomReadMarker{},
+		&NGWord{},
+		&NGWordHit{},
 		&MessageReaction{},
 		&ReactionType{},
 		&UserBlock{},
+		&HostBan{},
 		&PlayerName{},
 		&UserFollow{},
 		&UserActivity{},
 		&RoomLog{},
+		&RoomSessionSummary{},
+		&HuntRecord{},
+		&HuntRecordParticipant{},
 		&PasswordReset{},
 		&UserReport{},
 		&ReportAttachment{},
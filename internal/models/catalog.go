@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Monster ゲームバージョンごとのモンスター図鑑。部屋のターゲットモンスターの正規化に使う
+type Monster struct {
+	BaseModel
+	GameVersionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_monsters_game_version_name" json:"game_version_id"`
+	Name          string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_monsters_game_version_name" json:"name"`
+	DisplayOrder  int       `gorm:"not null;default:0" json:"display_order"`
+	IsActive      bool      `gorm:"not null;default:true" json:"is_active"`
+
+	// リレーション
+	GameVersion GameVersion `gorm:"foreignKey:GameVersionID" json:"-"`
+}
+
+// Quest ゲームバージョンごとのクエスト一覧。メインターゲットのモンスターがあれば MonsterID に持つ
+type Quest struct {
+	BaseModel
+	GameVersionID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_quests_game_version_name" json:"game_version_id"`
+	MonsterID     *uuid.UUID `gorm:"type:uuid;index" json:"monster_id"`
+	Name          string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_quests_game_version_name" json:"name"`
+	Rank          string     `gorm:"type:varchar(20);not null;default:''" json:"rank"` // 例: 村★5、集会所上位
+	DisplayOrder  int        `gorm:"not null;default:0" json:"display_order"`
+	IsActive      bool       `gorm:"not null;default:true" json:"is_active"`
+
+	// リレーション
+	GameVersion GameVersion `gorm:"foreignKey:GameVersionID" json:"-"`
+	Monster     *Monster    `gorm:"foreignKey:MonsterID" json:"monster,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// HostBan ホストが自分の部屋すべてへの参加を禁止したユーザー
+type HostBan struct {
+	BaseModel
+	HostUserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_host_bans_host_banned" json:"host_user_id"`
+	BannedUserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_host_bans_host_banned" json:"banned_user_id"`
+	Reason       *string   `gorm:"type:text" json:"reason"`
+
+	// リレーション
+	Host   User `gorm:"foreignKey:HostUserID" json:"-"`
+	Banned User `gorm:"foreignKey:BannedUserID" json:"banned"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxHuntClearSeconds 記録できるクリアタイムの上限（クエストの制限時間 50分）
+const MaxHuntClearSeconds = 50 * 60
+
+// HuntRecord 部屋でクリアしたクエストの記録。ホストが部屋のメンバーを参加者として登録する
+type HuntRecord struct {
+	BaseModel
+	RoomID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"room_id"`
+	GameVersionID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"game_version_id"`
+	MonsterID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"monster_id"`
+	QuestID          *uuid.UUID `gorm:"type:uuid" json:"quest_id"`
+	ClearTimeSeconds int        `gorm:"not null" json:"clear_time_seconds"`
+	RecordedByUserID uuid.UUID  `gorm:"type:uuid;not null" json:"recorded_by_user_id"` // 記録したホスト
+	ClearedAt        time.Time  `gorm:"not null" json:"cleared_at"`
+
+	// リレーション
+	Room         Room                    `gorm:"foreignKey:RoomID" json:"-"`
+	GameVersion  GameVersion             `gorm:"foreignKey:GameVersionID" json:"-"`
+	Monster      Monster                 `gorm:"foreignKey:MonsterID" json:"monster"`
+	Quest        *Quest                  `gorm:"foreignKey:QuestID" json:"quest,omitempty"`
+	Participants []HuntRecordParticipant `gorm:"foreignKey:HuntRecordID" json:"participants"`
+}
+
+// HuntRecordParticipant クエストをクリアしたメンバー。記録時点の RoomMember に紐づける
+type HuntRecordParticipant struct {
+	BaseModel
+	HuntRecordID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_hunt_participants_record_user" json:"hunt_record_id"`
+	RoomMemberID uuid.UUID `gorm:"type:uuid;not null;index" json:"room_member_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_hunt_participants_record_user;index" json:"user_id"`
+
+	// リレーション
+	RoomMember RoomMember `gorm:"foreignKey:RoomMemberID" json:"-"`
+	User       User       `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// ClearTime クリアタイム
+func (h *HuntRecord) ClearTime() time.Duration {
+	return time.Duration(h.ClearTimeSeconds) * time.Second
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// NGワードの照合方法（ng_words.match_type）
+const (
+	NGWordMatchExact     = "exact"     // 単語として一致（前後が文字・数字でない）
+	NGWordMatchSubstring = "substring" // 部分一致
+	NGWordMatchRegex     = "regex"     // 正規表現
+)
+
+// NGワードに一致したときの扱い（ng_words.action）
+const (
+	NGWordActionBlock = "block" // 投稿・保存を拒否する
+	NGWordActionMask  = "mask"  // 一致した部分を伏せ字にして受け付ける
+)
+
+// NGワードを適用する入力欄（ng_word_hits.target）
+const (
+	NGWordTargetChat            = "chat"
+	NGWordTargetRoomName        = "room_name"
+	NGWordTargetRoomDescription = "room_description"
+	NGWordTargetDisplayName     = "display_name"
+	NGWordTargetBio             = "bio"
+)
+
+// NGWord 管理者が登録するNGワード
+type NGWord struct {
+	BaseModel
+	Pattern         string     `gorm:"type:varchar(200);not null" json:"pattern"`
+	MatchType       string     `gorm:"type:varchar(20);not null;default:'substring'" json:"match_type"`
+	Action          string     `gorm:"type:varchar(20);not null;default:'mask'" json:"action"`
+	IsActive        bool       `gorm:"not null;default:true" json:"is_active"`
+	Note            *string    `gorm:"type:varchar(200)" json:"note"`
+	CreatedByUserID *uuid.UUID `gorm:"type:uuid" json:"created_by_user_id"`
+
+	// リレーション
+	CreatedBy *User `gorm:"foreignKey:CreatedByUserID" json:"-"`
+}
+
+// IsValidNGWordMatchType 照合方法が定義済みの値かどうか
+func IsValidNGWordMatchType(matchType string) bool {
+	switch matchType {
+	case NGWordMatchExact, NGWordMatchSubstring, NGWordMatchRegex:
+		return true
+	}
+	return false
+}
+
+// IsValidNGWordAction 一致時の扱いが定義済みの値かどうか
+func IsValidNGWordAction(action string) bool {
+	return action == NGWordActionBlock || action == NGWordActionMask
+}
+
+// NGWordHit NGワードに一致した入力の記録。管理画面で確認する。
+// NGワードを削除しても記録は残すため、一致した時点のパターンと扱いを保存する
+type NGWordHit struct {
+	BaseModel
+	NGWordID uuid.UUID  `gorm:"type:uuid;not null;index" json:"ng_word_id"`
+	Pattern  string     `gorm:"type:varchar(200);not null" json:"pattern"`
+	Action   string     `gorm:"type:varchar(20);not null" json:"action"`
+	UserID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	RoomID   *uuid.UUID `gorm:"type:uuid" json:"room_id"`
+	Target   string     `gorm:"type:varchar(30);not null" json:"target"`
+	Content  string     `gorm:"type:text;not null" json:"content"` // 伏せ字にする前の入力
+
+	// リレーション
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
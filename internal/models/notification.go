@@ -12,6 +12,12 @@ const (
 	NotificationRoomKicked        = "room_kicked"         // 部屋からホストにより退出させられた
 	NotificationRoomDismissed     = "room_dismissed"      // 参加していた部屋がホストにより解散された
 	NotificationFollow            = "follow"              // フォローされた
+	NotificationRoomStartingSoon  = "room_starting_soon"  // 参加している部屋の開始予定時刻が近づいた
+	NotificationWaitlistOffered   = "waitlist_offered"    // キャンセル待ちしていた部屋に空きが出て席が確保された
+	NotificationJoinApproved      = "join_approved"       // 参加申請がホストに承認され、部屋に参加した
+	NotificationJoinRejected      = "join_rejected"       // 参加申請がホストに見送られた
+	NotificationSessionSummary    = "session_summary"     // 途中で抜けた部屋が解散され、セッションのまとめができた
+	NotificationMention           = "mention"             // 部屋のチャットでメンションされた
 )
 
 // Notification ユーザー宛のお知らせ
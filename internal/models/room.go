@@ -14,6 +14,54 @@ const (
 	DismissReasonInactive = "inactive" // 一定期間活動がなく自動解散
 )
 
+// ホスト交代の理由（room_logs の transfer_host の details.reason）
+const (
+	HostChangeReasonTransfer   = "transfer"   // ホストが指名して譲った
+	HostChangeReasonSuccession = "succession" // ホストの退出により、参加期間が最も長いメンバーが引き継いだ
+)
+
+// 部屋の公開範囲（rooms.visibility）
+const (
+	RoomVisibilityPublic    = "public"    // 部屋一覧・サイトマップ・活動フィードに掲載する
+	RoomVisibilityFollowers = "followers" // ホストのフォロワー（承認済み）にのみ公開する
+	RoomVisibilityUnlisted  = "unlisted"  // リンクを知っている人だけが開ける。一覧には掲載しない
+)
+
+// SlowModeOption 低速モードの選択肢
+type SlowModeOption struct {
+	Seconds int
+	Label   string
+}
+
+// RoomSlowModeOptions ホストが選べる低速モードの間隔（Seconds が 0 ならオフ）
+var RoomSlowModeOptions = []SlowModeOption{
+	{Seconds: 0, Label: "オフ"},
+	{Seconds: 5, Label: "5秒"},
+	{Seconds: 10, Label: "10秒"},
+	{Seconds: 30, Label: "30秒"},
+	{Seconds: 60, Label: "1分"},
+	{Seconds: 300, Label: "5分"},
+}
+
+// IsValidSlowModeSeconds 低速モードの間隔として選べる値かどうか
+func IsValidSlowModeSeconds(seconds int) bool {
+	for _, option := range RoomSlowModeOptions {
+		if seconds == option.Seconds {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRoomVisibility 部屋の公開範囲として有効な値かどうか
+func IsValidRoomVisibility(visibility string) bool {
+	switch visibility {
+	case RoomVisibilityPublic, RoomVisibilityFollowers, RoomVisibilityUnlisted:
+		return true
+	}
+	return false
+}
+
 type Room struct {
 	BaseModel
 	RoomCode        string     `gorm:"type:varchar(20);uniqueIndex;not null" json:"room_code"`
@@ -25,6 +73,8 @@ type Room struct {
 	CurrentPlayers  int        `gorm:"not null;default:0" json:"current_players"`
 	PasswordHash    *string    `gorm:"type:varchar(255)" json:"password_hash,omitempty"`
 	TargetMonster   *string    `gorm:"type:varchar(100)" json:"target_monster"`
+	MonsterID       *uuid.UUID `gorm:"type:uuid;index" json:"monster_id"` // 図鑑に一致した場合のみ。自由入力は TargetMonster のみ
+	QuestID         *uuid.UUID `gorm:"type:uuid;index" json:"quest_id"`
 	RankRequirement *string    `gorm:"type:varchar(20)" json:"rank_requirement"`
 	IsActive        bool       `gorm:"not null;default:true" json:"is_active"`
 	IsClosed        bool       `gorm:"not null;default:false" json:"is_closed"`
@@ -32,10 +82,24 @@ type Room struct {
 	OGVersion       int        `gorm:"not null;default:0" json:"og_version"`
 	DismissedAt     *time.Time `json:"dismissed_at"`
 	DismissReason   *string    `gorm:"type:varchar(20)" json:"dismiss_reason"`
+	// 開始予定時刻（nil は作成直後から募集中の部屋）
+	ScheduledStartAt    *time.Time `gorm:"index" json:"scheduled_start_at"`
+	ScheduledEndAt      *time.Time `json:"scheduled_end_at"`
+	StartReminderSentAt *time.Time `json:"-"`
+	// 公開範囲（RoomVisibilityPublic / RoomVisibilityFollowers / RoomVisibilityUnlisted）
+	Visibility string `gorm:"type:varchar(20);not null;default:'public';index" json:"visibility"`
+	// 参加にホストの承認が必要な部屋（パスワードの代わりにホストが参加申請を審査する）
+	RequiresApproval bool `gorm:"not null;default:false" json:"requires_approval"`
+	// 募集する武器種のコード（WeaponTypes の順に重複なしで保存する）。API では GetWantedWeapons で返す
+	WantedWeapons JSONB `gorm:"type:text;default:'[]'" json:"-"`
+	// 低速モード：ホスト以外のメンバーが次のメッセージを送れるまでの秒数（0 はオフ）
+	SlowModeSeconds int `gorm:"not null;default:0" json:"slow_mode_seconds"`
 
 	// リレーション
 	GameVersion GameVersion   `gorm:"foreignKey:GameVersionID" json:"game_version"`
 	Host        User          `gorm:"foreignKey:HostUserID" json:"host"`
+	Monster     *Monster      `gorm:"foreignKey:MonsterID" json:"monster,omitempty"`
+	Quest       *Quest        `gorm:"foreignKey:QuestID" json:"quest,omitempty"`
 	Members     []RoomMember  `gorm:"foreignKey:RoomID" json:"members,omitempty"`
 	Messages    []RoomMessage `gorm:"foreignKey:RoomID" json:"messages,omitempty"`
 	Logs        []RoomLog     `gorm:"foreignKey:RoomID" json:"logs,omitempty"`
@@ -71,6 +135,34 @@ func (r *Room) IsAutoDismissed() bool {
 	return !r.IsActive && r.DismissReason != nil && *r.DismissReason == DismissReasonInactive
 }
 
+// IsUpcoming 開始予定時刻が now より後の「開始前」の部屋かどうか
+func (r *Room) IsUpcoming(now time.Time) bool {
+	return r.ScheduledStartAt != nil && r.ScheduledStartAt.After(now)
+}
+
+// GetVisibility 公開範囲を取得（未設定の部屋は公開として扱う）
+func (r *Room) GetVisibility() string {
+	if r.Visibility == "" {
+		return RoomVisibilityPublic
+	}
+	return r.Visibility
+}
+
+// SlowModeInterval 低速モードの間隔。オフなら 0
+func (r *Room) SlowModeInterval() time.Duration {
+	return time.Duration(r.SlowModeSeconds) * time.Second
+}
+
+// IsListed 部屋一覧・サイトマップ・公開の活動フィードに誰でも見える形で掲載する部屋かどうか
+func (r *Room) IsListed() bool {
+	return r.GetVisibility() == RoomVisibilityPublic
+}
+
+// IsFollowersOnly ホストのフォロワーにのみ公開する部屋かどうか
+func (r *Room) IsFollowersOnly() bool {
+	return r.GetVisibility() == RoomVisibilityFollowers
+}
+
 func (r *Room) IsFull() bool {
 	return r.CurrentPlayers >= r.MaxPlayers
 }
@@ -103,6 +195,57 @@ func (r *Room) GetRankRequirement() string {
 	return ""
 }
 
+// GetWantedWeapons 募集する武器種のコードを取得
+func (r *Room) GetWantedWeapons() []string {
+	items, ok := r.WantedWeapons.Data.([]interface{})
+	if !ok {
+		if weapons, ok := r.WantedWeapons.Data.([]string); ok {
+			return weapons
+		}
+		return []string{}
+	}
+	weapons := make([]string, 0, len(items))
+	for _, item := range items {
+		if code, ok := item.(string); ok {
+			weapons = append(weapons, code)
+		}
+	}
+	return weapons
+}
+
+// SetWantedWeapons 募集する武器種を設定する。不明なコードと重複は除き、WeaponTypes の順に並べる
+func (r *Room) SetWantedWeapons(codes []string) {
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+	weapons := make([]string, 0, len(wanted))
+	for _, weapon := range WeaponTypes {
+		if wanted[weapon.Code] {
+			weapons = append(weapons, weapon.Code)
+		}
+	}
+	r.WantedWeapons.Data = weapons
+}
+
+// WeaponSlots 募集する武器種ごとに、参加中のメンバーの武器種（memberWeapons）で埋まっているかを返す
+func (r *Room) WeaponSlots(memberWeapons []string) []WeaponSlot {
+	taken := make(map[string]bool, len(memberWeapons))
+	for _, code := range memberWeapons {
+		taken[code] = true
+	}
+	wanted := r.GetWantedWeapons()
+	slots := make([]WeaponSlot, 0, len(wanted))
+	for _, code := range wanted {
+		slots = append(slots, WeaponSlot{
+			WeaponType: code,
+			Name:       WeaponTypeName(code),
+			Filled:     taken[code],
+		})
+	}
+	return slots
+}
+
 // RoomWithJoinStatus 参加状態を含む部屋情報
 type RoomWithJoinStatus struct {
 	Room
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomInvite ホストが発行する部屋の招待リンク。招待リンクからの参加はパスワードの確認を省略する
+type RoomInvite struct {
+	BaseModel
+	RoomID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"room_id"`
+	Token           string     `gorm:"type:varchar(64);not null;unique;index" json:"token"`
+	CreatedByUserID uuid.UUID  `gorm:"type:uuid;not null" json:"created_by_user_id"`
+	ExpiresAt       *time.Time `json:"expires_at"`                         // nil は無期限
+	MaxUses         int        `gorm:"not null;default:0" json:"max_uses"` // 0 は回数無制限
+	UseCount        int        `gorm:"not null;default:0" json:"use_count"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+}
+
+// IsRevoked ホストが無効にした招待リンクかどうか
+func (i *RoomInvite) IsRevoked() bool {
+	return i.RevokedAt != nil
+}
+
+// IsExpired now の時点で有効期限が過ぎているかどうか
+func (i *RoomInvite) IsExpired(now time.Time) bool {
+	return i.ExpiresAt != nil && !i.ExpiresAt.After(now)
+}
+
+// IsExhausted 使用回数の上限に達しているかどうか
+func (i *RoomInvite) IsExhausted() bool {
+	return i.MaxUses > 0 && i.UseCount >= i.MaxUses
+}
+
+// IsUsable now の時点で招待リンクとして使えるかどうか
+func (i *RoomInvite) IsUsable(now time.Time) bool {
+	return !i.IsRevoked() && !i.IsExpired(now) && !i.IsExhausted()
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 参加申請の状態（room_join_requests.status）
+const (
+	JoinRequestStatusPending   = "pending"   // ホストの審査待ち
+	JoinRequestStatusApproved  = "approved"  // 承認され、部屋に参加した
+	JoinRequestStatusRejected  = "rejected"  // ホストが見送った
+	JoinRequestStatusCancelled = "cancelled" // 申請者が取り下げた、または部屋が解散された
+)
+
+// RoomJoinRequest 承認制の部屋への参加申請。ホストが承認すると参加が完了する
+type RoomJoinRequest struct {
+	BaseModel
+	RoomID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"room_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status    string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	DecidedAt *time.Time `json:"decided_at"`
+
+	// リレーション
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// IsPending ホストの審査待ちかどうか
+func (r *RoomJoinRequest) IsPending() bool {
+	return r.Status == JoinRequestStatusPending
+}
@@ -10,7 +10,7 @@ import (
 const (
 	MemberStatusActive = "active"
 	MemberStatusLeft   = "left"
-	MemberStatusKicked = "kicked" // ホストにより退出させられた（同じ部屋には再参加できない）
+	MemberStatusKicked = "kicked" // ホストにより退出させられた（期限までは同じ部屋に再参加できない）
 )
 
 type RoomMember struct {
@@ -22,6 +22,12 @@ type RoomMember struct {
 	Status       string     `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
 	JoinedAt     time.Time  `json:"joined_at"`
 	LeftAt       *time.Time `json:"left_at"`
+	// 参加時に申告する装備（未申告は nil）
+	WeaponType *string `gorm:"type:varchar(30)" json:"weapon_type"`
+	HunterRank *int    `json:"hunter_rank"`
+	// キック時にホストが添えた理由と再参加を禁止する期限（期限が nil なら無期限）
+	KickReason  *string    `gorm:"type:text" json:"kick_reason,omitempty"`
+	KickedUntil *time.Time `json:"kicked_until,omitempty"`
 
 	// リレーション
 	Room Room `gorm:"foreignKey:RoomID" json:"room"`
@@ -30,3 +36,11 @@ type RoomMember struct {
 	// 表示用フィールド（DBには保存されない）
 	DisplayName string `gorm:"-" json:"display_name,omitempty"`
 }
+
+// IsKickedAt now の時点で、キックにより同じ部屋への再参加が禁止されているかどうか
+func (m *RoomMember) IsKickedAt(now time.Time) bool {
+	if m.Status != MemberStatusKicked {
+		return false
+	}
+	return m.KickedUntil == nil || m.KickedUntil.After(now)
+}
@@ -1,18 +1,90 @@
 package models
 
 import (
+	"time"
+	"unicode/utf8"
+
 	"github.com/google/uuid"
 )
 
+// RoomMessageQuoteLength 返信先として引用するメッセージの最大文字数
+const RoomMessageQuoteLength = 80
+
+// メッセージ種別（RoomMessage.MessageType）
+const (
+	RoomMessageTypeChat   = "chat"   // 通常のチャット
+	RoomMessageTypeSystem = "system" // 入退室などのシステムメッセージ
+	RoomMessageTypeImage  = "image"  // 画像添付（本文はキャプション。空でもよい）
+)
+
+// RoomMessageImageQuote 画像メッセージにキャプションが無い場合の引用表示
+const RoomMessageImageQuote = "[画像]"
+
 type RoomMessage struct {
 	BaseModel
-	RoomID      uuid.UUID `gorm:"type:uuid;not null" json:"room_id"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
-	Message     string    `gorm:"type:text;not null" json:"message"`
-	MessageType string    `gorm:"type:varchar(20);not null;default:'chat'" json:"message_type"`
-	IsDeleted   bool      `gorm:"not null;default:false" json:"is_deleted"`
+	RoomID          uuid.UUID              `gorm:"type:uuid;not null" json:"room_id"`
+	UserID          uuid.UUID              `gorm:"type:uuid;not null" json:"user_id"`
+	Message         string                 `gorm:"type:text;not null" json:"message"`
+	MessageType     string                 `gorm:"type:varchar(20);not null;default:'chat'" json:"message_type"`
+	IsDeleted       bool                   `gorm:"not null;default:false" json:"is_deleted"`
+	EditedAt        *time.Time             `json:"edited_at,omitempty"`
+	DeletedByUserID *uuid.UUID             `gorm:"type:uuid" json:"deleted_by_user_id,omitempty"` // 削除した本人またはホスト
+	ReplyToID       *uuid.UUID             `gorm:"type:uuid;index" json:"reply_to_id,omitempty"`  // 返信先のメッセージ
+	Mentions        []string               `gorm:"-" json:"mentions,omitempty"`                   // メンションされたメンバーの名前（表示用。保存しない）
+	ReplyTo         *RoomMessageQuote      `gorm:"-" json:"reply_to,omitempty"`                   // 返信先の引用（表示用。保存しない）
+	Reactions       []MessageReactionCount `gorm:"-" json:"reactions,omitempty"`                  // リアクションの集計（表示用。保存しない）
+
+	// リレーション
+	Room       Room                   `gorm:"foreignKey:RoomID" json:"room"`
+	User       User                   `gorm:"foreignKey:UserID" json:"user"`
+	Attachment *RoomMessageAttachment `gorm:"foreignKey:MessageID" json:"attachment,omitempty"`
+}
+
+// IsUserMessage メンバーが投稿したメッセージか（チャット・画像）
+func (m *RoomMessage) IsUserMessage() bool {
+	return m.MessageType == RoomMessageTypeChat || m.MessageType == RoomMessageTypeImage
+}
+
+// RoomMessageQuote 返信先として表示するメッセージの要約
+type RoomMessageQuote struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Message   string    `json:"message"` // 冒頭 RoomMessageQuoteLength 文字。削除済みなら空
+	IsDeleted bool      `json:"is_deleted"`
+}
+
+// Quote 返信先として引用する要約を作る（User を読み込んでおくこと）
+func (m *RoomMessage) Quote() *RoomMessageQuote {
+	name := m.User.DisplayName
+	if name == "" && m.User.Username != nil {
+		name = *m.User.Username
+	}
+	quote := &RoomMessageQuote{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		UserName:  name,
+		IsDeleted: m.IsDeleted,
+	}
+	if !m.IsDeleted {
+		quote.Message = m.Message
+		if quote.Message == "" && m.MessageType == RoomMessageTypeImage {
+			quote.Message = RoomMessageImageQuote
+		}
+		if utf8.RuneCountInString(quote.Message) > RoomMessageQuoteLength {
+			quote.Message = string([]rune(quote.Message)[:RoomMessageQuoteLength]) + "…"
+		}
+	}
+	return quote
+}
+
+// RoomMessageEdit メッセージ編集前の本文。モデレーター向けの編集履歴として残す
+type RoomMessageEdit struct {
+	BaseModel
+	MessageID       uuid.UUID `gorm:"type:uuid;not null;index" json:"message_id"`
+	EditorUserID    uuid.UUID `gorm:"type:uuid;not null" json:"editor_user_id"`
+	PreviousMessage string    `gorm:"type:text;not null" json:"previous_message"`
 
 	// リレーション
-	Room Room `gorm:"foreignKey:RoomID" json:"room"`
-	User User `gorm:"foreignKey:UserID" json:"user"`
+	Editor User `gorm:"foreignKey:EditorUserID" json:"editor"`
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// RoomMessageAttachment チャットに添付された画像（メッセージ1件につき1枚）
+// 実体は公開バケットの chat/<room_id>/ 配下にあり、メッセージ削除・部屋解散時に消す
+type RoomMessageAttachment struct {
+	BaseModel
+	MessageID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"message_id"`
+	RoomID              uuid.UUID `gorm:"type:uuid;not null;index" json:"room_id"`
+	ObjectPath          string    `gorm:"type:varchar(500);not null" json:"-"`
+	URL                 string    `gorm:"type:varchar(1000);not null" json:"url"`
+	ContentType         string    `gorm:"type:varchar(50);not null" json:"content_type"`
+	SizeBytes           int64     `gorm:"not null" json:"size_bytes"`
+	Width               int       `gorm:"not null;default:0" json:"width"`
+	Height              int       `gorm:"not null;default:0" json:"height"`
+	ThumbnailObjectPath string    `gorm:"type:varchar(500);not null" json:"-"`
+	ThumbnailURL        string    `gorm:"type:varchar(1000);not null" json:"thumbnail_url"`
+	ThumbnailWidth      int       `gorm:"not null;default:0" json:"thumbnail_width"`
+	ThumbnailHeight     int       `gorm:"not null;default:0" json:"thumbnail_height"`
+}
+
+// ObjectPaths 削除対象となるストレージ上のパス（本体とサムネイル）
+func (a *RoomMessageAttachment) ObjectPaths() []string {
+	return []string{a.ObjectPath, a.ThumbnailObjectPath}
+}
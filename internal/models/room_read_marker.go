@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomReadMarker メンバーが部屋のチャットをどこまで読んだか（部屋×ユーザーで1件）
+type RoomReadMarker struct {
+	RoomID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"room_id"`
+	UserID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	LastReadMessageID uuid.UUID `gorm:"type:uuid;not null" json:"last_read_message_id"`
+	LastReadAt        time.Time `gorm:"not null" json:"last_read_at"` // 最後に読んだメッセージの投稿日時
+	UpdatedAt         time.Time `json:"updated_at"`
+}
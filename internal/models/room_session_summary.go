@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomSessionSummary 解散した部屋のセッションのまとめ。解散時に RoomLog・RoomMember・RoomMessage から作成する
+type RoomSessionSummary struct {
+	BaseModel
+	RoomID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"room_id"`
+	HostUserID      uuid.UUID `gorm:"type:uuid;not null" json:"host_user_id"` // 解散時点のホスト
+	StartedAt       time.Time `gorm:"not null" json:"started_at"`
+	EndedAt         time.Time `gorm:"not null" json:"ended_at"`
+	DurationSeconds int64     `gorm:"not null;default:0" json:"duration_seconds"`
+	PeakPlayers     int       `gorm:"not null;default:0" json:"peak_players"`
+	MessageCount    int       `gorm:"not null;default:0" json:"message_count"`
+	// 参加したことのある全員（ホストを含む）。API では GetParticipants で返す
+	Participants JSONB `gorm:"type:text;default:'[]'" json:"-"`
+
+	// リレーション
+	Room Room `gorm:"foreignKey:RoomID" json:"-"`
+}
+
+// SessionParticipant セッションの参加者1人分
+type SessionParticipant struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	IsHost      bool      `json:"is_host"` // 解散時点のホスト
+	Kicked      bool      `json:"kicked"`  // ホストにより退出させられた
+}
+
+// GetParticipants 参加者の一覧を取得
+func (s *RoomSessionSummary) GetParticipants() []SessionParticipant {
+	if participants, ok := s.Participants.Data.([]SessionParticipant); ok {
+		return participants
+	}
+	// DB から読み込んだ値は汎用的な型になっているため、JSON を経由して変換する
+	raw, err := json.Marshal(s.Participants.Data)
+	if err != nil {
+		return []SessionParticipant{}
+	}
+	var participants []SessionParticipant
+	if err := json.Unmarshal(raw, &participants); err != nil || participants == nil {
+		return []SessionParticipant{}
+	}
+	return participants
+}
+
+// HasParticipant userID がセッションに参加していたかどうか
+func (s *RoomSessionSummary) HasParticipant(userID uuid.UUID) bool {
+	for _, p := range s.GetParticipants() {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Duration セッションの長さ
+func (s *RoomSessionSummary) Duration() time.Duration {
+	return time.Duration(s.DurationSeconds) * time.Second
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomWaitlistEntry.Status の値
+const (
+	WaitlistStatusWaiting   = "waiting"   // 空き待ち
+	WaitlistStatusOffered   = "offered"   // 空き席を確保中（OfferExpiresAt までに参加できる）
+	WaitlistStatusJoined    = "joined"    // 部屋に参加した
+	WaitlistStatusCancelled = "cancelled" // 本人の取り消し、または部屋の解散
+	WaitlistStatusExpired   = "expired"   // 確保期限までに参加しなかった
+)
+
+// RoomWaitlistEntry 満員の部屋のキャンセル待ち。登録順（created_at）に空き席を案内する
+type RoomWaitlistEntry struct {
+	BaseModel
+	RoomID         uuid.UUID  `gorm:"type:uuid;not null;index:idx_room_waitlist_room_status" json:"room_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'waiting';index:idx_room_waitlist_room_status" json:"status"`
+	OfferedAt      *time.Time `json:"offered_at"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at"`
+
+	// リレーション
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// IsActive 待機中または空き席を確保中かどうか
+func (e *RoomWaitlistEntry) IsActive() bool {
+	return e.Status == WaitlistStatusWaiting || e.Status == WaitlistStatusOffered
+}
+
+// HasValidOffer now の時点で確保した空き席が有効かどうか
+func (e *RoomWaitlistEntry) HasValidOffer(now time.Time) bool {
+	return e.Status == WaitlistStatusOffered && e.OfferExpiresAt != nil && e.OfferExpiresAt.After(now)
+}
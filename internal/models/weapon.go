@@ -0,0 +1,68 @@
+package models
+
+// 武器種（room_members.weapon_type / rooms.wanted_weapons）
+const (
+	WeaponGreatSword     = "great_sword"
+	WeaponLongSword      = "long_sword"
+	WeaponSwordAndShield = "sword_and_shield"
+	WeaponDualBlades     = "dual_blades"
+	WeaponHammer         = "hammer"
+	WeaponHuntingHorn    = "hunting_horn"
+	WeaponLance          = "lance"
+	WeaponGunlance       = "gunlance"
+	WeaponSwitchAxe      = "switch_axe"
+	WeaponChargeBlade    = "charge_blade"
+	WeaponInsectGlaive   = "insect_glaive"
+	WeaponLightBowgun    = "light_bowgun"
+	WeaponHeavyBowgun    = "heavy_bowgun"
+	WeaponBow            = "bow"
+)
+
+// MaxHunterRank 申告できるハンターランクの上限
+const MaxHunterRank = 999
+
+// WeaponType 武器種のコードと表示名
+type WeaponType struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// WeaponTypes 選択肢として表示する順の武器種一覧
+var WeaponTypes = []WeaponType{
+	{Code: WeaponGreatSword, Name: "大剣"},
+	{Code: WeaponLongSword, Name: "太刀"},
+	{Code: WeaponSwordAndShield, Name: "片手剣"},
+	{Code: WeaponDualBlades, Name: "双剣"},
+	{Code: WeaponHammer, Name: "ハンマー"},
+	{Code: WeaponHuntingHorn, Name: "狩猟笛"},
+	{Code: WeaponLance, Name: "ランス"},
+	{Code: WeaponGunlance, Name: "ガンランス"},
+	{Code: WeaponSwitchAxe, Name: "スラッシュアックス"},
+	{Code: WeaponChargeBlade, Name: "チャージアックス"},
+	{Code: WeaponInsectGlaive, Name: "操虫棍"},
+	{Code: WeaponLightBowgun, Name: "ライトボウガン"},
+	{Code: WeaponHeavyBowgun, Name: "ヘビィボウガン"},
+	{Code: WeaponBow, Name: "弓"},
+}
+
+// IsValidWeaponType 武器種として有効なコードかどうか
+func IsValidWeaponType(code string) bool {
+	return WeaponTypeName(code) != ""
+}
+
+// WeaponTypeName 武器種の表示名。不明なコードは空文字
+func WeaponTypeName(code string) string {
+	for _, weapon := range WeaponTypes {
+		if weapon.Code == code {
+			return weapon.Name
+		}
+	}
+	return ""
+}
+
+// WeaponSlot 部屋が募集している武器種の枠と、参加中のメンバーで埋まっているか
+type WeaponSlot struct {
+	WeaponType string `json:"weapon_type"`
+	Name       string `json:"name"`
+	Filled     bool   `json:"filled"`
+}
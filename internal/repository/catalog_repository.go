@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// catalogRepository はモンスター・クエスト図鑑の操作を行うリポジトリの実装
+type catalogRepository struct {
+	db DBInterface
+}
+
+// NewCatalogRepository は新しいCatalogRepositoryインスタンスを作成
+func NewCatalogRepository(db DBInterface) CatalogRepository {
+	return &catalogRepository{db: db}
+}
+
+// GetMonsters はゲームバージョンの有効なモンスターを表示順に取得
+func (r *catalogRepository) GetMonsters(gameVersionID uuid.UUID) ([]models.Monster, error) {
+	var monsters []models.Monster
+	err := r.db.GetConn().
+		Where("game_version_id = ? AND is_active = ?", gameVersionID, true).
+		Order("display_order ASC, name ASC").
+		Find(&monsters).Error
+	return monsters, err
+}
+
+// FindMonsterByID はIDでモンスターを検索
+func (r *catalogRepository) FindMonsterByID(id uuid.UUID) (*models.Monster, error) {
+	var monster models.Monster
+	err := r.db.GetConn().Where("id = ?", id).First(&monster).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("モンスターが見つかりません")
+		}
+		return nil, err
+	}
+	return &monster, nil
+}
+
+// FindMonsterByName はゲームバージョン内で名前が一致する有効なモンスターを検索（大文字小文字・前後の空白は無視）。
+// 見つからない場合は nil を返す
+func (r *catalogRepository) FindMonsterByName(gameVersionID uuid.UUID, name string) (*models.Monster, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	var monster models.Monster
+	err := r.db.GetConn().
+		Where("game_version_id = ? AND is_active = ? AND LOWER(name) = ?", gameVersionID, true, strings.ToLower(name)).
+		First(&monster).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &monster, nil
+}
+
+// GetQuests はゲームバージョンの有効なクエストを表示順に取得。monsterID を指定した場合はそのモンスターのクエストのみ
+func (r *catalogRepository) GetQuests(gameVersionID uuid.UUID, monsterID *uuid.UUID) ([]models.Quest, error) {
+	query := r.db.GetConn().
+		Where("game_version_id = ? AND is_active = ?", gameVersionID, true)
+	if monsterID != nil {
+		query = query.Where("monster_id = ?", *monsterID)
+	}
+	var quests []models.Quest
+	err := query.Order("display_order ASC, name ASC").Find(&quests).Error
+	return quests, err
+}
+
+// FindQuestByID はIDでクエストを検索
+func (r *catalogRepository) FindQuestByID(id uuid.UUID) (*models.Quest, error) {
+	var quest models.Quest
+	err := r.db.GetConn().Preload("Monster").Where("id = ?", id).First(&quest).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("クエストが見つかりません")
+		}
+		return nil, err
+	}
+	return &quest, nil
+}
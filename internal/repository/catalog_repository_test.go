@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCatalogRepository(t *testing.T) {
+	db, repo := newTestRepository(t, &models.GameVersion{}, &models.Monster{}, &models.Quest{})
+	mhp2g, mhp3 := uuid.New(), uuid.New()
+
+	newMonster := func(gameVersionID uuid.UUID, name string, order int, active bool) models.Monster {
+		monster := models.Monster{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: gameVersionID, Name: name, DisplayOrder: order, IsActive: true}
+		if err := db.Create(&monster).Error; err != nil {
+			t.Fatal(err)
+		}
+		if !active {
+			db.Model(&monster).Update("is_active", false)
+		}
+		return monster
+	}
+	rajang := newMonster(mhp2g, "ラージャン", 2, true)
+	newMonster(mhp2g, "ティガレックス", 1, true)
+	newMonster(mhp2g, "廃止モンスター", 3, false)
+	newMonster(mhp3, "ジンオウガ", 1, true)
+
+	for i, name := range []string{"ラージャンの狩猟（上位）", "採取ツアー"} {
+		quest := models.Quest{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: mhp2g, Name: name, DisplayOrder: i, IsActive: true}
+		if name != "採取ツアー" {
+			quest.MonsterID = &rajang.ID
+		}
+		if err := db.Create(&quest).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	monsters, err := repo.Catalog.GetMonsters(mhp2g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monsters) != 2 || monsters[0].Name != "ティガレックス" || monsters[1].Name != "ラージャン" {
+		t.Errorf("GetMonsters = %+v, want 有効なモンスターを表示順に2件", monsters)
+	}
+
+	found, err := repo.Catalog.FindMonsterByName(mhp2g, " ラージャン ")
+	if err != nil || found == nil || found.ID != rajang.ID {
+		t.Errorf("FindMonsterByName(ラージャン) = %v, %v", found, err)
+	}
+	for _, name := range []string{"ジンオウガ", "廃止モンスター", "ラージャン亜種", ""} {
+		found, err := repo.Catalog.FindMonsterByName(mhp2g, name)
+		if err != nil || found != nil {
+			t.Errorf("FindMonsterByName(%q) = %v, %v, want nil", name, found, err)
+		}
+	}
+
+	quests, err := repo.Catalog.GetQuests(mhp2g, nil)
+	if err != nil || len(quests) != 2 {
+		t.Errorf("GetQuests(nil) = %d件, %v, want 2件", len(quests), err)
+	}
+	quests, err = repo.Catalog.GetQuests(mhp2g, &rajang.ID)
+	if err != nil || len(quests) != 1 {
+		t.Fatalf("GetQuests(ラージャン) = %d件, %v, want 1件", len(quests), err)
+	}
+	quest, err := repo.Catalog.FindQuestByID(quests[0].ID)
+	if err != nil || quest.Monster == nil || quest.Monster.ID != rajang.ID {
+		t.Errorf("FindQuestByID = %+v, %v, want メインターゲット付き", quest, err)
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// hostBanRepository はホストのBANリストを扱うリポジトリの実装
+type hostBanRepository struct {
+	db DBInterface
+}
+
+// NewHostBanRepository は新しいHostBanRepositoryインスタンスを作成
+func NewHostBanRepository(db DBInterface) HostBanRepository {
+	return &hostBanRepository{db: db}
+}
+
+// BanUser ユーザーをホストのBANリストに追加する。既にBAN済みなら理由だけを更新する
+func (r *hostBanRepository) BanUser(hostUserID, bannedUserID uuid.UUID, reason *string) (*models.HostBan, error) {
+	if hostUserID == bannedUserID {
+		return nil, errors.New("自分自身をBANすることはできません")
+	}
+
+	var ban models.HostBan
+	err := r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("host_user_id = ? AND banned_user_id = ?", hostUserID, bannedUserID).
+			First(&ban).Error; err == nil {
+			ban.Reason = reason
+			return tx.Model(&ban).Update("reason", reason).Error
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("BANリストの確認に失敗しました: %w", err)
+		}
+
+		ban = models.HostBan{
+			HostUserID:   hostUserID,
+			BannedUserID: bannedUserID,
+			Reason:       reason,
+		}
+		return tx.Create(&ban).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+// UnbanUser ユーザーをホストのBANリストから外す
+func (r *hostBanRepository) UnbanUser(hostUserID, bannedUserID uuid.UUID) error {
+	result := r.db.GetConn().Where("host_user_id = ? AND banned_user_id = ?", hostUserID, bannedUserID).
+		Delete(&models.HostBan{})
+	if result.Error != nil {
+		return fmt.Errorf("BANの解除に失敗しました: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("解除対象のBANが見つかりません")
+	}
+	return nil
+}
+
+// IsBanned userID が hostUserID のBANリストに含まれているかどうか
+func (r *hostBanRepository) IsBanned(hostUserID, userID uuid.UUID) (bool, error) {
+	return isHostBanned(r.db.GetConn(), hostUserID, userID)
+}
+
+// GetBansByHost ホストのBANリストを新しい順に取得する
+func (r *hostBanRepository) GetBansByHost(hostUserID uuid.UUID) ([]models.HostBan, error) {
+	var bans []models.HostBan
+	err := r.db.GetConn().
+		Preload("Banned", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "display_name", "username", "avatar_url")
+		}).
+		Where("host_user_id = ?", hostUserID).
+		Order("created_at DESC").
+		Find(&bans).Error
+	if err != nil {
+		return nil, fmt.Errorf("BANリストの取得に失敗しました: %w", err)
+	}
+	return bans, nil
+}
+
+// isHostBanned 参加処理のトランザクション内からBANリストを確認する
+func isHostBanned(tx *gorm.DB, hostUserID, userID uuid.UUID) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.HostBan{}).
+		Where("host_user_id = ? AND banned_user_id = ?", hostUserID, userID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("BANリストの確認に失敗しました: %w", err)
+	}
+	return count > 0, nil
+}
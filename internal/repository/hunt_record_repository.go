@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// MonsterHuntStat ユーザーのモンスターごとの討伐数とベストタイム
+type MonsterHuntStat struct {
+	GameVersionID   uuid.UUID
+	GameVersionCode string
+	MonsterID       uuid.UUID
+	MonsterName     string
+	Clears          int64
+	BestSeconds     int
+}
+
+// GameVersionHuntStat ユーザーのゲームバージョンごとのクリア数
+type GameVersionHuntStat struct {
+	GameVersionID   uuid.UUID
+	GameVersionCode string
+	GameVersionName string
+	Clears          int64
+}
+
+// HuntStats ユーザーの狩猟記録の集計
+type HuntStats struct {
+	TotalClears   int64
+	ByGameVersion []GameVersionHuntStat
+	ByMonster     []MonsterHuntStat
+}
+
+// HuntLeaderboardEntry モンスターごとのランキングの1行（ユーザーのベストタイム）
+type HuntLeaderboardEntry struct {
+	Rank        int
+	UserID      uuid.UUID
+	DisplayName string
+	AvatarURL   *string
+	BestSeconds int
+	Clears      int64
+}
+
+// huntRecordRepository は狩猟記録の操作を行うリポジトリの実装
+type huntRecordRepository struct {
+	db DBInterface
+}
+
+// NewHuntRecordRepository は新しいHuntRecordRepositoryインスタンスを作成
+func NewHuntRecordRepository(db DBInterface) HuntRecordRepository {
+	return &huntRecordRepository{db: db}
+}
+
+// CreateHuntRecord ホストがクエストのクリアを記録する。participantUserIDs が空なら参加中のメンバー全員を参加者にする
+func (r *huntRecordRepository) CreateHuntRecord(record *models.HuntRecord, participantUserIDs []uuid.UUID) error {
+	return r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		if err := tx.Where("id = ?", record.RoomID).First(&room).Error; err != nil {
+			return err
+		}
+		if !room.IsActive {
+			return fmt.Errorf("この部屋は利用できません")
+		}
+		if room.HostUserID != record.RecordedByUserID {
+			return fmt.Errorf("部屋のホストのみがクリアを記録できます")
+		}
+		record.GameVersionID = room.GameVersionID
+
+		var monster models.Monster
+		if err := tx.Where("id = ? AND game_version_id = ? AND is_active = ?", record.MonsterID, room.GameVersionID, true).
+			First(&monster).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("この部屋のゲームバージョンのモンスターを選んでください")
+			}
+			return err
+		}
+		if record.QuestID != nil {
+			var quest models.Quest
+			if err := tx.Where("id = ? AND game_version_id = ? AND is_active = ?", *record.QuestID, room.GameVersionID, true).
+				First(&quest).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("この部屋のゲームバージョンのクエストを選んでください")
+				}
+				return err
+			}
+			if quest.MonsterID != nil && *quest.MonsterID != monster.ID {
+				return fmt.Errorf("クエストのメインターゲットと討伐したモンスターが一致しません")
+			}
+		}
+
+		var members []models.RoomMember
+		if err := tx.Where("room_id = ? AND status = ?", room.ID, models.MemberStatusActive).
+			Find(&members).Error; err != nil {
+			return err
+		}
+		memberByUser := make(map[uuid.UUID]models.RoomMember, len(members))
+		for _, member := range members {
+			memberByUser[member.UserID] = member
+		}
+
+		if len(participantUserIDs) == 0 {
+			for _, member := range members {
+				participantUserIDs = append(participantUserIDs, member.UserID)
+			}
+		}
+		seen := make(map[uuid.UUID]bool, len(participantUserIDs))
+		record.Participants = nil
+		for _, userID := range participantUserIDs {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			member, ok := memberByUser[userID]
+			if !ok {
+				return fmt.Errorf("部屋に参加していないユーザーは参加者にできません")
+			}
+			record.Participants = append(record.Participants, models.HuntRecordParticipant{
+				RoomMemberID: member.ID,
+				UserID:       userID,
+			})
+		}
+		if len(record.Participants) == 0 {
+			return fmt.Errorf("参加者がいません")
+		}
+
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		record.Monster = monster
+
+		log := models.RoomLog{
+			RoomID: room.ID,
+			UserID: &record.RecordedByUserID,
+			Action: "hunt_clear",
+			Details: models.JSONB{
+				Data: map[string]interface{}{
+					"hunt_record_id":     record.ID,
+					"monster_name":       monster.Name,
+					"clear_time_seconds": record.ClearTimeSeconds,
+					"participant_count":  len(record.Participants),
+				},
+			},
+		}
+		return tx.Create(&log).Error
+	})
+}
+
+// GetRecordsByRoom 部屋の狩猟記録を新しい順に取得
+func (r *huntRecordRepository) GetRecordsByRoom(roomID uuid.UUID) ([]models.HuntRecord, error) {
+	var records []models.HuntRecord
+	err := r.db.GetConn().
+		Preload("Monster").
+		Preload("Quest").
+		Preload("Participants.User").
+		Where("room_id = ?", roomID).
+		Order("cleared_at DESC").
+		Find(&records).Error
+	return records, err
+}
+
+// GetUserHuntStats ユーザーが参加者に含まれる狩猟記録を、ゲームバージョンごと・モンスターごとに集計する
+func (r *huntRecordRepository) GetUserHuntStats(userID uuid.UUID) (*HuntStats, error) {
+	stats := &HuntStats{}
+	conn := r.db.GetConn()
+
+	if err := conn.Table("hunt_record_participants AS p").
+		Select("hr.game_version_id, gv.code AS game_version_code, gv.name AS game_version_name, COUNT(*) AS clears").
+		Joins("JOIN hunt_records hr ON hr.id = p.hunt_record_id").
+		Joins("JOIN game_versions gv ON gv.id = hr.game_version_id").
+		Where("p.user_id = ?", userID).
+		Group("hr.game_version_id, gv.code, gv.name, gv.display_order").
+		Order("gv.display_order ASC").
+		Scan(&stats.ByGameVersion).Error; err != nil {
+		return nil, fmt.Errorf("ゲームバージョンごとの集計に失敗しました: %w", err)
+	}
+
+	if err := conn.Table("hunt_record_participants AS p").
+		Select("hr.game_version_id, gv.code AS game_version_code, hr.monster_id, m.name AS monster_name, COUNT(*) AS clears, MIN(hr.clear_time_seconds) AS best_seconds").
+		Joins("JOIN hunt_records hr ON hr.id = p.hunt_record_id").
+		Joins("JOIN game_versions gv ON gv.id = hr.game_version_id").
+		Joins("JOIN monsters m ON m.id = hr.monster_id").
+		Where("p.user_id = ?", userID).
+		Group("hr.game_version_id, gv.code, gv.display_order, hr.monster_id, m.name, m.display_order").
+		Order("gv.display_order ASC, m.display_order ASC, m.name ASC").
+		Scan(&stats.ByMonster).Error; err != nil {
+		return nil, fmt.Errorf("モンスターごとの集計に失敗しました: %w", err)
+	}
+
+	for _, stat := range stats.ByGameVersion {
+		stats.TotalClears += stat.Clears
+	}
+	return stats, nil
+}
+
+// GetMonsterLeaderboard ゲームバージョンのモンスターについて、ユーザーごとのベストタイムの速い順に上位 limit 件を返す。
+// 同じタイムは同じ順位にする
+func (r *huntRecordRepository) GetMonsterLeaderboard(gameVersionID, monsterID uuid.UUID, limit int) ([]HuntLeaderboardEntry, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var entries []HuntLeaderboardEntry
+	err := r.db.GetConn().Table("hunt_record_participants AS p").
+		Select("p.user_id, u.display_name, u.avatar_url, MIN(hr.clear_time_seconds) AS best_seconds, COUNT(*) AS clears").
+		Joins("JOIN hunt_records hr ON hr.id = p.hunt_record_id").
+		Joins("JOIN users u ON u.id = p.user_id").
+		Where("hr.game_version_id = ? AND hr.monster_id = ? AND u.is_active = ?", gameVersionID, monsterID, true).
+		Group("p.user_id, u.display_name, u.avatar_url").
+		Order("best_seconds ASC, clears DESC, u.display_name ASC").
+		Limit(limit).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("ランキングの取得に失敗しました: %w", err)
+	}
+
+	for i := range entries {
+		if i > 0 && entries[i].BestSeconds == entries[i-1].BestSeconds {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// huntTestEnv 狩猟記録のテスト用に、ゲームバージョン・図鑑・ホストと参加者のいる部屋を用意する
+type huntTestEnv struct {
+	t        *testing.T
+	db       *gorm.DB
+	repo     *Repository
+	version  models.GameVersion
+	other    models.GameVersion
+	rathalos models.Monster
+	tigrex   models.Monster
+	room     *models.Room
+	host     *models.User
+	guest    *models.User
+}
+
+func newHuntTestEnv(t *testing.T) *huntTestEnv {
+	t.Helper()
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Monster{}, &models.Quest{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomWaitlistEntry{}, &models.RoomJoinRequest{}, &models.HostBan{}, &models.HuntRecord{}, &models.HuntRecordParticipant{})
+	env := &huntTestEnv{t: t, db: db, repo: repo}
+
+	env.version = models.GameVersion{BaseModel: models.BaseModel{ID: uuid.New()}, Code: "MHP2G", Name: "MHP2G", DisplayOrder: 1, PlatformID: uuid.New(), IsActive: true}
+	env.other = models.GameVersion{BaseModel: models.BaseModel{ID: uuid.New()}, Code: "MHP3", Name: "MHP3", DisplayOrder: 2, PlatformID: uuid.New(), IsActive: true}
+	for _, v := range []*models.GameVersion{&env.version, &env.other} {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	env.rathalos = env.createMonster(env.version.ID, "リオレウス", 1)
+	env.tigrex = env.createMonster(env.version.ID, "ティガレックス", 2)
+
+	env.host, env.guest = env.createUser("ホスト"), env.createUser("参加者")
+	env.room = env.createRoom("HUNT01", env.host)
+	if err := env.repo.Room.JoinRoom(env.room.ID, env.guest.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func (e *huntTestEnv) createMonster(gameVersionID uuid.UUID, name string, order int) models.Monster {
+	e.t.Helper()
+	monster := models.Monster{BaseModel: models.BaseModel{ID: uuid.New()}, GameVersionID: gameVersionID, Name: name, DisplayOrder: order, IsActive: true}
+	if err := e.db.Create(&monster).Error; err != nil {
+		e.t.Fatal(err)
+	}
+	return monster
+}
+
+func (e *huntTestEnv) createUser(name string) *models.User {
+	e.t.Helper()
+	return createTestUser(e.t, e.repo, name)
+}
+
+func (e *huntTestEnv) createRoom(code string, host *models.User) *models.Room {
+	e.t.Helper()
+	room := &models.Room{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		RoomCode:       code,
+		Name:           "狩猟部屋",
+		GameVersionID:  e.version.ID,
+		HostUserID:     host.ID,
+		MaxPlayers:     4,
+		CurrentPlayers: 1,
+		IsActive:       true,
+	}
+	if err := e.db.Create(room).Error; err != nil {
+		e.t.Fatal(err)
+	}
+	member := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: host.ID, PlayerNumber: 1, IsHost: true, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+	if err := e.db.Create(&member).Error; err != nil {
+		e.t.Fatal(err)
+	}
+	return room
+}
+
+func (e *huntTestEnv) record(room *models.Room, monster models.Monster, seconds int, participants ...uuid.UUID) error {
+	return e.repo.HuntRecord.CreateHuntRecord(&models.HuntRecord{
+		RoomID:           room.ID,
+		MonsterID:        monster.ID,
+		ClearTimeSeconds: seconds,
+		RecordedByUserID: room.HostUserID,
+		ClearedAt:        time.Now(),
+	}, participants)
+}
+
+func TestCreateHuntRecord(t *testing.T) {
+	env := newHuntTestEnv(t)
+	otherMonster := env.createMonster(env.other.ID, "ジンオウガ", 1)
+	outsider := env.createUser("部外者")
+	questID := uuid.New()
+	quest := models.Quest{BaseModel: models.BaseModel{ID: questID}, GameVersionID: env.version.ID, MonsterID: &env.tigrex.ID, Name: "轟竜ティガレックス", IsActive: true}
+	if err := env.db.Create(&quest).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		record       models.HuntRecord
+		participants []uuid.UUID
+		wantErr      bool
+		wantCount    int
+	}{
+		{
+			name:      "参加者を省略すると参加中のメンバー全員",
+			record:    models.HuntRecord{MonsterID: env.rathalos.ID, ClearTimeSeconds: 600, RecordedByUserID: env.host.ID},
+			wantCount: 2,
+		},
+		{
+			name:         "参加者を指定（重複は1人として扱う）",
+			record:       models.HuntRecord{MonsterID: env.tigrex.ID, QuestID: &questID, ClearTimeSeconds: 480, RecordedByUserID: env.host.ID},
+			participants: []uuid.UUID{env.guest.ID, env.guest.ID},
+			wantCount:    1,
+		},
+		{
+			name:    "ホスト以外は記録できない",
+			record:  models.HuntRecord{MonsterID: env.rathalos.ID, ClearTimeSeconds: 600, RecordedByUserID: env.guest.ID},
+			wantErr: true,
+		},
+		{
+			name:    "別のゲームバージョンのモンスター",
+			record:  models.HuntRecord{MonsterID: otherMonster.ID, ClearTimeSeconds: 600, RecordedByUserID: env.host.ID},
+			wantErr: true,
+		},
+		{
+			name:    "クエストのターゲットと一致しないモンスター",
+			record:  models.HuntRecord{MonsterID: env.rathalos.ID, QuestID: &questID, ClearTimeSeconds: 600, RecordedByUserID: env.host.ID},
+			wantErr: true,
+		},
+		{
+			name:         "部屋にいないユーザーは参加者にできない",
+			record:       models.HuntRecord{MonsterID: env.rathalos.ID, ClearTimeSeconds: 600, RecordedByUserID: env.host.ID},
+			participants: []uuid.UUID{outsider.ID},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			record.RoomID = env.room.ID
+			record.ClearedAt = time.Now()
+			err := env.repo.HuntRecord.CreateHuntRecord(&record, tt.participants)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("エラーになるはずが成功した")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateHuntRecord() error = %v", err)
+			}
+			if record.GameVersionID != env.version.ID {
+				t.Errorf("GameVersionID = %v, want 部屋のゲームバージョン", record.GameVersionID)
+			}
+			if len(record.Participants) != tt.wantCount {
+				t.Errorf("参加者 = %d 人, want %d", len(record.Participants), tt.wantCount)
+			}
+			for _, p := range record.Participants {
+				if p.RoomMemberID == uuid.Nil {
+					t.Errorf("参加者 %v が RoomMember に紐づいていない", p.UserID)
+				}
+			}
+		})
+	}
+
+	records, err := env.repo.HuntRecord.GetRecordsByRoom(env.room.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("記録 = %d 件, want 2", len(records))
+	}
+	var logCount int64
+	env.db.Model(&models.RoomLog{}).Where("room_id = ? AND action = ?", env.room.ID, "hunt_clear").Count(&logCount)
+	if logCount != 2 {
+		t.Errorf("hunt_clear のログ = %d 件, want 2", logCount)
+	}
+}
+
+func TestHuntStatsAndLeaderboard(t *testing.T) {
+	env := newHuntTestEnv(t)
+	rival := env.createUser("ライバル")
+	otherHost := env.createUser("別ホスト")
+	otherRoom := env.createRoom("HUNT02", otherHost)
+	if err := env.repo.Room.JoinRoom(otherRoom.ID, rival.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []error{
+		env.record(env.room, env.rathalos, 720),
+		env.record(env.room, env.rathalos, 540, env.guest.ID),
+		env.record(env.room, env.tigrex, 900),
+		env.record(otherRoom, env.rathalos, 540),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	stats, err := env.repo.HuntRecord.GetUserHuntStats(env.guest.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalClears != 3 {
+		t.Errorf("TotalClears = %d, want 3", stats.TotalClears)
+	}
+	if len(stats.ByGameVersion) != 1 || stats.ByGameVersion[0].GameVersionCode != "MHP2G" || stats.ByGameVersion[0].Clears != 3 {
+		t.Errorf("ByGameVersion = %+v", stats.ByGameVersion)
+	}
+	wantMonsters := []MonsterHuntStat{
+		{MonsterName: "リオレウス", Clears: 2, BestSeconds: 540},
+		{MonsterName: "ティガレックス", Clears: 1, BestSeconds: 900},
+	}
+	if len(stats.ByMonster) != len(wantMonsters) {
+		t.Fatalf("ByMonster = %+v", stats.ByMonster)
+	}
+	for i, want := range wantMonsters {
+		got := stats.ByMonster[i]
+		if got.MonsterName != want.MonsterName || got.Clears != want.Clears || got.BestSeconds != want.BestSeconds {
+			t.Errorf("ByMonster[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+
+	board, err := env.repo.HuntRecord.GetMonsterLeaderboard(env.version.ID, env.rathalos.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 540秒: 参加者（2回）・別ホスト・ライバル（各1回）が同率1位、720秒のホストが4位
+	wantBoard := []struct {
+		name string
+		rank int
+	}{{"参加者", 1}, {"ライバル", 1}, {"別ホスト", 1}, {"ホスト", 4}}
+	if len(board) != len(wantBoard) {
+		t.Fatalf("ランキング = %+v", board)
+	}
+	for i, want := range wantBoard {
+		if board[i].DisplayName != want.name || board[i].Rank != want.rank {
+			t.Errorf("ランキング[%d] = %s %d位, want %s %d位", i, board[i].DisplayName, board[i].Rank, want.name, want.rank)
+		}
+	}
+
+	empty, err := env.repo.HuntRecord.GetMonsterLeaderboard(env.other.ID, env.rathalos.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("別のゲームバージョンで絞り込むと空になるはず: %+v", empty)
+	}
+}
@@ -26,6 +26,38 @@ type GameVersionRepository interface {
 	GetActiveGameVersions() ([]models.GameVersion, error)
 }
 
+type CatalogRepository interface {
+	GetMonsters(gameVersionID uuid.UUID) ([]models.Monster, error)
+	FindMonsterByID(id uuid.UUID) (*models.Monster, error)
+	FindMonsterByName(gameVersionID uuid.UUID, name string) (*models.Monster, error)
+	GetQuests(gameVersionID uuid.UUID, monsterID *uuid.UUID) ([]models.Quest, error)
+	FindQuestByID(id uuid.UUID) (*models.Quest, error)
+}
+
+type RoomWaitlistRepository interface {
+	JoinWaitlist(roomID, userID uuid.UUID) (*models.RoomWaitlistEntry, error)
+	LeaveWaitlist(roomID, userID uuid.UUID) error
+	FindActiveEntry(roomID, userID uuid.UUID) (*models.RoomWaitlistEntry, int, error)
+	GetWaitingEntries(roomID uuid.UUID) ([]models.RoomWaitlistEntry, error)
+	CountReservedSeats(roomID uuid.UUID, now time.Time) (int, error)
+	PromoteWaitlist(roomID uuid.UUID, now time.Time, reservation time.Duration) (offered, expired []models.RoomWaitlistEntry, err error)
+}
+
+type RoomInviteRepository interface {
+	CreateInvite(invite *models.RoomInvite) error
+	GetInvitesByRoom(roomID uuid.UUID) ([]models.RoomInvite, error)
+	FindInviteByToken(roomID uuid.UUID, token string) (*models.RoomInvite, error)
+	RevokeInvite(roomID, inviteID, userID uuid.UUID) error
+}
+
+type RoomJoinRequestRepository interface {
+	CreateJoinRequest(roomID, userID uuid.UUID) (*models.RoomJoinRequest, error)
+	FindPendingRequest(roomID, userID uuid.UUID) (*models.RoomJoinRequest, error)
+	GetPendingRequests(roomID uuid.UUID) ([]models.RoomJoinRequest, error)
+	RejectJoinRequest(roomID, requestID, hostUserID uuid.UUID) (*models.RoomJoinRequest, error)
+	CancelJoinRequest(roomID, userID uuid.UUID) error
+}
+
 type PlatformRepository interface {
 	GetActivePlatforms() ([]models.Platform, error)
 }
@@ -35,32 +67,54 @@ type RoomRepository interface {
 	FindRoomByID(id uuid.UUID) (*models.Room, error)
 	FindRoomByRoomCode(roomCode string) (*models.Room, error)
 	RoomCodeExists(roomCode string) (bool, error)
-	GetActiveRooms(gameVersionID *uuid.UUID, limit, offset int) ([]models.Room, error)
-	GetActiveRoomsWithJoinStatus(userID *uuid.UUID, gameVersionID *uuid.UUID, limit, offset int) ([]models.RoomWithJoinStatus, error)
-	CountActiveRooms(gameVersionID *uuid.UUID) (int64, error)
+	GetActiveRooms(params RoomSearchParams) ([]models.Room, error)
+	GetActiveRoomsWithJoinStatus(userID *uuid.UUID, params RoomSearchParams) ([]models.RoomWithJoinStatus, error)
+	CountActiveRooms(params RoomSearchParams) (int64, error)
 	UpdateRoom(room *models.Room) error
 	DismissRoom(id uuid.UUID, reason string) error
 	FindInactiveRooms(idleSince time.Time) ([]models.Room, error)
+	FindRoomsStartingBefore(now, until time.Time) ([]models.Room, error)
+	MarkStartReminderSent(roomID uuid.UUID) error
 	ToggleRoomClosed(id uuid.UUID, isClosed bool) error
 	IncrementRoomPlayerCount(id uuid.UUID) error
 	DecrementRoomPlayerCount(id uuid.UUID) error
 	JoinRoom(roomID, userID uuid.UUID, password string) error
+	JoinRoomWithInvite(roomID, userID uuid.UUID, inviteToken string) error
+	ApproveJoinRequest(roomID, requestID uuid.UUID) (*models.RoomJoinRequest, error)
 	LeaveRoom(roomID, userID uuid.UUID) error
-	KickMember(roomID, userID uuid.UUID) error
+	KickMember(roomID, userID uuid.UUID, reason *string, until *time.Time) error
+	TransferHost(roomID, fromUserID, toUserID uuid.UUID) error
 	FindActiveRoomByUserID(userID uuid.UUID) (*models.Room, error)
 	IsUserJoinedRoom(roomID, userID uuid.UUID) bool
+	IsUserKickedFromRoom(roomID, userID uuid.UUID) bool
+	HasParticipatedInRoom(roomID, userID uuid.UUID) bool
 	GetRoomMembers(roomID uuid.UUID) ([]models.RoomMember, error)
+	UpdateMemberLoadout(roomID, userID uuid.UUID, weaponType *string, hunterRank *int) error
+	GetActiveMemberWeapons(roomIDs []uuid.UUID) (map[uuid.UUID][]string, error)
 	GetRoomLogs(roomID uuid.UUID) ([]models.RoomLog, error)
 	GetUserRoomStatus(userID uuid.UUID) (string, *models.Room, error) // (status, room, error)
-	GetRoomsByHostUser(userID uuid.UUID, limit, offset int) ([]models.Room, error)
-	CountRoomsByHostUser(userID uuid.UUID) (int64, error)
+	GetRoomsByHostUser(userID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Room, error)
+	CountRoomsByHostUser(userID uuid.UUID, viewerID *uuid.UUID) (int64, error)
 	GetAllRoomsForAdmin(limit, offset int) ([]models.Room, error)
 	CountAllRooms() (int64, error)
 }
 
+type RoomSessionSummaryRepository interface {
+	FindByRoomID(roomID uuid.UUID) (*models.RoomSessionSummary, error)
+	FindByRoomIDs(roomIDs []uuid.UUID) (map[uuid.UUID]*models.RoomSessionSummary, error)
+}
+
+type HuntRecordRepository interface {
+	CreateHuntRecord(record *models.HuntRecord, participantUserIDs []uuid.UUID) error
+	GetRecordsByRoom(roomID uuid.UUID) ([]models.HuntRecord, error)
+	GetUserHuntStats(userID uuid.UUID) (*HuntStats, error)
+	GetMonsterLeaderboard(gameVersionID, monsterID uuid.UUID, limit int) ([]HuntLeaderboardEntry, error)
+}
+
 type RoomLogRepository interface {
 	CreateLog(log *models.RoomLog) error
 	ListRecentLogs(limit, offset int) ([]models.RoomLog, error)
+	ListRoomLogs(roomID uuid.UUID, actions []string) ([]models.RoomLog, error)
 	CountLogs() (int64, error)
 }
 
@@ -76,7 +130,28 @@ type PlayerNameRepository interface {
 type RoomMessageRepository interface {
 	CreateMessage(message *models.RoomMessage) error
 	GetMessages(roomID uuid.UUID, limit int, beforeID *uuid.UUID) ([]models.RoomMessage, error)
-	DeleteMessage(id uuid.UUID) error
+	FindMessageByID(id uuid.UUID) (*models.RoomMessage, error)
+	EachMessageBatch(roomID uuid.UUID, batchSize int, fn func([]models.RoomMessage) error) error
+	EditMessage(id, editorUserID uuid.UUID, text string) (*models.RoomMessage, error)
+	GetMessageEdits(messageIDs []uuid.UUID) ([]models.RoomMessageEdit, error)
+	MarkRead(roomID, userID uuid.UUID, message *models.RoomMessage) error
+	GetReadMarker(roomID, userID uuid.UUID) (*models.RoomReadMarker, error)
+	CountUnread(roomID, userID uuid.UUID) (int64, error)
+	DeleteMessage(id, deletedByUserID uuid.UUID) error
+	ListAttachmentsByRoom(roomID uuid.UUID) ([]models.RoomMessageAttachment, error)
+	ListOrphanedAttachments(limit int) ([]models.RoomMessageAttachment, error)
+	DeleteAttachments(ids []uuid.UUID) error
+}
+
+type NGWordRepository interface {
+	ListNGWords() ([]models.NGWord, error)
+	ListActiveNGWords() ([]models.NGWord, error)
+	CreateNGWord(word *models.NGWord) error
+	SetNGWordActive(id uuid.UUID, active bool) error
+	DeleteNGWord(id uuid.UUID) error
+	CreateHits(hits []models.NGWordHit) error
+	ListRecentHits(limit, offset int) ([]models.NGWordHit, error)
+	CountHits() (int64, error)
 }
 
 type UserBlockRepository interface {
@@ -89,6 +164,13 @@ type UserBlockRepository interface {
 	GetBlockingUsers(blockedUserID uuid.UUID) ([]models.User, error)
 }
 
+type HostBanRepository interface {
+	BanUser(hostUserID, bannedUserID uuid.UUID, reason *string) (*models.HostBan, error)
+	UnbanUser(hostUserID, bannedUserID uuid.UUID) error
+	IsBanned(hostUserID, userID uuid.UUID) (bool, error)
+	GetBansByHost(hostUserID uuid.UUID) ([]models.HostBan, error)
+}
+
 type UserFollowRepository interface {
 	CreateFollow(follow *models.UserFollow) error
 	DeleteFollow(followerUserID, followingUserID uuid.UUID) error
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+)
+
+// ngWordRepository はNGワードと一致記録の操作を行うリポジトリの実装
+type ngWordRepository struct {
+	db DBInterface
+}
+
+// NewNGWordRepository は新しいNGWordRepositoryインスタンスを作成
+func NewNGWordRepository(db DBInterface) NGWordRepository {
+	return &ngWordRepository{db: db}
+}
+
+// ListNGWords 登録済みのNGワードを新しい順に取得（無効化したものを含む）
+func (r *ngWordRepository) ListNGWords() ([]models.NGWord, error) {
+	var words []models.NGWord
+	err := r.db.GetConn().
+		Preload("CreatedBy").
+		Order("created_at DESC").
+		Find(&words).Error
+	return words, err
+}
+
+// ListActiveNGWords 入力のチェックに使う有効なNGワードを取得
+func (r *ngWordRepository) ListActiveNGWords() ([]models.NGWord, error) {
+	var words []models.NGWord
+	err := r.db.GetConn().
+		Where("is_active = ?", true).
+		Order("created_at ASC").
+		Find(&words).Error
+	return words, err
+}
+
+// CreateNGWord NGワードを登録
+func (r *ngWordRepository) CreateNGWord(word *models.NGWord) error {
+	return r.db.GetConn().Create(word).Error
+}
+
+// SetNGWordActive NGワードの有効・無効を切り替える
+func (r *ngWordRepository) SetNGWordActive(id uuid.UUID, active bool) error {
+	return r.db.GetConn().Model(&models.NGWord{}).
+		Where("id = ?", id).
+		Update("is_active", active).Error
+}
+
+// DeleteNGWord NGワードを削除（一致記録は残す）
+func (r *ngWordRepository) DeleteNGWord(id uuid.UUID) error {
+	return r.db.GetConn().Where("id = ?", id).Delete(&models.NGWord{}).Error
+}
+
+// CreateHits NGワードに一致した入力をまとめて記録
+func (r *ngWordRepository) CreateHits(hits []models.NGWordHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	return r.db.GetConn().Create(&hits).Error
+}
+
+// ListRecentHits 一致記録を新しい順に取得
+func (r *ngWordRepository) ListRecentHits(limit, offset int) ([]models.NGWordHit, error) {
+	var hits []models.NGWordHit
+	err := r.db.GetConn().
+		Preload("User").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&hits).Error
+	return hits, err
+}
+
+// CountHits 一致記録の件数
+func (r *ngWordRepository) CountHits() (int64, error) {
+	var count int64
+	err := r.db.GetConn().Model(&models.NGWordHit{}).Count(&count).Error
+	return count, err
+}
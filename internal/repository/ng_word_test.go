@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestNGWordRepository(t *testing.T) {
+	_, repo := newTestRepository(t, &models.User{}, &models.NGWord{}, &models.NGWordHit{})
+
+	spam := &models.NGWord{Pattern: "spam", MatchType: models.NGWordMatchSubstring, Action: models.NGWordActionMask, IsActive: true}
+	slur := &models.NGWord{Pattern: "死ね", MatchType: models.NGWordMatchSubstring, Action: models.NGWordActionBlock, IsActive: true}
+	for _, w := range []*models.NGWord{spam, slur} {
+		if err := repo.NGWord.CreateNGWord(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.NGWord.SetNGWordActive(spam.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := repo.NGWord.ListActiveNGWords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0].ID != slur.ID {
+		t.Errorf("有効なNGワード = %+v, want 死ね のみ", active)
+	}
+
+	hit := models.NGWordHit{NGWordID: slur.ID, Pattern: slur.Pattern, Action: slur.Action, UserID: uuid.New(), Target: models.NGWordTargetChat, Content: "死ね"}
+	if err := repo.NGWord.CreateHits([]models.NGWordHit{hit}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.NGWord.DeleteNGWord(slur.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	words, err := repo.NGWord.ListNGWords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 1 || words[0].ID != spam.ID {
+		t.Errorf("NGワード一覧 = %+v, want spam のみ", words)
+	}
+	// NGワードを削除しても一致記録は残る
+	if count, err := repo.NGWord.CountHits(); err != nil || count != 1 {
+		t.Errorf("CountHits() = %d, %v, want 1", count, err)
+	}
+	hits, err := repo.NGWord.ListRecentHits(10, 0)
+	if err != nil || len(hits) != 1 || hits[0].Pattern != "死ね" {
+		t.Errorf("ListRecentHits() = %+v, %v", hits, err)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"mhp-rooms/internal/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
 )
 
 var ErrNotFound = errors.New("not found")
 
+// ErrReactionExists 同じユーザーが同じメッセージに同じリアクションを既に付けている
+var ErrReactionExists = errors.New("reaction already exists")
+
 type ReactionRepository interface {
 	GetMessageReactions(messageID uuid.UUID, userID *uuid.UUID) ([]models.MessageReactionCount, error)
+	GetReactionSummaries(messageIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID][]models.MessageReactionCount, error)
 	AddReaction(reaction *models.MessageReaction) error
 	RemoveReaction(messageID, userID uuid.UUID, reactionType string) error
 	GetReactionTypes() ([]models.ReactionType, error)
@@ -26,45 +31,78 @@ func NewReactionRepository(db DBInterface) ReactionRepository {
 	return &reactionRepository{db: db}
 }
 
+// GetMessageReactions メッセージのリアクションを種類ごとに集計する
 func (r *reactionRepository) GetMessageReactions(messageID uuid.UUID, userID *uuid.UUID) ([]models.MessageReactionCount, error) {
-	var reactionCounts []models.MessageReactionCount
-	query := `
-		SELECT 
-			mr.message_id,
-			mr.reaction_type,
-			rt.emoji,
-			rt.name AS reaction_name,
-			COUNT(mr.user_id) AS reaction_count,
-			ARRAY_AGG(mr.user_id ORDER BY mr.created_at) AS user_ids
-		FROM message_reactions mr
-		JOIN reaction_types rt ON mr.reaction_type = rt.code
-		WHERE mr.message_id = ? AND rt.is_active = true
-		GROUP BY mr.message_id, mr.reaction_type, rt.emoji, rt.name
-		ORDER BY rt.display_order`
-
-	if err := r.db.GetConn().Raw(query, messageID).Scan(&reactionCounts).Error; err != nil {
+	summaries, err := r.GetReactionSummaries([]uuid.UUID{messageID}, userID)
+	if err != nil {
 		return nil, err
 	}
+	return summaries[messageID], nil
+}
 
-	// ユーザーがログインしている場合、そのユーザーのリアクション状態をチェック
-	if userID != nil {
-		for i := range reactionCounts {
-			for _, uid := range reactionCounts[i].UserIDs {
-				if uid == *userID {
-					reactionCounts[i].HasReacted = true
-					break
-				}
-			}
-			// セキュリティのため、他のユーザーIDは削除
-			reactionCounts[i].UserIDs = nil
-		}
+// reactionRow 集計前のリアクション1件（種類の表示情報付き）
+type reactionRow struct {
+	MessageID    uuid.UUID
+	UserID       uuid.UUID
+	ReactionType string
+	Emoji        string
+	ReactionName string
+}
+
+// GetReactionSummaries 複数メッセージのリアクションを種類ごとに集計する。
+// ARRAY_AGG が使えない turso でも動くよう、集計は Go 側で行う。
+// userID を渡すとそのユーザーがリアクション済みかを has_reacted に入れる。他のユーザーIDは返さない
+func (r *reactionRepository) GetReactionSummaries(messageIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID][]models.MessageReactionCount, error) {
+	summaries := make(map[uuid.UUID][]models.MessageReactionCount)
+	if len(messageIDs) == 0 {
+		return summaries, nil
 	}
 
-	return reactionCounts, nil
+	var rows []reactionRow
+	err := r.db.GetConn().
+		Table("message_reactions AS mr").
+		Select("mr.message_id, mr.user_id, mr.reaction_type, rt.emoji, rt.name AS reaction_name").
+		Joins("JOIN reaction_types AS rt ON mr.reaction_type = rt.code").
+		Where("mr.message_id IN ? AND rt.is_active = ?", messageIDs, true).
+		Order("rt.display_order, mr.created_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts := summaries[row.MessageID]
+		i := 0
+		for i < len(counts) && counts[i].ReactionType != row.ReactionType {
+			i++
+		}
+		if i == len(counts) {
+			counts = append(counts, models.MessageReactionCount{
+				MessageID:    row.MessageID,
+				ReactionType: row.ReactionType,
+				Emoji:        row.Emoji,
+				ReactionName: row.ReactionName,
+			})
+		}
+		counts[i].ReactionCount++
+		if userID != nil && row.UserID == *userID {
+			counts[i].HasReacted = true
+		}
+		summaries[row.MessageID] = counts
+	}
+	return summaries, nil
 }
 
+// AddReaction リアクションを追加する。同じリアクションが既にあれば ErrReactionExists を返す
 func (r *reactionRepository) AddReaction(reaction *models.MessageReaction) error {
-	return r.db.GetConn().Create(reaction).Error
+	result := r.db.GetConn().Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReactionExists
+	}
+	return nil
 }
 
 func (r *reactionRepository) RemoveReaction(messageID, userID uuid.UUID, reactionType string) error {
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestReactionSummaries(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.RoomMessage{}, &models.ReactionType{}, &models.MessageReaction{})
+	// 本番では persistence の createConstraintsAndIndexes が作るユニークインデックス
+	if err := db.Exec("CREATE UNIQUE INDEX idx_message_reactions_unique ON message_reactions(message_id, user_id, reaction_type)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	types := []models.ReactionType{
+		{Code: "thumbs_up", Name: "いいね", Emoji: "👍", DisplayOrder: 1, IsActive: true},
+		{Code: "fire", Name: "熱い", Emoji: "🔥", DisplayOrder: 2, IsActive: true},
+		{Code: "retired", Name: "廃止", Emoji: "💤", DisplayOrder: 3, IsActive: true},
+	}
+	if err := db.Create(&types).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 無効化したリアクションは集計に含めない
+	if err := db.Model(&models.ReactionType{}).Where("code = ?", "retired").Update("is_active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	alice, bob := uuid.New(), uuid.New()
+	first, second, quiet := uuid.New(), uuid.New(), uuid.New()
+	add := func(messageID, userID uuid.UUID, reactionType string) {
+		t.Helper()
+		if err := repo.Reaction.AddReaction(&models.MessageReaction{MessageID: messageID, UserID: userID, ReactionType: reactionType}); err != nil {
+			t.Fatalf("AddReaction(%s) error = %v", reactionType, err)
+		}
+	}
+	add(first, alice, "fire")
+	add(first, bob, "fire")
+	add(first, bob, "thumbs_up")
+	add(first, alice, "retired")
+	add(second, bob, "thumbs_up")
+
+	// 同じリアクションは二重に付けられない
+	err := repo.Reaction.AddReaction(&models.MessageReaction{MessageID: first, UserID: alice, ReactionType: "fire"})
+	if !errors.Is(err, ErrReactionExists) {
+		t.Fatalf("重複した AddReaction() error = %v, want ErrReactionExists", err)
+	}
+
+	summaries, err := repo.Reaction.GetReactionSummaries([]uuid.UUID{first, second, quiet}, &alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := summaries[first]
+	want := []models.MessageReactionCount{
+		{MessageID: first, ReactionType: "thumbs_up", Emoji: "👍", ReactionName: "いいね", ReactionCount: 1},
+		{MessageID: first, ReactionType: "fire", Emoji: "🔥", ReactionName: "熱い", ReactionCount: 2, HasReacted: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("集計 = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].ReactionType != want[i].ReactionType || got[i].Emoji != want[i].Emoji || got[i].ReactionName != want[i].ReactionName ||
+			got[i].ReactionCount != want[i].ReactionCount || got[i].HasReacted != want[i].HasReacted {
+			t.Errorf("集計[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+		if len(got[i].UserIDs) != 0 {
+			t.Errorf("集計[%d] にユーザーIDが含まれている: %v", i, got[i].UserIDs)
+		}
+	}
+	if len(summaries[second]) != 1 || summaries[second][0].HasReacted {
+		t.Errorf("2件目の集計 = %+v", summaries[second])
+	}
+	if _, ok := summaries[quiet]; ok {
+		t.Errorf("リアクションのないメッセージに集計がある: %+v", summaries[quiet])
+	}
+
+	// 単体の取得も同じ集計を返し、外したリアクションは数えない
+	if err := repo.Reaction.RemoveReaction(first, bob, "fire"); err != nil {
+		t.Fatal(err)
+	}
+	single, err := repo.Reaction.GetMessageReactions(first, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(single) != 2 || single[1].ReactionType != "fire" || single[1].ReactionCount != 1 || single[1].HasReacted {
+		t.Errorf("GetMessageReactions() = %+v", single)
+	}
+}
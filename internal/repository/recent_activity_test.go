@@ -22,7 +22,7 @@ func TestRecentPublicActivityQueries(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := db.AutoMigrate(&models.User{}, &models.UserActivity{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Room{}, &models.UserActivity{}); err != nil {
 		t.Fatal(err)
 	}
 	repo := NewRepository(recentActivityTestDB{conn: db})
@@ -13,41 +13,69 @@ type DBInterface interface {
 	GetType() string
 }
 
+// timeComparison 日時カラム同士・日時カラムとパラメータを比較するための式を返す。
+// libSQL(SQLite) は日時を文字列として比較するため、タイムゾーン表記（+09:00 / +00:00）が
+// 混在すると誤判定する。strftime() で UTC に正規化してから比較する。同じ秒の前後も区別できるよう
+// ミリ秒まで残す（Postgres は素の比較で正しい）
+func timeComparison(db DBInterface) (func(column string) string, string) {
+	if db.GetType() == "turso" {
+		normalize := func(column string) string { return "strftime('%Y-%m-%d %H:%M:%f', " + column + ")" }
+		return normalize, normalize("?")
+	}
+	return func(column string) string { return column }, "?"
+}
+
 type Repository struct {
 	User          UserRepository
 	GameVersion   GameVersionRepository
+	Catalog       CatalogRepository
 	Platform      PlatformRepository
 	Room          RoomRepository
+	RoomWaitlist  RoomWaitlistRepository
+	RoomInvite    RoomInviteRepository
+	JoinRequest   RoomJoinRequestRepository
 	PasswordReset PasswordResetRepository
 	PlayerName    PlayerNameRepository
 	Reaction      ReactionRepository
 	RoomMessage   RoomMessageRepository
 	UserBlock     UserBlockRepository
+	HostBan       HostBanRepository
 	UserFollow    UserFollowRepository
 	UserActivity  UserActivityRepository
 	Report        ReportRepository
 	Contact       ContactRepository
 	Notification  NotificationRepository
 	RoomLog       RoomLogRepository
+	RoomSession   RoomSessionSummaryRepository
+	HuntRecord    HuntRecordRepository
+	NGWord        NGWordRepository
 }
 
 func NewRepository(db DBInterface) *Repository {
 	return &Repository{
 		User:          NewUserRepository(db),
 		GameVersion:   NewGameVersionRepository(db),
+		Catalog:       NewCatalogRepository(db),
 		Platform:      NewPlatformRepository(db),
 		Room:          NewRoomRepository(db),
+		RoomWaitlist:  NewRoomWaitlistRepository(db),
+		RoomInvite:    NewRoomInviteRepository(db),
+		JoinRequest:   NewRoomJoinRequestRepository(db),
 		PasswordReset: NewPasswordResetRepository(db),
 		PlayerName:    NewPlayerNameRepository(db),
 		Reaction:      NewReactionRepository(db),
 		RoomMessage:   NewRoomMessageRepository(db),
 		UserBlock:     NewUserBlockRepository(db),
+		HostBan:       NewHostBanRepository(db),
 		UserFollow:    NewUserFollowRepository(db),
 		UserActivity:  NewUserActivityRepository(db),
 		Report:        NewReportRepository(db),
 		Contact:       NewContactRepository(db),
 		Notification:  NewNotificationRepository(db),
 		RoomLog:       NewRoomLogRepository(db),
+		RoomSession:   NewRoomSessionSummaryRepository(db),
+		HuntRecord:    NewHuntRecordRepository(db),
+		NGWord:        NewNGWordRepository(db),
 	}
 }
 
@@ -109,8 +137,8 @@ func (r *Repository) RoomCodeExists(roomCode string) (bool, error) {
 	return r.Room.RoomCodeExists(roomCode)
 }
 
-func (r *Repository) GetActiveRooms(gameVersionID *uuid.UUID, limit, offset int) ([]models.Room, error) {
-	return r.Room.GetActiveRooms(gameVersionID, limit, offset)
+func (r *Repository) GetActiveRooms(params RoomSearchParams) ([]models.Room, error) {
+	return r.Room.GetActiveRooms(params)
 }
 
 func (r *Repository) UpdateRoom(room *models.Room) error {
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomHostTransferAndSuccession(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{})
+
+	host, veteran, newcomer, outsider := createTestUser(t, repo, "ホスト"), createTestUser(t, repo, "古参"), createTestUser(t, repo, "新参"), createTestUser(t, repo, "部外者")
+
+	room := &models.Room{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		RoomCode:       "HOST01",
+		Name:           "交代部屋",
+		GameVersionID:  uuid.New(),
+		HostUserID:     host.ID,
+		MaxPlayers:     4,
+		CurrentPlayers: 3,
+		IsActive:       true,
+	}
+	if err := db.Create(room).Error; err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, user := range []*models.User{host, veteran, newcomer} {
+		member := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: user.ID, PlayerNumber: i + 1, IsHost: i == 0, Status: models.MemberStatusActive, JoinedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := db.Create(&member).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hostOf := func() uuid.UUID {
+		t.Helper()
+		var current models.Room
+		if err := db.First(&current, "id = ?", room.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		var flagged []models.RoomMember
+		db.Where("room_id = ? AND is_host = ?", room.ID, true).Find(&flagged)
+		if len(flagged) != 1 || flagged[0].UserID != current.HostUserID {
+			t.Fatalf("ホストフラグ %+v が rooms.host_user_id %s と一致しない", flagged, current.HostUserID)
+		}
+		return current.HostUserID
+	}
+
+	// ホスト以外・部外者への譲渡はできない
+	if err := repo.Room.TransferHost(room.ID, veteran.ID, newcomer.ID); err == nil {
+		t.Error("ホスト以外が譲渡できてしまう")
+	}
+	if err := repo.Room.TransferHost(room.ID, host.ID, outsider.ID); err == nil {
+		t.Error("参加していないユーザーに譲渡できてしまう")
+	}
+
+	// 指名して譲る
+	if err := repo.Room.TransferHost(room.ID, host.ID, newcomer.ID); err != nil {
+		t.Fatalf("TransferHost() = %v", err)
+	}
+	if got := hostOf(); got != newcomer.ID {
+		t.Fatalf("譲渡後のホスト = %s, want 新参", got)
+	}
+
+	// ホストが退出すると、参加期間が最も長いメンバーが引き継ぐ
+	if err := repo.Room.LeaveRoom(room.ID, newcomer.ID); err != nil {
+		t.Fatalf("LeaveRoom(ホスト) = %v", err)
+	}
+	if got := hostOf(); got != host.ID {
+		t.Fatalf("退出後のホスト = %s, want 参加期間が最も長い元ホスト", got)
+	}
+
+	var logs []models.RoomLog
+	db.Where("room_id = ? AND action = ?", room.ID, "transfer_host").Order("created_at ASC").Find(&logs)
+	if len(logs) != 2 {
+		t.Fatalf("transfer_host のログ = %d 件, want 2", len(logs))
+	}
+	if details, _ := logs[1].Details.Data.(map[string]interface{}); details["reason"] != models.HostChangeReasonSuccession || details["user_name"] != "ホスト" {
+		t.Errorf("自動引き継ぎのログ = %+v", logs[1].Details.Data)
+	}
+
+	// 最後の1人になったホストは退出できない
+	if err := repo.Room.LeaveRoom(room.ID, veteran.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Room.LeaveRoom(room.ID, host.ID); err == nil || !strings.HasPrefix(err.Error(), "HOST_ALONE:") {
+		t.Errorf("ひとりのホストの退出 = %v, want HOST_ALONE", err)
+	}
+	if !repo.Room.IsUserJoinedRoom(room.ID, host.ID) {
+		t.Error("退出できなかったホストがメンバーから外れている")
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// roomInviteRepository はホストが発行する招待リンクを扱うリポジトリの実装
+type roomInviteRepository struct {
+	db DBInterface
+}
+
+// NewRoomInviteRepository は新しいRoomInviteRepositoryインスタンスを作成
+func NewRoomInviteRepository(db DBInterface) RoomInviteRepository {
+	return &roomInviteRepository{db: db}
+}
+
+// CreateInvite 招待リンクを作成し、作成を room_logs に記録する
+func (r *roomInviteRepository) CreateInvite(invite *models.RoomInvite) error {
+	return r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(invite).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.RoomLog{
+			RoomID: invite.RoomID,
+			UserID: &invite.CreatedByUserID,
+			Action: "create_invite",
+			Details: models.JSONB{
+				Data: map[string]interface{}{
+					"invite_id":  invite.ID,
+					"expires_at": invite.ExpiresAt,
+					"max_uses":   invite.MaxUses,
+				},
+			},
+		}).Error
+	})
+}
+
+// GetInvitesByRoom 部屋の招待リンクを新しい順に取得
+func (r *roomInviteRepository) GetInvitesByRoom(roomID uuid.UUID) ([]models.RoomInvite, error) {
+	var invites []models.RoomInvite
+	err := r.db.GetConn().
+		Where("room_id = ?", roomID).
+		Order("created_at DESC").
+		Find(&invites).Error
+	return invites, err
+}
+
+// FindInviteByToken 部屋の招待リンクをトークンで検索。見つからなければ nil を返す
+func (r *roomInviteRepository) FindInviteByToken(roomID uuid.UUID, token string) (*models.RoomInvite, error) {
+	var invite models.RoomInvite
+	result := r.db.GetConn().
+		Where("room_id = ? AND token = ?", roomID, token).
+		Limit(1).
+		Find(&invite)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &invite, nil
+}
+
+// RevokeInvite 招待リンクを無効にし、userID による無効化を room_logs に記録する。無効にした後のリンクからは参加できない
+func (r *roomInviteRepository) RevokeInvite(roomID, inviteID, userID uuid.UUID) error {
+	return r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RoomInvite{}).
+			Where("id = ? AND room_id = ? AND revoked_at IS NULL", inviteID, roomID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("招待リンクが見つからないか、既に無効になっています")
+		}
+		return tx.Create(&models.RoomLog{
+			RoomID: roomID,
+			UserID: &userID,
+			Action: "revoke_invite",
+			Details: models.JSONB{
+				Data: map[string]interface{}{
+					"invite_id": inviteID,
+				},
+			},
+		}).Error
+	})
+}
+
+// useInvite 招待リンクを1回分使用する。使えないリンクは INVITE_INVALID を返す。
+// 部屋への参加と同じトランザクションで呼び、参加に失敗した場合は使用回数も戻るようにする
+func useInvite(tx *gorm.DB, roomID uuid.UUID, token string, now time.Time) (*models.RoomInvite, error) {
+	var invite models.RoomInvite
+	if err := tx.Where("room_id = ? AND token = ?", roomID, token).First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("INVITE_INVALID:招待リンクが見つかりません")
+		}
+		return nil, fmt.Errorf("招待リンク検索エラー: %w", err)
+	}
+
+	// 使えない理由をそれぞれ伝えるため、取得した1件を Go 側で判定する
+	switch {
+	case invite.IsRevoked():
+		return nil, fmt.Errorf("INVITE_INVALID:この招待リンクはホストによって無効にされています")
+	case invite.IsExpired(now):
+		return nil, fmt.Errorf("INVITE_INVALID:招待リンクの有効期限が切れています")
+	case invite.IsExhausted():
+		return nil, fmt.Errorf("INVITE_INVALID:招待リンクの使用回数が上限に達しています")
+	}
+
+	// 同時に参加された場合でも上限を超えないよう、条件付きで使用回数を増やす
+	result := tx.Model(&models.RoomInvite{}).
+		Where("id = ? AND (max_uses = 0 OR use_count < max_uses)", invite.ID).
+		Update("use_count", gorm.Expr("use_count + ?", 1))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("INVITE_INVALID:招待リンクの使用回数が上限に達しています")
+	}
+	invite.UseCount++
+	return &invite, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestJoinRoomWithInvite(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomWaitlistEntry{}, &models.RoomInvite{}, &models.HostBan{})
+
+	host, first, second, third := createTestUser(t, repo, "ホスト"), createTestUser(t, repo, "招待1"), createTestUser(t, repo, "招待2"), createTestUser(t, repo, "招待3")
+
+	room := &models.Room{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		RoomCode:       "INVT01",
+		Name:           "鍵部屋",
+		GameVersionID:  uuid.New(),
+		HostUserID:     host.ID,
+		MaxPlayers:     4,
+		CurrentPlayers: 1,
+		IsActive:       true,
+	}
+	if err := room.SetPassword("secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(room).Error; err != nil {
+		t.Fatal(err)
+	}
+	hostMember := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: host.ID, PlayerNumber: 1, IsHost: true, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+	if err := db.Create(&hostMember).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	invite := &models.RoomInvite{RoomID: room.ID, Token: "once-token", CreatedByUserID: host.ID, MaxUses: 1}
+	if err := repo.RoomInvite.CreateInvite(invite); err != nil {
+		t.Fatal(err)
+	}
+
+	expectInvalid := func(err error, name string) {
+		t.Helper()
+		if err == nil || !strings.HasPrefix(err.Error(), "INVITE_INVALID:") {
+			t.Errorf("%s: err = %v, want INVITE_INVALID", name, err)
+		}
+	}
+
+	// パスワードなしでは参加できないが、招待リンクなら参加できる
+	if err := repo.Room.JoinRoom(room.ID, first.ID, ""); err == nil {
+		t.Fatal("パスワードなしで参加できてしまう")
+	}
+	if err := repo.Room.JoinRoomWithInvite(room.ID, first.ID, invite.Token); err != nil {
+		t.Fatalf("JoinRoomWithInvite() = %v", err)
+	}
+
+	// 使用回数の上限に達したリンクは使えない
+	expectInvalid(repo.Room.JoinRoomWithInvite(room.ID, second.ID, invite.Token), "上限到達")
+	expectInvalid(repo.Room.JoinRoomWithInvite(room.ID, second.ID, "unknown"), "存在しないトークン")
+
+	// 別の部屋の招待リンクは使えない
+	other := &models.RoomInvite{RoomID: uuid.New(), Token: "other-room", CreatedByUserID: host.ID}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatal(err)
+	}
+	expectInvalid(repo.Room.JoinRoomWithInvite(room.ID, second.ID, other.Token), "別の部屋")
+
+	// 期限切れ・無効化したリンクは使えない
+	past := time.Now().Add(-time.Minute)
+	expiredInvite := &models.RoomInvite{RoomID: room.ID, Token: "expired", CreatedByUserID: host.ID, ExpiresAt: &past}
+	if err := repo.RoomInvite.CreateInvite(expiredInvite); err != nil {
+		t.Fatal(err)
+	}
+	expectInvalid(repo.Room.JoinRoomWithInvite(room.ID, second.ID, expiredInvite.Token), "期限切れ")
+
+	revoked := &models.RoomInvite{RoomID: room.ID, Token: "revoked", CreatedByUserID: host.ID}
+	if err := repo.RoomInvite.CreateInvite(revoked); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RoomInvite.RevokeInvite(room.ID, revoked.ID, host.ID); err != nil {
+		t.Fatalf("RevokeInvite() = %v", err)
+	}
+	if err := repo.RoomInvite.RevokeInvite(room.ID, revoked.ID, host.ID); err == nil {
+		t.Error("無効化済みのリンクを再度無効にできてしまう")
+	}
+	expectInvalid(repo.Room.JoinRoomWithInvite(room.ID, second.ID, revoked.Token), "無効化")
+
+	// 参加に失敗した場合は使用回数を消費しない
+	unlimited := &models.RoomInvite{RoomID: room.ID, Token: "unlimited", CreatedByUserID: host.ID}
+	if err := repo.RoomInvite.CreateInvite(unlimited); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Room.JoinRoomWithInvite(room.ID, first.ID, unlimited.Token); err == nil || !strings.HasPrefix(err.Error(), "ALREADY_JOINED:") {
+		t.Errorf("参加済みユーザーの招待リンク使用 = %v, want ALREADY_JOINED", err)
+	}
+	for _, user := range []*models.User{second, third} {
+		if err := repo.Room.JoinRoomWithInvite(room.ID, user.ID, unlimited.Token); err != nil {
+			t.Fatalf("無制限のリンクで参加(%s) = %v", user.DisplayName, err)
+		}
+	}
+	found, err := repo.RoomInvite.FindInviteByToken(room.ID, unlimited.Token)
+	if err != nil || found == nil || found.UseCount != 2 {
+		t.Errorf("無制限のリンクの使用回数 = %+v, %v, want 2", found, err)
+	}
+
+	// 使用ごとに room_logs に記録される
+	var logs []models.RoomLog
+	db.Where("room_id = ? AND action = ?", room.ID, "use_invite").Find(&logs)
+	if len(logs) != 3 {
+		t.Errorf("use_invite のログ = %d 件, want 3", len(logs))
+	}
+
+	invites, err := repo.RoomInvite.GetInvitesByRoom(room.ID)
+	if err != nil || len(invites) != 4 {
+		t.Errorf("GetInvitesByRoom() = %d 件, %v, want 4", len(invites), err)
+	}
+}
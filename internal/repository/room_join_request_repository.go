@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// roomJoinRequestRepository は承認制の部屋への参加申請を扱うリポジトリの実装
+type roomJoinRequestRepository struct {
+	db DBInterface
+}
+
+// NewRoomJoinRequestRepository は新しいRoomJoinRequestRepositoryインスタンスを作成
+func NewRoomJoinRequestRepository(db DBInterface) RoomJoinRequestRepository {
+	return &roomJoinRequestRepository{db: db}
+}
+
+// CreateJoinRequest 参加申請を作成する。既に審査待ちの申請があればそれをそのまま返す
+func (r *roomJoinRequestRepository) CreateJoinRequest(roomID, userID uuid.UUID) (*models.RoomJoinRequest, error) {
+	var request models.RoomJoinRequest
+	err := r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.JoinRequestStatusPending).
+			First(&request).Error; err == nil {
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("参加申請検索エラー: %w", err)
+		}
+
+		request = models.RoomJoinRequest{
+			RoomID: roomID,
+			UserID: userID,
+			Status: models.JoinRequestStatusPending,
+		}
+		return tx.Create(&request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindPendingRequest userID の審査待ちの参加申請を取得する。なければ nil を返す
+func (r *roomJoinRequestRepository) FindPendingRequest(roomID, userID uuid.UUID) (*models.RoomJoinRequest, error) {
+	var request models.RoomJoinRequest
+	result := r.db.GetConn().
+		Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.JoinRequestStatusPending).
+		Limit(1).
+		Find(&request)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &request, nil
+}
+
+// GetPendingRequests 部屋の審査待ちの参加申請を申請順に取得
+func (r *roomJoinRequestRepository) GetPendingRequests(roomID uuid.UUID) ([]models.RoomJoinRequest, error) {
+	var requests []models.RoomJoinRequest
+	err := r.db.GetConn().
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "supabase_user_id", "username", "display_name", "avatar_url")
+		}).
+		Where("room_id = ? AND status = ?", roomID, models.JoinRequestStatusPending).
+		Order("created_at ASC, id ASC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// RejectJoinRequest ホストが参加申請を見送り、room_logs に記録する
+func (r *roomJoinRequestRepository) RejectJoinRequest(roomID, requestID, hostUserID uuid.UUID) (*models.RoomJoinRequest, error) {
+	var request models.RoomJoinRequest
+	err := r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND room_id = ?", requestID, roomID).First(&request).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("JOIN_REQUEST_INVALID:参加申請が見つかりません")
+			}
+			return err
+		}
+		if err := decideJoinRequest(tx, &request, models.JoinRequestStatusRejected, time.Now()); err != nil {
+			return err
+		}
+		return tx.Create(&models.RoomLog{
+			RoomID: roomID,
+			UserID: &hostUserID,
+			Action: "reject_join",
+			Details: models.JSONB{
+				Data: map[string]interface{}{
+					"target_user_id":  request.UserID,
+					"join_request_id": request.ID,
+				},
+			},
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// CancelJoinRequest 申請者が審査待ちの参加申請を取り下げる
+func (r *roomJoinRequestRepository) CancelJoinRequest(roomID, userID uuid.UUID) error {
+	result := r.db.GetConn().
+		Model(&models.RoomJoinRequest{}).
+		Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.JoinRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.JoinRequestStatusCancelled,
+			"decided_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("審査待ちの参加申請はありません")
+	}
+	return nil
+}
+
+// approveJoinRequest userID の審査待ちの参加申請を承認済みにする。
+// 部屋への参加と同じトランザクションで呼び、参加に失敗した場合は審査待ちに戻るようにする
+func approveJoinRequest(tx *gorm.DB, roomID, userID, requestID uuid.UUID, now time.Time) error {
+	var request models.RoomJoinRequest
+	if err := tx.Where("id = ? AND room_id = ? AND user_id = ?", requestID, roomID, userID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("JOIN_REQUEST_INVALID:参加申請が見つかりません")
+		}
+		return fmt.Errorf("参加申請検索エラー: %w", err)
+	}
+	return decideJoinRequest(tx, &request, models.JoinRequestStatusApproved, now)
+}
+
+// decideJoinRequest 審査待ちの参加申請を status にする。同時に審査された場合に備え、審査待ちのときだけ更新する
+func decideJoinRequest(tx *gorm.DB, request *models.RoomJoinRequest, status string, now time.Time) error {
+	result := tx.Model(&models.RoomJoinRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.JoinRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"decided_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("JOIN_REQUEST_INVALID:この参加申請は既に処理されています")
+	}
+	request.Status = status
+	request.DecidedAt = &now
+	return nil
+}
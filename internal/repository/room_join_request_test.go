@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomJoinRequests(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomWaitlistEntry{}, &models.RoomJoinRequest{}, &models.HostBan{}, &models.RoomSessionSummary{}, &models.RoomMessage{})
+
+	host, applicant, rejected := createTestUser(t, repo, "ホスト"), createTestUser(t, repo, "申請者"), createTestUser(t, repo, "見送り")
+
+	password := "secret"
+	room := &models.Room{
+		BaseModel:        models.BaseModel{ID: uuid.New()},
+		RoomCode:         "APPR01",
+		Name:             "承認制の部屋",
+		GameVersionID:    uuid.New(),
+		HostUserID:       host.ID,
+		MaxPlayers:       4,
+		CurrentPlayers:   1,
+		IsActive:         true,
+		RequiresApproval: true,
+	}
+	if err := room.SetPassword(password); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(room).Error; err != nil {
+		t.Fatal(err)
+	}
+	hostMember := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: host.ID, PlayerNumber: 1, IsHost: true, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+	if err := db.Create(&hostMember).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	expectPrefix := func(err error, prefix string) {
+		t.Helper()
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("err = %v, want %s", err, prefix)
+		}
+	}
+
+	// 部屋一覧にも承認制であることが載る
+	listed, err := repo.Room.GetActiveRooms(RoomSearchParams{})
+	if err != nil || len(listed) != 1 || !listed[0].RequiresApproval {
+		t.Fatalf("部屋一覧 = %+v, %v", listed, err)
+	}
+
+	// 承認制の部屋はパスワードが合っていても直接は参加できない
+	expectPrefix(repo.Room.JoinRoom(room.ID, applicant.ID, password), "APPROVAL_REQUIRED:")
+
+	// 他の部屋に参加したままでも申請はできるが、そのままでは承認できない
+	elsewhere := models.RoomMember{ID: uuid.New(), RoomID: uuid.New(), UserID: applicant.ID, PlayerNumber: 1, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+	if err := db.Create(&elsewhere).Error; err != nil {
+		t.Fatal(err)
+	}
+	expectPrefix(repo.Room.JoinRoom(room.ID, applicant.ID, ""), "APPROVAL_REQUIRED:")
+
+	request, err := repo.JoinRequest.CreateJoinRequest(room.ID, applicant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := repo.JoinRequest.CreateJoinRequest(room.ID, applicant.ID)
+	if err != nil || again.ID != request.ID {
+		t.Errorf("二重申請で新しい申請が作られた: %v, %v", again, err)
+	}
+	other, err := repo.JoinRequest.CreateJoinRequest(room.ID, rejected.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := repo.JoinRequest.GetPendingRequests(room.ID)
+	if err != nil || len(pending) != 2 || pending[0].User.DisplayName != applicant.DisplayName {
+		t.Fatalf("審査待ちの申請 = %+v, %v", pending, err)
+	}
+
+	_, err = repo.Room.ApproveJoinRequest(room.ID, request.ID)
+	expectPrefix(err, "OTHER_ROOM_ACTIVE:")
+	if err := db.Model(&elsewhere).Update("status", models.MemberStatusLeft).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 見送った申請は承認できない
+	if _, err := repo.JoinRequest.RejectJoinRequest(room.ID, other.ID, host.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Room.ApproveJoinRequest(room.ID, other.ID)
+	expectPrefix(err, "JOIN_REQUEST_INVALID:")
+	if repo.Room.IsUserJoinedRoom(room.ID, rejected.ID) {
+		t.Error("見送った申請者が参加している")
+	}
+
+	// 承認すると参加が完了し、承認が room_logs に残る
+	approved, err := repo.Room.ApproveJoinRequest(room.ID, request.ID)
+	if err != nil || approved.Status != models.JoinRequestStatusApproved {
+		t.Fatalf("ApproveJoinRequest = %+v, %v", approved, err)
+	}
+	if !repo.Room.IsUserJoinedRoom(room.ID, applicant.ID) {
+		t.Error("承認後も申請者が参加していない")
+	}
+	var logs []models.RoomLog
+	if err := db.Where("room_id = ? AND action IN ?", room.ID, []string{"approve_join", "reject_join"}).Find(&logs).Error; err != nil || len(logs) != 2 {
+		t.Errorf("審査のログ = %d件, %v, want 2", len(logs), err)
+	}
+	_, err = repo.Room.ApproveJoinRequest(room.ID, request.ID)
+	expectPrefix(err, "JOIN_REQUEST_INVALID:")
+
+	// 満員で参加できなければ申請は審査待ちのまま残る
+	if err := db.Model(room).Update("max_players", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	retry, err := repo.JoinRequest.CreateJoinRequest(room.ID, rejected.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Room.ApproveJoinRequest(room.ID, retry.ID)
+	expectPrefix(err, "ROOM_FULL:")
+	if found, _ := repo.JoinRequest.FindPendingRequest(room.ID, rejected.ID); found == nil || found.ID != retry.ID {
+		t.Errorf("参加できなかった申請が審査待ちに残っていない: %+v", found)
+	}
+
+	// 解散すると審査待ちの申請は取り消される
+	if err := repo.Room.DismissRoom(room.ID, models.DismissReasonHost); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := repo.JoinRequest.FindPendingRequest(room.ID, rejected.ID); found != nil {
+		t.Errorf("解散後も審査待ちの申請が残っている: %+v", found)
+	}
+	if err := repo.JoinRequest.CancelJoinRequest(room.ID, rejected.ID); err == nil {
+		t.Error("取り消し済みの申請を取り下げられてしまう")
+	}
+}
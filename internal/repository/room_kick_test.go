@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// kickTestEnv キック・BANのテスト用に、ユーザーと部屋を作る
+type kickTestEnv struct {
+	t    *testing.T
+	db   *gorm.DB
+	repo *Repository
+}
+
+func newKickTestEnv(t *testing.T) *kickTestEnv {
+	t.Helper()
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomWaitlistEntry{}, &models.RoomJoinRequest{}, &models.HostBan{})
+	return &kickTestEnv{t: t, db: db, repo: repo}
+}
+
+func (e *kickTestEnv) newUser(name string) *models.User {
+	e.t.Helper()
+	return createTestUser(e.t, e.repo, name)
+}
+
+// newRoom host が一人で待っている部屋を作る
+func (e *kickTestEnv) newRoom(code string, host *models.User, maxPlayers int) *models.Room {
+	e.t.Helper()
+	room := &models.Room{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		RoomCode:       code,
+		Name:           "キックの部屋",
+		GameVersionID:  uuid.New(),
+		HostUserID:     host.ID,
+		MaxPlayers:     maxPlayers,
+		CurrentPlayers: 1,
+		IsActive:       true,
+	}
+	if err := e.db.Create(room).Error; err != nil {
+		e.t.Fatal(err)
+	}
+	hostMember := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: host.ID, PlayerNumber: 1, IsHost: true, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+	if err := e.db.Create(&hostMember).Error; err != nil {
+		e.t.Fatal(err)
+	}
+	return room
+}
+
+func TestRoomKickMember(t *testing.T) {
+	env := newKickTestEnv(t)
+	repo := env.repo
+	host, guest := env.newUser("ホスト"), env.newUser("参加者")
+	room := env.newRoom("KICK01", host, 4)
+
+	if err := repo.Room.JoinRoom(room.ID, guest.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if repo.Room.IsUserKickedFromRoom(room.ID, guest.ID) {
+		t.Error("キック前からキック済みになっている")
+	}
+
+	if err := repo.Room.KickMember(room.ID, guest.ID, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.Room.IsUserKickedFromRoom(room.ID, guest.ID) {
+		t.Error("キックしたメンバーがキック済みになっていない")
+	}
+	if repo.Room.IsUserKickedFromRoom(room.ID, host.ID) {
+		t.Error("ホストがキック済みになっている")
+	}
+
+	// キックされたメンバーは再参加できない
+	if err := repo.Room.JoinRoom(room.ID, guest.ID, ""); err == nil || !strings.HasPrefix(err.Error(), "KICKED:") {
+		t.Errorf("再参加 err = %v, want KICKED", err)
+	}
+}
+
+func TestRoomKickMemberWithReasonAndDuration(t *testing.T) {
+	env := newKickTestEnv(t)
+	repo := env.repo
+	host, guest := env.newUser("ホスト"), env.newUser("参加者")
+	room := env.newRoom("KICK02", host, 4)
+
+	if err := repo.Room.JoinRoom(room.ID, guest.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	reason := "暴言"
+	until := time.Now().Add(time.Hour)
+	if err := repo.Room.KickMember(room.ID, guest.ID, &reason, &until); err != nil {
+		t.Fatal(err)
+	}
+
+	var member models.RoomMember
+	if err := env.db.Where("room_id = ? AND user_id = ?", room.ID, guest.ID).First(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+	if member.KickReason == nil || *member.KickReason != reason || member.KickedUntil == nil {
+		t.Fatalf("キックの理由・期限が保存されていない: %+v", member)
+	}
+
+	// 期限内は再参加・キャンセル待ちともにできない
+	if err := repo.Room.JoinRoom(room.ID, guest.ID, ""); err == nil || !strings.HasPrefix(err.Error(), "KICKED:") {
+		t.Errorf("期限内の再参加 err = %v, want KICKED", err)
+	}
+	if _, err := repo.RoomWaitlist.JoinWaitlist(room.ID, guest.ID); err == nil || !strings.HasPrefix(err.Error(), "KICKED:") {
+		t.Errorf("期限内のキャンセル待ち err = %v, want KICKED", err)
+	}
+
+	// 期限が過ぎれば同じ行を使って再参加でき、キックの記録は消える
+	if err := env.db.Model(&member).Update("kicked_until", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if repo.Room.IsUserKickedFromRoom(room.ID, guest.ID) {
+		t.Error("期限切れのキックがまだ有効になっている")
+	}
+	if err := repo.Room.JoinRoom(room.ID, guest.ID, ""); err != nil {
+		t.Fatalf("期限後の再参加 err = %v", err)
+	}
+	var rejoined []models.RoomMember
+	if err := env.db.Where("room_id = ? AND user_id = ?", room.ID, guest.ID).Find(&rejoined).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rejoined) != 1 || rejoined[0].Status != models.MemberStatusActive || rejoined[0].KickReason != nil || rejoined[0].KickedUntil != nil {
+		t.Errorf("再参加後のメンバー = %+v, want キック記録なしの active 1件", rejoined)
+	}
+}
+
+func TestHostBanAppliesToAllRoomsOfHost(t *testing.T) {
+	env := newKickTestEnv(t)
+	repo := env.repo
+	host, otherHost, troll := env.newUser("ホスト"), env.newUser("別のホスト"), env.newUser("荒らし")
+
+	reason := "迷惑行為"
+	if _, err := repo.HostBan.BanUser(host.ID, troll.ID, &reason); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.HostBan.BanUser(host.ID, host.ID, nil); err == nil {
+		t.Error("自分自身をBANできてしまう")
+	}
+	// 既にBAN済みなら理由だけ更新する
+	if _, err := repo.HostBan.BanUser(host.ID, troll.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	bans, err := repo.HostBan.GetBansByHost(host.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bans) != 1 || bans[0].Reason != nil || bans[0].Banned.DisplayName != "荒らし" {
+		t.Fatalf("BANリスト = %+v, want 理由なしの荒らし1件", bans)
+	}
+
+	tonight, tomorrow := env.newRoom("BAN001", host, 4), env.newRoom("BAN002", host, 1)
+	others := env.newRoom("BAN003", otherHost, 4)
+
+	tests := []struct {
+		name string
+		join func() error
+		want string
+	}{
+		{"同じホストの部屋に参加", func() error { return repo.Room.JoinRoom(tonight.ID, troll.ID, "") }, "HOST_BANNED:"},
+		{"同じホストの満員の部屋でキャンセル待ち", func() error {
+			_, err := repo.RoomWaitlist.JoinWaitlist(tomorrow.ID, troll.ID)
+			return err
+		}, "HOST_BANNED:"},
+		{"別のホストの部屋には参加できる", func() error { return repo.Room.JoinRoom(others.ID, troll.ID, "") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.join()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	// BANを解除すれば参加できる
+	if err := repo.Room.LeaveRoom(others.ID, troll.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.HostBan.UnbanUser(host.ID, troll.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.HostBan.UnbanUser(host.ID, troll.ID); err == nil {
+		t.Error("BAN済みでないユーザーの解除がエラーにならない")
+	}
+	if err := repo.Room.JoinRoom(tonight.ID, troll.ID, ""); err != nil {
+		t.Errorf("BAN解除後の参加 err = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"mhp-rooms/internal/models"
@@ -35,6 +36,19 @@ func (r *roomLogRepository) ListRecentLogs(limit, offset int) ([]models.RoomLog,
 	return logs, err
 }
 
+// ListRoomLogs 部屋の操作ログのうち actions に含まれるものを古い順で取得
+func (r *roomLogRepository) ListRoomLogs(roomID uuid.UUID, actions []string) ([]models.RoomLog, error) {
+	var logs []models.RoomLog
+	err := r.db.GetConn().
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "display_name")
+		}).
+		Where("room_id = ? AND action IN ?", roomID, actions).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error
+	return logs, err
+}
+
 func (r *roomLogRepository) CountLogs() (int64, error) {
 	var count int64
 	err := r.db.GetConn().Model(&models.RoomLog{}).Count(&count).Error
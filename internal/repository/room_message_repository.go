@@ -1,8 +1,13 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"mhp-rooms/internal/models"
 )
@@ -19,13 +24,15 @@ func (r *roomMessageRepository) CreateMessage(message *models.RoomMessage) error
 	return r.db.GetConn().Create(message).Error
 }
 
+// GetMessages 部屋のメッセージを古い順に取得する。削除済みのメッセージも墓標として表示できるよう含める
 func (r *roomMessageRepository) GetMessages(roomID uuid.UUID, limit int, beforeID *uuid.UUID) ([]models.RoomMessage, error) {
 	var messages []models.RoomMessage
 	query := r.db.GetConn().
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "supabase_user_id", "email", "username", "display_name", "avatar_url", "bio", "psn_online_id", "nintendo_network_id", "nintendo_switch_id", "pretendo_network_id", "twitter_id", "is_active", "role", "created_at", "updated_at")
 		}).
-		Where("room_id = ? AND is_deleted = ?", roomID, false).
+		Preload("Attachment").
+		Where("room_id = ?", roomID).
 		Order("created_at DESC").
 		Limit(limit)
 
@@ -46,11 +53,234 @@ func (r *roomMessageRepository) GetMessages(roomID uuid.UUID, limit int, beforeI
 		messages[i], messages[j] = messages[j], messages[i]
 	}
 
+	if err := r.attachReplyQuotes(messages); err != nil {
+		return nil, err
+	}
 	return messages, nil
 }
 
-func (r *roomMessageRepository) DeleteMessage(id uuid.UUID) error {
+// attachReplyQuotes 返信先のメッセージをまとめて読み込み、引用の要約を付ける
+func (r *roomMessageRepository) attachReplyQuotes(messages []models.RoomMessage) error {
+	var parentIDs []uuid.UUID
+	for _, message := range messages {
+		if message.ReplyToID != nil {
+			parentIDs = append(parentIDs, *message.ReplyToID)
+		}
+	}
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	var parents []models.RoomMessage
+	if err := r.db.GetConn().Preload("User").Where("id IN ?", parentIDs).Find(&parents).Error; err != nil {
+		return fmt.Errorf("返信先メッセージの取得に失敗しました: %w", err)
+	}
+	quotes := make(map[uuid.UUID]*models.RoomMessageQuote, len(parents))
+	for i := range parents {
+		quotes[parents[i].ID] = parents[i].Quote()
+	}
+	for i := range messages {
+		if messages[i].ReplyToID != nil {
+			messages[i].ReplyTo = quotes[*messages[i].ReplyToID]
+		}
+	}
+	return nil
+}
+
+// FindMessageByID IDでメッセージを取得
+func (r *roomMessageRepository) FindMessageByID(id uuid.UUID) (*models.RoomMessage, error) {
+	var message models.RoomMessage
+	if err := r.db.GetConn().Preload("User").Preload("Attachment").Where("id = ?", id).First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// EachMessageBatch 部屋のメッセージを古い順に batchSize 件ずつ読み込み、fn に渡す。
+// 全件をメモリに載せずに書き出すためのもので、fn がエラーを返すとそこで打ち切る
+func (r *roomMessageRepository) EachMessageBatch(roomID uuid.UUID, batchSize int, fn func([]models.RoomMessage) error) error {
+	var last *models.RoomMessage
+	for {
+		query := r.db.GetConn().
+			Preload("User", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "username", "display_name")
+			}).
+			Preload("Attachment").
+			Where("room_id = ?", roomID)
+		// 同じ時刻のメッセージがあっても取りこぼさないよう (created_at, id) の順で続きを読む
+		if last != nil {
+			query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", last.CreatedAt, last.CreatedAt, last.ID)
+		}
+
+		var messages []models.RoomMessage
+		if err := query.Order("created_at ASC, id ASC").Limit(batchSize).Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		if err := fn(messages); err != nil {
+			return err
+		}
+		if len(messages) < batchSize {
+			return nil
+		}
+		last = &messages[len(messages)-1]
+	}
+}
+
+// EditMessage メッセージ本文を書き換え、編集前の本文を履歴として残す
+func (r *roomMessageRepository) EditMessage(id, editorUserID uuid.UUID, text string) (*models.RoomMessage, error) {
+	var message models.RoomMessage
+	err := r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&message).Error; err != nil {
+			return err
+		}
+		if message.IsDeleted {
+			return fmt.Errorf("削除されたメッセージは編集できません")
+		}
+		if message.Message == text {
+			return nil
+		}
+
+		edit := models.RoomMessageEdit{
+			MessageID:       message.ID,
+			EditorUserID:    editorUserID,
+			PreviousMessage: message.Message,
+		}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		message.Message = text
+		message.EditedAt = &now
+		return tx.Model(&models.RoomMessage{}).Where("id = ?", message.ID).Updates(map[string]interface{}{
+			"message":   text,
+			"edited_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetMessageEdits 指定メッセージの編集履歴を古い順に取得する
+func (r *roomMessageRepository) GetMessageEdits(messageIDs []uuid.UUID) ([]models.RoomMessageEdit, error) {
+	var edits []models.RoomMessageEdit
+	if len(messageIDs) == 0 {
+		return edits, nil
+	}
+	err := r.db.GetConn().
+		Preload("Editor").
+		Where("message_id IN ?", messageIDs).
+		Order("created_at ASC").
+		Find(&edits).Error
+	return edits, err
+}
+
+// MarkRead 既読位置を message まで進める。既に新しいメッセージまで読んでいる場合は戻さない
+func (r *roomMessageRepository) MarkRead(roomID, userID uuid.UUID, message *models.RoomMessage) error {
+	marker := models.RoomReadMarker{
+		RoomID:            roomID,
+		UserID:            userID,
+		LastReadMessageID: message.ID,
+		LastReadAt:        message.CreatedAt,
+		UpdatedAt:         time.Now(),
+	}
+	ts, _ := timeComparison(r.db)
+	return r.db.GetConn().
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "room_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_read_message_id", "last_read_at", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: ts("room_read_markers.last_read_at") + " < " + ts("excluded.last_read_at")},
+			}},
+		}).
+		Create(&marker).Error
+}
+
+// GetReadMarker 既読位置を取得する。まだ何も読んでいなければ nil
+func (r *roomMessageRepository) GetReadMarker(roomID, userID uuid.UUID) (*models.RoomReadMarker, error) {
+	var marker models.RoomReadMarker
+	err := r.db.GetConn().Where("room_id = ? AND user_id = ?", roomID, userID).First(&marker).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &marker, nil
+}
+
+// CountUnread 既読位置より後に投稿された他のメンバーのチャット・画像メッセージ数を数える。
+// 既読位置がなければ参加した時点から数える
+func (r *roomMessageRepository) CountUnread(roomID, userID uuid.UUID) (int64, error) {
+	marker, err := r.GetReadMarker(roomID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var since time.Time
+	if marker != nil {
+		since = marker.LastReadAt
+	} else {
+		var member models.RoomMember
+		err := r.db.GetConn().
+			Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusActive).
+			Order("joined_at DESC").
+			First(&member).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		since = member.JoinedAt
+	}
+
+	ts, param := timeComparison(r.db)
+	var count int64
+	err = r.db.GetConn().Model(&models.RoomMessage{}).
+		Where("room_id = ? AND user_id <> ? AND message_type IN ? AND is_deleted = ?", roomID, userID, []string{models.RoomMessageTypeChat, models.RoomMessageTypeImage}, false).
+		Where(ts("created_at")+" > "+param, since).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteMessage メッセージを論理削除し、削除したユーザーを記録する
+func (r *roomMessageRepository) DeleteMessage(id, deletedByUserID uuid.UUID) error {
 	return r.db.GetConn().Model(&models.RoomMessage{}).
 		Where("id = ?", id).
-		Update("is_deleted", true).Error
+		Updates(map[string]interface{}{
+			"is_deleted":         true,
+			"deleted_by_user_id": deletedByUserID,
+		}).Error
+}
+
+// ListAttachmentsByRoom 部屋のメッセージに添付された画像を取得
+func (r *roomMessageRepository) ListAttachmentsByRoom(roomID uuid.UUID) ([]models.RoomMessageAttachment, error) {
+	var attachments []models.RoomMessageAttachment
+	err := r.db.GetConn().Where("room_id = ?", roomID).Find(&attachments).Error
+	return attachments, err
+}
+
+// ListOrphanedAttachments 削除済みメッセージまたは解散済みの部屋に残っている添付画像を取得
+// （削除時にストレージの後始末が失敗した分を定期処理で拾い直すため）
+func (r *roomMessageRepository) ListOrphanedAttachments(limit int) ([]models.RoomMessageAttachment, error) {
+	var attachments []models.RoomMessageAttachment
+	err := r.db.GetConn().
+		Joins("JOIN room_messages ON room_messages.id = room_message_attachments.message_id").
+		Joins("JOIN rooms ON rooms.id = room_message_attachments.room_id").
+		Where("room_messages.is_deleted = ? OR (rooms.is_active = ? AND rooms.dismissed_at IS NOT NULL)", true, false).
+		Order("room_message_attachments.created_at ASC").
+		Limit(limit).
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// DeleteAttachments 添付画像のレコードを削除（ストレージ上の実体は呼び出し側で消す）
+func (r *roomMessageRepository) DeleteAttachments(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.GetConn().Where("id IN ?", ids).Delete(&models.RoomMessageAttachment{}).Error
 }
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomMessageEditAndDelete(t *testing.T) {
+	_, repo := newTestRepository(t, &models.User{}, &models.RoomMessage{}, &models.RoomMessageEdit{}, &models.RoomMessageAttachment{})
+
+	author, host := createTestUser(t, repo, "投稿者"), createTestUser(t, repo, "ホスト")
+
+	roomID := uuid.New()
+	kept := &models.RoomMessage{RoomID: roomID, UserID: author.ID, Message: "集会所1です", MessageType: "chat"}
+	removed := &models.RoomMessage{RoomID: roomID, UserID: author.ID, Message: "荒らし発言", MessageType: "chat"}
+	for _, m := range []*models.RoomMessage{kept, removed} {
+		if err := repo.RoomMessage.CreateMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edits := []string{"集会所2です", "集会所2です", "集会所3です"}
+	for _, text := range edits {
+		updated, err := repo.RoomMessage.EditMessage(kept.ID, author.ID, text)
+		if err != nil {
+			t.Fatalf("EditMessage(%q) error = %v", text, err)
+		}
+		if updated.Message != text || updated.EditedAt == nil {
+			t.Errorf("EditMessage(%q) = %+v", text, updated)
+		}
+	}
+
+	// 同じ本文への編集は履歴に残さない
+	history, err := repo.RoomMessage.GetMessageEdits([]uuid.UUID{kept.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHistory := []string{"集会所1です", "集会所2です"}
+	if len(history) != len(wantHistory) {
+		t.Fatalf("編集履歴 = %+v", history)
+	}
+	for i, want := range wantHistory {
+		if history[i].PreviousMessage != want || history[i].Editor.DisplayName != "投稿者" {
+			t.Errorf("編集履歴[%d] = %q (%s), want %q", i, history[i].PreviousMessage, history[i].Editor.DisplayName, want)
+		}
+	}
+
+	if err := repo.RoomMessage.DeleteMessage(removed.ID, host.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.RoomMessage.EditMessage(removed.ID, author.ID, "書き換え"); err == nil {
+		t.Error("削除されたメッセージは編集できないはず")
+	}
+
+	// 削除済みのメッセージも墓標として取得できる
+	messages, err := repo.RoomMessage.GetMessages(roomID, 20, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("メッセージ = %d 件, want 2", len(messages))
+	}
+	found, err := repo.RoomMessage.FindMessageByID(removed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.IsDeleted || found.DeletedByUserID == nil || *found.DeletedByUserID != host.ID {
+		t.Errorf("削除後のメッセージ = %+v", found)
+	}
+}
+
+func TestRoomMessageReplies(t *testing.T) {
+	_, repo := newTestRepository(t, &models.User{}, &models.RoomMessage{}, &models.RoomMessageEdit{}, &models.RoomMessageAttachment{})
+
+	author := createTestUser(t, repo, "投稿者")
+
+	roomID := uuid.New()
+	long := strings.Repeat("あ", models.RoomMessageQuoteLength+10)
+	parent := &models.RoomMessage{RoomID: roomID, UserID: author.ID, Message: long, MessageType: "chat"}
+	removed := &models.RoomMessage{RoomID: roomID, UserID: author.ID, Message: "消される発言", MessageType: "chat"}
+	for _, m := range []*models.RoomMessage{parent, removed} {
+		if err := repo.RoomMessage.CreateMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reply := &models.RoomMessage{RoomID: roomID, UserID: author.ID, Message: "返信です", MessageType: "chat", ReplyToID: &parent.ID}
+	replyToRemoved := &models.RoomMessage{RoomID: roomID, UserID: author.ID, Message: "返信です", MessageType: "chat", ReplyToID: &removed.ID}
+	for _, m := range []*models.RoomMessage{reply, replyToRemoved} {
+		if err := repo.RoomMessage.CreateMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.RoomMessage.DeleteMessage(removed.ID, author.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := repo.RoomMessage.GetMessages(roomID, 20, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[uuid.UUID]models.RoomMessage, len(messages))
+	for _, m := range messages {
+		byID[m.ID] = m
+	}
+
+	if q := byID[parent.ID].ReplyTo; q != nil {
+		t.Errorf("返信でないメッセージに引用が付いている: %+v", q)
+	}
+	q := byID[reply.ID].ReplyTo
+	if q == nil {
+		t.Fatal("返信の引用が付いていない")
+	}
+	wantExcerpt := strings.Repeat("あ", models.RoomMessageQuoteLength) + "…"
+	if q.ID != parent.ID || q.UserName != "投稿者" || q.Message != wantExcerpt || q.IsDeleted {
+		t.Errorf("引用 = %+v", q)
+	}
+	q = byID[replyToRemoved.ID].ReplyTo
+	if q == nil || !q.IsDeleted || q.Message != "" {
+		t.Errorf("削除された返信先の引用 = %+v", q)
+	}
+}
+
+func TestRoomReadMarkers(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.RoomMember{}, &models.RoomMessage{}, &models.RoomReadMarker{})
+
+	reader, writer := createTestUser(t, repo, "読み手"), createTestUser(t, repo, "書き手")
+
+	roomID := uuid.New()
+	base := time.Now().Add(-time.Hour)
+	member := models.RoomMember{ID: uuid.New(), RoomID: roomID, UserID: reader.ID, PlayerNumber: 2, Status: models.MemberStatusActive, JoinedAt: base.Add(time.Minute)}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 参加前の発言・自分の発言・システムメッセージ・削除済みは未読に数えない
+	newMessage := func(minutes int, userID uuid.UUID, messageType string, deleted bool) *models.RoomMessage {
+		t.Helper()
+		m := &models.RoomMessage{BaseModel: models.BaseModel{CreatedAt: base.Add(time.Duration(minutes) * time.Minute)}, RoomID: roomID, UserID: userID, Message: "本文", MessageType: messageType, IsDeleted: deleted}
+		if err := repo.RoomMessage.CreateMessage(m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	newMessage(0, writer.ID, "chat", false)
+	newMessage(2, reader.ID, "system", false)
+	first := newMessage(3, writer.ID, "chat", false)
+	newMessage(4, reader.ID, "chat", false)
+	second := newMessage(5, writer.ID, "chat", false)
+	newMessage(6, writer.ID, "chat", true)
+	third := newMessage(7, writer.ID, "chat", false)
+
+	assertUnread := func(want int64) {
+		t.Helper()
+		got, err := repo.RoomMessage.CountUnread(roomID, reader.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("CountUnread() = %d, want %d", got, want)
+		}
+	}
+
+	if marker, err := repo.RoomMessage.GetReadMarker(roomID, reader.ID); err != nil || marker != nil {
+		t.Fatalf("GetReadMarker() = %+v, %v, want nil", marker, err)
+	}
+	assertUnread(3)
+
+	if err := repo.RoomMessage.MarkRead(roomID, reader.ID, second); err != nil {
+		t.Fatal(err)
+	}
+	assertUnread(1)
+
+	// 古いメッセージで既読位置は戻らない
+	if err := repo.RoomMessage.MarkRead(roomID, reader.ID, first); err != nil {
+		t.Fatal(err)
+	}
+	marker, err := repo.RoomMessage.GetReadMarker(roomID, reader.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marker == nil || marker.LastReadMessageID != second.ID {
+		t.Errorf("既読位置 = %+v, want %v", marker, second.ID)
+	}
+
+	if err := repo.RoomMessage.MarkRead(roomID, reader.ID, third); err != nil {
+		t.Fatal(err)
+	}
+	assertUnread(0)
+}
+
+func TestRoomReadMarkersMixedTimeZones(t *testing.T) {
+	db := newTestDB(t, &models.RoomMessage{}, &models.RoomReadMarker{})
+	// libSQL と同じ日時の比較式を SQLite で確かめる
+	repo := NewRepository(testDB{conn: db, dbType: "turso"})
+
+	// 日時は文字列として保存されるため、タイムゾーン表記が違うと文字列の大小と前後が一致しない
+	jst := time.FixedZone("JST", 9*60*60)
+	roomID, readerID, writerID := uuid.New(), uuid.New(), uuid.New()
+	newMessage := func(at time.Time) *models.RoomMessage {
+		t.Helper()
+		m := &models.RoomMessage{BaseModel: models.BaseModel{CreatedAt: at}, RoomID: roomID, UserID: writerID, Message: "本文", MessageType: "chat"}
+		if err := repo.RoomMessage.CreateMessage(m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	assertMarker := func(want *models.RoomMessage) {
+		t.Helper()
+		marker, err := repo.RoomMessage.GetReadMarker(roomID, readerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if marker == nil || marker.LastReadMessageID != want.ID {
+			t.Errorf("既読位置 = %+v, want %v", marker, want.ID)
+		}
+	}
+
+	noon := newMessage(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	stale := newMessage(time.Date(2026, 10, 1, 20, 30, 0, 0, jst)) // 11:30 UTC
+	later := newMessage(time.Date(2026, 10, 1, 21, 30, 0, 0, jst)) // 12:30 UTC
+	latest := newMessage(time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC))
+	newMessage(time.Date(2026, 10, 1, 21, 45, 0, 0, jst)) // 12:45 UTC（既読）
+	newMessage(time.Date(2026, 10, 1, 13, 10, 0, 0, time.UTC))
+
+	steps := []struct {
+		name    string
+		message *models.RoomMessage
+		want    *models.RoomMessage
+	}{
+		{"最初の既読", noon, noon},
+		{"文字列では後ろでも古いメッセージで戻らない", stale, noon},
+		{"新しいメッセージで進む", later, later},
+		{"文字列では前でも新しいメッセージで進む", latest, latest},
+	}
+	for _, step := range steps {
+		if err := repo.RoomMessage.MarkRead(roomID, readerID, step.message); err != nil {
+			t.Fatal(err)
+		}
+		t.Run(step.name, func(t *testing.T) { assertMarker(step.want) })
+	}
+
+	if got, err := repo.RoomMessage.CountUnread(roomID, readerID); err != nil || got != 1 {
+		t.Errorf("CountUnread() = %d, %v, want 1", got, err)
+	}
+}
+
+func TestRoomMessageAttachments(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.Room{}, &models.RoomMessage{}, &models.RoomMessageAttachment{})
+
+	author := createTestUser(t, repo, "投稿者")
+
+	now := time.Now()
+	newRoom := func(code string, dismissed bool) *models.Room {
+		t.Helper()
+		room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, RoomCode: code, Name: code, GameVersionID: uuid.New(), HostUserID: author.ID, MaxPlayers: 4, IsActive: true}
+		if err := db.Create(room).Error; err != nil {
+			t.Fatal(err)
+		}
+		if dismissed {
+			if err := db.Model(room).Updates(map[string]interface{}{"is_active": false, "dismissed_at": now}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		return room
+	}
+	live := newRoom("LIVE", false)
+	dismissed := newRoom("DISMISSED", true)
+
+	newImage := func(roomID uuid.UUID, caption string) *models.RoomMessage {
+		t.Helper()
+		m := &models.RoomMessage{
+			RoomID:      roomID,
+			UserID:      author.ID,
+			Message:     caption,
+			MessageType: models.RoomMessageTypeImage,
+			Attachment: &models.RoomMessageAttachment{
+				RoomID:              roomID,
+				ObjectPath:          "chat/" + roomID.String() + "/shot.png",
+				URL:                 "https://assets.example.test/chat/shot.png",
+				ContentType:         "image/png",
+				SizeBytes:           1024,
+				Width:               1280,
+				Height:              720,
+				ThumbnailObjectPath: "chat/" + roomID.String() + "/thumbs/shot.jpg",
+				ThumbnailURL:        "https://assets.example.test/chat/thumbs/shot.jpg",
+				ThumbnailWidth:      320,
+				ThumbnailHeight:     180,
+			},
+		}
+		if err := repo.RoomMessage.CreateMessage(m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	kept := newImage(live.ID, "")
+	removed := newImage(live.ID, "消す")
+	inDismissed := newImage(dismissed.ID, "解散済み")
+	if kept.Attachment.MessageID != kept.ID {
+		t.Fatalf("添付のメッセージID = %s, want %s", kept.Attachment.MessageID, kept.ID)
+	}
+
+	// 一覧・単体取得とも添付を読み込む
+	messages, err := repo.RoomMessage.GetMessages(live.ID, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || messages[0].Attachment == nil || messages[0].Attachment.ThumbnailWidth != 320 {
+		t.Fatalf("GetMessages() = %+v", messages)
+	}
+	found, err := repo.RoomMessage.FindMessageByID(removed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Attachment == nil || found.Attachment.ID != removed.Attachment.ID {
+		t.Fatalf("FindMessageByID().Attachment = %+v", found.Attachment)
+	}
+	if quote := kept.Quote(); quote.Message != models.RoomMessageImageQuote {
+		t.Errorf("キャプションなし画像の引用 = %q, want %q", quote.Message, models.RoomMessageImageQuote)
+	}
+
+	byRoom, err := repo.RoomMessage.ListAttachmentsByRoom(live.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byRoom) != 2 {
+		t.Fatalf("ListAttachmentsByRoom() = %d 件, want 2", len(byRoom))
+	}
+
+	// 削除済みメッセージと解散済みの部屋の添付だけが後始末の対象
+	if err := repo.RoomMessage.DeleteMessage(removed.ID, author.ID); err != nil {
+		t.Fatal(err)
+	}
+	orphaned, err := repo.RoomMessage.ListOrphanedAttachments(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[uuid.UUID]bool{}
+	for _, a := range orphaned {
+		got[a.MessageID] = true
+	}
+	if len(orphaned) != 2 || !got[removed.ID] || !got[inDismissed.ID] {
+		t.Fatalf("ListOrphanedAttachments() = %+v, want 削除済みと解散済みの2件", orphaned)
+	}
+
+	ids := []uuid.UUID{orphaned[0].ID, orphaned[1].ID}
+	if err := repo.RoomMessage.DeleteAttachments(ids); err != nil {
+		t.Fatal(err)
+	}
+	if orphaned, err = repo.RoomMessage.ListOrphanedAttachments(10); err != nil || len(orphaned) != 0 {
+		t.Fatalf("削除後の ListOrphanedAttachments() = %+v, %v", orphaned, err)
+	}
+	if byRoom, err = repo.RoomMessage.ListAttachmentsByRoom(live.ID); err != nil || len(byRoom) != 1 || byRoom[0].MessageID != kept.ID {
+		t.Fatalf("削除後の ListAttachmentsByRoom() = %+v, %v", byRoom, err)
+	}
+}
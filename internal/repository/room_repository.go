@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +12,133 @@ import (
 	"mhp-rooms/internal/models"
 )
 
+// 部屋一覧の並び順（RoomSearchParams.Sort）
+const (
+	RoomSortRecent  = "recent"  // 新しい順（既定）
+	RoomSortPlayers = "players" // 参加人数が多い順
+	RoomSortVacancy = "vacancy" // 空き枠が多い順
+)
+
+// 部屋一覧のパスワード条件（RoomSearchParams.Password）
+const (
+	RoomPasswordAny     = ""        // 指定なし
+	RoomPasswordWith    = "with"    // パスワード付きの部屋のみ
+	RoomPasswordWithout = "without" // パスワードなしの部屋のみ
+)
+
+// RoomSearchParams は部屋一覧の検索条件です。
+type RoomSearchParams struct {
+	GameVersionID   *uuid.UUID
+	TargetMonster   string // ターゲットモンスターの部分一致
+	RankRequirement string // ランク条件の部分一致
+	Password        string // RoomPasswordWith / RoomPasswordWithout
+	HasVacancy      bool   // 参加受付中で空き枠がある部屋のみ
+	OpenWeapon      string // 募集中でまだ埋まっていない武器種（models.WeaponTypes のコード）
+	Query           string // 部屋名・説明の部分一致
+	Sort            string // RoomSortRecent / RoomSortPlayers / RoomSortVacancy
+	// 閲覧者のユーザーID。nil（未ログイン）の場合は公開の部屋だけを返す
+	ViewerID      *uuid.UUID
+	Limit, Offset int
+}
+
+// activeMemberCountSQL 部屋の参加中メンバー数を数える相関サブクエリ
+const activeMemberCountSQL = "(SELECT COUNT(*) FROM room_members rmc WHERE rmc.room_id = rooms.id AND rmc.status = 'active')"
+
+// listedRoomCondition 一覧に掲載する部屋の条件（" AND ..." 形式）。
+// 公開の部屋に加え、閲覧者がホスト・参加中の部屋と、フォロー承認済みのホストのフォロワー限定の部屋を含める
+func listedRoomCondition(viewerID *uuid.UUID) (string, []interface{}) {
+	if viewerID == nil {
+		return " AND rooms.visibility = ?", []interface{}{models.RoomVisibilityPublic}
+	}
+	return ` AND (rooms.visibility = ?
+			OR rooms.host_user_id = ?
+			OR EXISTS (SELECT 1 FROM room_members rmv WHERE rmv.room_id = rooms.id AND rmv.user_id = ? AND rmv.status = 'active')
+			OR (rooms.visibility = ? AND EXISTS (
+				SELECT 1 FROM user_follows uf
+				WHERE uf.follower_user_id = ? AND uf.following_user_id = rooms.host_user_id AND uf.status = ?)))`,
+		[]interface{}{
+			models.RoomVisibilityPublic,
+			*viewerID,
+			*viewerID,
+			models.RoomVisibilityFollowers, *viewerID, models.FollowStatusAccepted,
+		}
+}
+
+// likeEscaper LIKE のワイルドカードと、エスケープに使う \ を文字どおりに扱わせる
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern 入力を小文字にして、部分一致の LIKE パターン（ESCAPE '\' と組み合わせる）にする
+func containsPattern(keyword string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(keyword)) + "%"
+}
+
+// searchConditions 検索条件を rooms テーブルに対する " AND ..." 形式の SQL とパラメータに変換する
+func (params RoomSearchParams) searchConditions() (string, []interface{}) {
+	var sql strings.Builder
+	visibility, args := listedRoomCondition(params.ViewerID)
+	sql.WriteString(visibility)
+
+	if params.GameVersionID != nil {
+		sql.WriteString(" AND rooms.game_version_id = ?")
+		args = append(args, *params.GameVersionID)
+	}
+	if monster := strings.TrimSpace(params.TargetMonster); monster != "" {
+		sql.WriteString(" AND LOWER(COALESCE(rooms.target_monster, '')) LIKE ? ESCAPE '\\'")
+		args = append(args, containsPattern(monster))
+	}
+	if rank := strings.TrimSpace(params.RankRequirement); rank != "" {
+		sql.WriteString(" AND LOWER(COALESCE(rooms.rank_requirement, '')) LIKE ? ESCAPE '\\'")
+		args = append(args, containsPattern(rank))
+	}
+	switch params.Password {
+	case RoomPasswordWith:
+		sql.WriteString(" AND rooms.password_hash IS NOT NULL")
+	case RoomPasswordWithout:
+		sql.WriteString(" AND rooms.password_hash IS NULL")
+	}
+	if params.HasVacancy {
+		sql.WriteString(" AND rooms.is_closed = false AND " + activeMemberCountSQL + " < rooms.max_players")
+	}
+	if params.OpenWeapon != "" {
+		// 募集している武器種を、参加中のメンバーがまだ誰も担当していない部屋
+		sql.WriteString(` AND rooms.is_closed = false AND ` + activeMemberCountSQL + ` < rooms.max_players
+			AND rooms.wanted_weapons LIKE ?
+			AND NOT EXISTS (SELECT 1 FROM room_members rmw WHERE rmw.room_id = rooms.id AND rmw.status = 'active' AND rmw.weapon_type = ?)`)
+		args = append(args, `%"`+params.OpenWeapon+`"%`, params.OpenWeapon)
+	}
+	if query := strings.TrimSpace(params.Query); query != "" {
+		like := containsPattern(query)
+		sql.WriteString(" AND (LOWER(rooms.name) LIKE ? ESCAPE '\\' OR LOWER(COALESCE(rooms.description, '')) LIKE ? ESCAPE '\\')")
+		args = append(args, like, like)
+	}
+
+	return sql.String(), args
+}
+
+// sortOrder 並び順に応じた ORDER BY の項目（募集中/開始前の区別より後に適用）を返す
+func (params RoomSearchParams) sortOrder() string {
+	switch params.Sort {
+	case RoomSortPlayers:
+		return activeMemberCountSQL + " DESC, rooms.created_at DESC"
+	case RoomSortVacancy:
+		return "rooms.max_players - " + activeMemberCountSQL + " DESC, rooms.created_at DESC"
+	default:
+		return "rooms.created_at DESC"
+	}
+}
+
+// pagination Limit / Offset を安全な範囲に丸める
+func (params RoomSearchParams) pagination() (int, int) {
+	limit, offset := params.Limit, params.Offset
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
 type roomRepository struct {
 	db DBInterface
 }
@@ -58,6 +186,7 @@ func (r *roomRepository) FindRoomByID(id uuid.UUID) (*models.Room, error) {
 	var room models.Room
 	err := r.db.GetConn().
 		Preload("GameVersion").
+		Preload("Quest").
 		Preload("Host", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id", "supabase_user_id", "email", "username", "display_name", "avatar_url", "bio", "psn_online_id", "nintendo_network_id", "nintendo_switch_id", "pretendo_network_id", "twitter_id", "is_active", "role", "created_at", "updated_at")
 		}).
@@ -101,7 +230,7 @@ func (r *roomRepository) RoomCodeExists(roomCode string) (bool, error) {
 	return count > 0, nil
 }
 
-func (r *roomRepository) GetActiveRooms(gameVersionID *uuid.UUID, limit, offset int) ([]models.Room, error) {
+func (r *roomRepository) GetActiveRooms(params RoomSearchParams) ([]models.Room, error) {
 	// 効率的なクエリ: 1つのクエリで部屋情報と参加者数を取得
 	type roomQueryResult struct {
 		models.Room
@@ -120,6 +249,8 @@ func (r *roomRepository) GetActiveRooms(gameVersionID *uuid.UUID, limit, offset
 			rooms.game_version_id, rooms.host_user_id, rooms.max_players,
 			rooms.password_hash, rooms.target_monster, rooms.rank_requirement,
 			rooms.is_active, rooms.is_closed, rooms.created_at, rooms.updated_at, rooms.closed_at,
+			rooms.scheduled_start_at, rooms.scheduled_end_at, rooms.visibility, rooms.requires_approval,
+			rooms.wanted_weapons,
 			gv.name as game_version_name,
 			gv.code as game_version_code,
 			u.username as host_username,
@@ -132,19 +263,24 @@ func (r *roomRepository) GetActiveRooms(gameVersionID *uuid.UUID, limit, offset
 		LEFT JOIN room_members rm ON rooms.id = rm.room_id AND rm.status = 'active'
 		WHERE rooms.is_active = true`
 
-	params := []interface{}{}
-	if gameVersionID != nil {
-		sqlQuery += " AND rooms.game_version_id = ?"
-		params = append(params, *gameVersionID)
-	}
+	conditions, args := params.searchConditions()
+	sqlQuery += conditions
 
+	// 募集中の部屋を先に指定の並び順、開始前の部屋はその後に開始が近い順で並べる
+	upcoming, upcomingArgs := r.upcomingCondition(time.Now())
+	limit, offset := params.pagination()
 	sqlQuery += `
 		GROUP BY rooms.id, gv.id, u.id
-		ORDER BY rooms.created_at DESC
+		ORDER BY
+			CASE WHEN ` + upcoming + ` THEN 1 ELSE 0 END,
+			CASE WHEN ` + upcoming + ` THEN rooms.scheduled_start_at END ASC,
+			` + params.sortOrder() + `
 		LIMIT ? OFFSET ?`
-	params = append(params, limit, offset)
+	args = append(args, upcomingArgs...)
+	args = append(args, upcomingArgs...)
+	args = append(args, limit, offset)
 
-	if err := r.db.GetConn().Raw(sqlQuery, params...).Scan(&results).Error; err != nil {
+	if err := r.db.GetConn().Raw(sqlQuery, args...).Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
@@ -174,10 +310,13 @@ func (r *roomRepository) GetActiveRooms(gameVersionID *uuid.UUID, limit, offset
 }
 
 // GetActiveRoomsWithJoinStatus ユーザーの参加状態を含めて部屋一覧を取得（パフォーマンス最適化版）
-func (r *roomRepository) GetActiveRoomsWithJoinStatus(userID *uuid.UUID, gameVersionID *uuid.UUID, limit, offset int) ([]models.RoomWithJoinStatus, error) {
+func (r *roomRepository) GetActiveRoomsWithJoinStatus(userID *uuid.UUID, params RoomSearchParams) ([]models.RoomWithJoinStatus, error) {
+	if params.ViewerID == nil {
+		params.ViewerID = userID
+	}
 	if userID == nil {
 		// ユーザーIDがnilの場合は、通常の部屋一覧を取得してisJoinedをfalseに設定
-		normalRooms, err := r.GetActiveRooms(gameVersionID, limit, offset)
+		normalRooms, err := r.GetActiveRooms(params)
 		if err != nil {
 			return nil, err
 		}
@@ -200,6 +339,8 @@ func (r *roomRepository) GetActiveRoomsWithJoinStatus(userID *uuid.UUID, gameVer
 			rooms.game_version_id, rooms.host_user_id, rooms.max_players,
 			rooms.password_hash, rooms.target_monster, rooms.rank_requirement,
 			rooms.is_active, rooms.is_closed, rooms.created_at, rooms.updated_at, rooms.closed_at,
+			rooms.scheduled_start_at, rooms.scheduled_end_at, rooms.visibility, rooms.requires_approval,
+			rooms.wanted_weapons,
 			gv.name as game_version_name,
 			gv.code as game_version_code,
 			u.username as host_username,
@@ -224,20 +365,25 @@ func (r *roomRepository) GetActiveRoomsWithJoinStatus(userID *uuid.UUID, gameVer
 		WHERE rooms.is_active = true
 	`
 
-	params := []interface{}{*userID}
+	args := []interface{}{*userID}
 
-	if gameVersionID != nil {
-		query += " AND rooms.game_version_id = ?"
-		params = append(params, *gameVersionID)
-	}
+	conditions, conditionArgs := params.searchConditions()
+	query += conditions
+	args = append(args, conditionArgs...)
 
+	upcoming, upcomingArgs := r.upcomingCondition(time.Now())
+	limit, offset := params.pagination()
 	query += `
 		ORDER BY
 			CASE WHEN user_membership.is_joined IS NOT NULL THEN 0 ELSE 1 END,
-			rooms.created_at DESC
+			CASE WHEN ` + upcoming + ` THEN 1 ELSE 0 END,
+			CASE WHEN ` + upcoming + ` THEN rooms.scheduled_start_at END ASC,
+			` + params.sortOrder() + `
 		LIMIT ? OFFSET ?
 	`
-	params = append(params, limit, offset)
+	args = append(args, upcomingArgs...)
+	args = append(args, upcomingArgs...)
+	args = append(args, limit, offset)
 
 	type roomQueryResult struct {
 		models.Room
@@ -251,7 +397,7 @@ func (r *roomRepository) GetActiveRoomsWithJoinStatus(userID *uuid.UUID, gameVer
 	}
 
 	var results []roomQueryResult
-	if err := r.db.GetConn().Raw(query, params...).Scan(&results).Error; err != nil {
+	if err := r.db.GetConn().Raw(query, args...).Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
@@ -284,16 +430,12 @@ func (r *roomRepository) GetActiveRoomsWithJoinStatus(userID *uuid.UUID, gameVer
 	return roomsWithStatus, nil
 }
 
-// CountActiveRooms アクティブな部屋の総数を取得
-func (r *roomRepository) CountActiveRooms(gameVersionID *uuid.UUID) (int64, error) {
-	var count int64
-	query := r.db.GetConn().Model(&models.Room{}).Where("is_active = true")
+// CountActiveRooms 検索条件に一致するアクティブな部屋の総数を取得
+func (r *roomRepository) CountActiveRooms(params RoomSearchParams) (int64, error) {
+	conditions, args := params.searchConditions()
 
-	if gameVersionID != nil {
-		query = query.Where("game_version_id = ?", *gameVersionID)
-	}
-
-	if err := query.Count(&count).Error; err != nil {
+	var count int64
+	if err := r.db.GetConn().Raw("SELECT COUNT(*) FROM rooms WHERE rooms.is_active = true"+conditions, args...).Scan(&count).Error; err != nil {
 		return 0, err
 	}
 
@@ -323,6 +465,33 @@ func (r *roomRepository) DecrementRoomPlayerCount(id uuid.UUID) error {
 }
 
 func (r *roomRepository) JoinRoom(roomID, userID uuid.UUID, password string) error {
+	return r.joinRoom(roomID, userID, password, "", uuid.Nil)
+}
+
+// JoinRoomWithInvite 招待リンクのトークンで部屋に参加する。パスワードの確認は行わず、使用を room_logs に記録する
+func (r *roomRepository) JoinRoomWithInvite(roomID, userID uuid.UUID, inviteToken string) error {
+	return r.joinRoom(roomID, userID, "", inviteToken, uuid.Nil)
+}
+
+// ApproveJoinRequest ホストが参加申請を承認し、申請者を部屋に参加させる。参加できなければ申請は審査待ちのまま残る
+func (r *roomRepository) ApproveJoinRequest(roomID, requestID uuid.UUID) (*models.RoomJoinRequest, error) {
+	var request models.RoomJoinRequest
+	if err := r.db.GetConn().Where("id = ? AND room_id = ?", requestID, roomID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("JOIN_REQUEST_INVALID:参加申請が見つかりません")
+		}
+		return nil, err
+	}
+	if err := r.joinRoom(roomID, request.UserID, "", "", request.ID); err != nil {
+		return nil, err
+	}
+	request.Status = models.JoinRequestStatusApproved
+	return &request, nil
+}
+
+// joinRoom 招待リンク・承認済みの参加申請・パスワードのいずれかを確認して部屋に参加する。
+// 承認制の部屋は招待リンクか参加申請の承認がなければ APPROVAL_REQUIRED を返す
+func (r *roomRepository) joinRoom(roomID, userID uuid.UUID, password, inviteToken string, joinRequestID uuid.UUID) error {
 	return r.db.GetConn().Transaction(func(tx *gorm.DB) error {
 		// ユーザーの存在確認（開発環境では自動作成）
 		var user models.User
@@ -347,34 +516,63 @@ func (r *roomRepository) JoinRoom(roomID, userID uuid.UUID, password string) err
 			}
 		}
 
+		var room models.Room
+		if err := tx.Where("id = ?", roomID).First(&room).Error; err != nil {
+			return err
+		}
+
+		// 承認制の部屋への参加申請は、他の部屋に参加したままでも送れる（承認時に改めて確認する）
+		applying := room.RequiresApproval && room.HostUserID != userID && inviteToken == "" && joinRequestID == uuid.Nil
+
 		// 他の部屋に参加しているかチェック
 		var activeInOtherRoom models.RoomMember
 		query := "user_id = ? AND status = ? AND room_id != ?"
-		if err := tx.Where(query, userID, "active", roomID).First(&activeInOtherRoom).Error; err == nil {
+		if err := tx.Where(query, userID, "active", roomID).First(&activeInOtherRoom).Error; err == nil && !applying {
 			// 既に他の部屋に参加している場合
 			return fmt.Errorf("OTHER_ROOM_ACTIVE:既に別の部屋に参加しています")
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			// record not found 以外のエラーが発生した場合
 			return fmt.Errorf("部屋メンバー検索エラー: %w", err)
 		}
 
-		var room models.Room
-		if err := tx.Where("id = ?", roomID).First(&room).Error; err != nil {
+		// キックされたユーザーは期限まで同じ部屋に再参加できない
+		if err := checkJoinRestrictions(tx, &room, userID, time.Now()); err != nil {
 			return err
 		}
 
-		// キックされたユーザーは同じ部屋に再参加できない
-		var kickedMember models.RoomMember
-		if err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusKicked).
-			First(&kickedMember).Error; err == nil {
-			return fmt.Errorf("KICKED:この部屋から退出させられたため、再度参加することはできません")
+		if !room.IsActive || room.IsClosed {
+			return fmt.Errorf("ルームに参加できません")
 		}
 
-		if !room.CanJoin() {
-			return fmt.Errorf("ルームに参加できません")
+		// 満員、または空き席がキャンセル待ちの他のユーザーに確保されている場合は参加できない
+		reserved, err := reservedSeatCount(r.db, tx, roomID, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		if room.CurrentPlayers+reserved >= room.MaxPlayers {
+			return fmt.Errorf("ROOM_FULL:満員のため参加できません。キャンセル待ちに登録できます")
 		}
 
-		if !room.CheckPassword(password) {
+		var invite *models.RoomInvite
+		switch {
+		case inviteToken != "":
+			invite, err = useInvite(tx, roomID, inviteToken, time.Now())
+			if err != nil {
+				return err
+			}
+		case joinRequestID != uuid.Nil:
+			if err := approveJoinRequest(tx, roomID, userID, joinRequestID, time.Now()); err != nil {
+				return err
+			}
+		case applying:
+			// 既にメンバーなら参加申請は作らない
+			var member models.RoomMember
+			if err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusActive).
+				First(&member).Error; err == nil {
+				return fmt.Errorf("ALREADY_JOINED:既にルームに参加しています")
+			}
+			return fmt.Errorf("APPROVAL_REQUIRED:この部屋への参加にはホストの承認が必要です")
+		case !room.CheckPassword(password):
 			return fmt.Errorf("パスワードが間違っています")
 		}
 
@@ -386,15 +584,20 @@ func (r *roomRepository) JoinRoom(roomID, userID uuid.UUID, password string) err
 			return fmt.Errorf("ALREADY_JOINED:既にルームに参加しています")
 		}
 
-		// 既存の退室済みメンバーがいるかチェック
+		// 既存の退室済みメンバー（キックの期限切れを含む）がいるかチェック
 		var leftMember models.RoomMember
-		if err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, "left").
+		if err := tx.Where("room_id = ? AND user_id = ? AND status IN ?", roomID, userID,
+			[]string{models.MemberStatusLeft, models.MemberStatusKicked}).
 			First(&leftMember).Error; err == nil {
-			// 既存の退室済みレコードを再アクティブ化
+			// 既存の退室済みレコードを再アクティブ化（装備は参加のたびに申告し直す）
 			if err := tx.Model(&leftMember).Updates(map[string]interface{}{
-				"status":    "active",
-				"joined_at": time.Now(),
-				"left_at":   nil,
+				"status":       "active",
+				"joined_at":    time.Now(),
+				"left_at":      nil,
+				"weapon_type":  nil,
+				"hunter_rank":  nil,
+				"kick_reason":  nil,
+				"kicked_until": nil,
 			}).Error; err != nil {
 				return err
 			}
@@ -425,6 +628,13 @@ func (r *roomRepository) JoinRoom(roomID, userID uuid.UUID, password string) err
 			return err
 		}
 
+		// キャンセル待ちから参加した場合は登録を参加済みにする
+		if err := tx.Model(&models.RoomWaitlistEntry{}).
+			Where("room_id = ? AND user_id = ? AND status IN ?", roomID, userID, activeWaitlistStatuses).
+			Update("status", models.WaitlistStatusJoined).Error; err != nil {
+			return err
+		}
+
 		// 入室ログを記録
 		log := models.RoomLog{
 			RoomID: roomID,
@@ -436,29 +646,168 @@ func (r *roomRepository) JoinRoom(roomID, userID uuid.UUID, password string) err
 				},
 			},
 		}
-		return tx.Create(&log).Error
+		if err := tx.Create(&log).Error; err != nil {
+			return err
+		}
+
+		if joinRequestID != uuid.Nil {
+			return tx.Create(&models.RoomLog{
+				RoomID: roomID,
+				UserID: &room.HostUserID,
+				Action: "approve_join",
+				Details: models.JSONB{
+					Data: map[string]interface{}{
+						"user_name":       user.DisplayName,
+						"target_user_id":  userID,
+						"join_request_id": joinRequestID,
+					},
+				},
+			}).Error
+		}
+		if invite == nil {
+			return nil
+		}
+		inviteLog := models.RoomLog{
+			RoomID: roomID,
+			UserID: &userID,
+			Action: "use_invite",
+			Details: models.JSONB{
+				Data: map[string]interface{}{
+					"user_name": user.DisplayName,
+					"invite_id": invite.ID,
+					"use_count": invite.UseCount,
+					"max_uses":  invite.MaxUses,
+				},
+			},
+		}
+		return tx.Create(&inviteLog).Error
 	})
 }
 
 func (r *roomRepository) LeaveRoom(roomID, userID uuid.UUID) error {
-	return r.removeMember(roomID, userID, models.MemberStatusLeft, "leave")
+	return r.removeMember(roomID, userID, models.MemberStatusLeft, "leave", nil)
+}
+
+// KickMember ホストがメンバーを退出させる。status を kicked にするため、until まで（nil なら無期限）同じ部屋には再参加できなくなる
+func (r *roomRepository) KickMember(roomID, userID uuid.UUID, reason *string, until *time.Time) error {
+	return r.removeMember(roomID, userID, models.MemberStatusKicked, "kick", map[string]interface{}{
+		"kick_reason":  reason,
+		"kicked_until": until,
+	})
+}
+
+// TransferHost ホストを同じ部屋に参加中のメンバー toUserID に譲る
+func (r *roomRepository) TransferHost(roomID, fromUserID, toUserID uuid.UUID) error {
+	return r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		if err := tx.Where("id = ?", roomID).First(&room).Error; err != nil {
+			return err
+		}
+		if !room.IsActive {
+			return fmt.Errorf("この部屋は利用できません")
+		}
+		if room.HostUserID != fromUserID {
+			return fmt.Errorf("部屋のホストのみがホストを譲れます")
+		}
+		if fromUserID == toUserID {
+			return fmt.Errorf("自分自身にホストを譲ることはできません")
+		}
+
+		var member models.RoomMember
+		if err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, toUserID, models.MemberStatusActive).
+			First(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("ホストを譲る相手が部屋に参加していません")
+			}
+			return err
+		}
+
+		return changeHost(tx, &room, toUserID, models.HostChangeReasonTransfer)
+	})
 }
 
-// KickMember ホストがメンバーを退出させる。status を kicked にするため、同じ部屋には再参加できなくなる
-func (r *roomRepository) KickMember(roomID, userID uuid.UUID) error {
-	return r.removeMember(roomID, userID, models.MemberStatusKicked, "kick")
+// changeHost 部屋のホストを newHostID に変更し、メンバーのホストフラグを付け替えて transfer_host のログを残す
+func changeHost(tx *gorm.DB, room *models.Room, newHostID uuid.UUID, reason string) error {
+	previousHostID := room.HostUserID
+
+	if err := tx.Model(&models.Room{}).
+		Where("id = ?", room.ID).
+		Update("host_user_id", newHostID).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.RoomMember{}).
+		Where("room_id = ? AND is_host = ?", room.ID, true).
+		Update("is_host", false).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.RoomMember{}).
+		Where("room_id = ? AND user_id = ? AND status = ?", room.ID, newHostID, models.MemberStatusActive).
+		Update("is_host", true).Error; err != nil {
+		return err
+	}
+	room.HostUserID = newHostID
+
+	var users []models.User
+	if err := tx.Select("id", "display_name").Where("id IN ?", []uuid.UUID{previousHostID, newHostID}).Find(&users).Error; err != nil {
+		return err
+	}
+	names := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.DisplayName
+	}
+
+	log := models.RoomLog{
+		RoomID: room.ID,
+		UserID: &previousHostID,
+		Action: "transfer_host",
+		Details: models.JSONB{
+			Data: map[string]interface{}{
+				"user_name":          names[newHostID],
+				"previous_host_name": names[previousHostID],
+				"new_host_user_id":   newHostID,
+				"reason":             reason,
+			},
+		},
+	}
+	return tx.Create(&log).Error
 }
 
-// removeMember アクティブなメンバーを status に変更して部屋から外し、人数を減らして action のログを残す
-func (r *roomRepository) removeMember(roomID, userID uuid.UUID, status, action string) error {
+// removeMember アクティブなメンバーを status に変更して部屋から外し、人数を減らして action のログを残す。
+// extra は status と同時に更新する追加のカラム（キックの理由・期限など）。
+// 外れるのがホストなら、参加期間が最も長いメンバーにホストを引き継ぐ（他にメンバーがいなければ外せない）
+func (r *roomRepository) removeMember(roomID, userID uuid.UUID, status, action string, extra map[string]interface{}) error {
 	return r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		if err := tx.Where("id = ?", roomID).First(&room).Error; err != nil {
+			return err
+		}
+
+		var successor *models.RoomMember
+		if room.HostUserID == userID && room.IsActive {
+			var candidates []models.RoomMember
+			if err := tx.Where("room_id = ? AND user_id <> ? AND status = ?", roomID, userID, models.MemberStatusActive).
+				Order("joined_at ASC, player_number ASC").
+				Limit(1).
+				Find(&candidates).Error; err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return fmt.Errorf("HOST_ALONE:他に参加しているメンバーがいないため退出できません。部屋を解散してください")
+			}
+			successor = &candidates[0]
+		}
+
 		now := time.Now()
+		updates := map[string]interface{}{
+			"status":  status,
+			"left_at": &now,
+		}
+		for column, value := range extra {
+			updates[column] = value
+		}
 		result := tx.Model(&models.RoomMember{}).
 			Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusActive).
-			Updates(map[string]interface{}{
-				"status":  status,
-				"left_at": &now,
-			})
+			Updates(updates)
 
 		if result.Error != nil {
 			return result.Error
@@ -493,7 +842,14 @@ func (r *roomRepository) removeMember(roomID, userID uuid.UUID, status, action s
 				},
 			},
 		}
-		return tx.Create(&log).Error
+		if err := tx.Create(&log).Error; err != nil {
+			return err
+		}
+
+		if successor != nil {
+			return changeHost(tx, &room, successor.UserID, models.HostChangeReasonSuccession)
+		}
+		return nil
 	})
 }
 
@@ -534,6 +890,65 @@ func (r *roomRepository) IsUserJoinedRoom(roomID, userID uuid.UUID) bool {
 	return err == nil
 }
 
+// IsUserKickedFromRoom userID が部屋からキックされていて、まだ再参加できないかどうか
+func (r *roomRepository) IsUserKickedFromRoom(roomID, userID uuid.UUID) bool {
+	member, err := findActiveKick(r.db.GetConn(), roomID, userID, time.Now())
+	return err == nil && member != nil
+}
+
+// HasParticipatedInRoom userID が部屋に参加中、または参加したことがあるか（キックされた場合を除く）
+func (r *roomRepository) HasParticipatedInRoom(roomID, userID uuid.UUID) bool {
+	var count int64
+	err := r.db.GetConn().Model(&models.RoomMember{}).
+		Where("room_id = ? AND user_id = ? AND status IN ?", roomID, userID,
+			[]string{models.MemberStatusActive, models.MemberStatusLeft}).
+		Count(&count).Error
+	return err == nil && count > 0
+}
+
+// findActiveKick now の時点で有効なキックの記録を返す。キックされていない・期限切れなら nil
+func findActiveKick(tx *gorm.DB, roomID, userID uuid.UUID, now time.Time) (*models.RoomMember, error) {
+	var member models.RoomMember
+	err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusKicked).
+		First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("部屋メンバー検索エラー: %w", err)
+	}
+	if !member.IsKickedAt(now) {
+		return nil, nil
+	}
+	return &member, nil
+}
+
+// jst 参加制限のメッセージに表示する日時のタイムゾーン
+var jst = time.FixedZone("JST", 9*60*60)
+
+// checkJoinRestrictions キックとホストのBANリストによる参加制限を確認する（参加・参加申請・キャンセル待ちで共通）
+func checkJoinRestrictions(tx *gorm.DB, room *models.Room, userID uuid.UUID, now time.Time) error {
+	kicked, err := findActiveKick(tx, room.ID, userID, now)
+	if err != nil {
+		return err
+	}
+	if kicked != nil {
+		if kicked.KickedUntil != nil {
+			return fmt.Errorf("KICKED:この部屋から退出させられたため、%s まで参加できません", kicked.KickedUntil.In(jst).Format("1/2 15:04"))
+		}
+		return fmt.Errorf("KICKED:この部屋から退出させられたため、再度参加することはできません")
+	}
+
+	banned, err := isHostBanned(tx, room.HostUserID, userID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return fmt.Errorf("HOST_BANNED:このホストの部屋には参加できません")
+	}
+	return nil
+}
+
 func (r *roomRepository) GetRoomMembers(roomID uuid.UUID) ([]models.RoomMember, error) {
 	var members []models.RoomMember
 	err := r.db.GetConn().
@@ -567,6 +982,48 @@ func (r *roomRepository) GetRoomMembers(roomID uuid.UUID) ([]models.RoomMember,
 	return members, nil
 }
 
+// UpdateMemberLoadout 参加中のメンバーが申告する武器種とハンターランクを更新する（nil は未申告に戻す）
+func (r *roomRepository) UpdateMemberLoadout(roomID, userID uuid.UUID, weaponType *string, hunterRank *int) error {
+	result := r.db.GetConn().
+		Model(&models.RoomMember{}).
+		Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusActive).
+		Updates(map[string]interface{}{
+			"weapon_type": weaponType,
+			"hunter_rank": hunterRank,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("この部屋に参加していません")
+	}
+	return nil
+}
+
+// GetActiveMemberWeapons 部屋ごとに、参加中のメンバーが申告している武器種を取得する（部屋一覧の武器枠の表示用）
+func (r *roomRepository) GetActiveMemberWeapons(roomIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	weapons := make(map[uuid.UUID][]string)
+	if len(roomIDs) == 0 {
+		return weapons, nil
+	}
+
+	var rows []struct {
+		RoomID     uuid.UUID
+		WeaponType string
+	}
+	if err := r.db.GetConn().
+		Model(&models.RoomMember{}).
+		Select("room_id", "weapon_type").
+		Where("room_id IN ? AND status = ? AND weapon_type IS NOT NULL", roomIDs, models.MemberStatusActive).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		weapons[row.RoomID] = append(weapons[row.RoomID], row.WeaponType)
+	}
+	return weapons, nil
+}
+
 func (r *roomRepository) GetRoomLogs(roomID uuid.UUID) ([]models.RoomLog, error) {
 	var logs []models.RoomLog
 	err := r.db.GetConn().
@@ -600,8 +1057,15 @@ func (r *roomRepository) UpdateRoom(room *models.Room) error {
 					"game_version_id":      room.GameVersionID,
 					"has_description":      room.Description != nil,
 					"has_target_monster":   room.TargetMonster != nil,
+					"monster_id":           room.MonsterID,
+					"quest_id":             room.QuestID,
 					"has_rank_requirement": room.RankRequirement != nil,
 					"has_password":         room.PasswordHash != nil,
+					"scheduled_start_at":   room.ScheduledStartAt,
+					"visibility":           room.GetVisibility(),
+					"requires_approval":    room.RequiresApproval,
+					"wanted_weapons":       room.GetWantedWeapons(),
+					"slow_mode_seconds":    room.SlowModeSeconds,
 				},
 			},
 		}
@@ -630,6 +1094,38 @@ func (r *roomRepository) DismissRoom(roomID uuid.UUID, reason string) error {
 			return err
 		}
 
+		// キャンセル待ちもすべて取り消す
+		if err := tx.Model(&models.RoomWaitlistEntry{}).
+			Where("room_id = ? AND status IN ?", roomID, activeWaitlistStatuses).
+			Update("status", models.WaitlistStatusCancelled).Error; err != nil {
+			return err
+		}
+
+		// 審査待ちの参加申請も取り消す
+		if err := tx.Model(&models.RoomJoinRequest{}).
+			Where("room_id = ? AND status = ?", roomID, models.JoinRequestStatusPending).
+			Updates(map[string]interface{}{
+				"status":     models.JoinRequestStatusCancelled,
+				"decided_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		// 部屋が消える前にセッションのまとめを残す（再度解散された場合は作り直さない）
+		var summaryCount int64
+		if err := tx.Model(&models.RoomSessionSummary{}).Where("room_id = ?", roomID).Count(&summaryCount).Error; err != nil {
+			return err
+		}
+		if summaryCount == 0 {
+			summary, err := buildSessionSummary(tx, &room, now)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(summary).Error; err != nil {
+				return err
+			}
+		}
+
 		// 部屋を非アクティブに変更
 		if err := tx.Model(&room).Updates(map[string]interface{}{
 			"is_active":       false,
@@ -665,19 +1161,14 @@ func (r *roomRepository) DismissRoom(roomID uuid.UUID, reason string) error {
 
 // FindInactiveRooms idleSince 以降に活動（作成・設定変更・参加・退出・チャット）が一度もない募集中の部屋を取得
 func (r *roomRepository) FindInactiveRooms(idleSince time.Time) ([]models.Room, error) {
-	// libSQL(SQLite) は日時を文字列として比較するため、タイムゾーン表記（+09:00 / +00:00）が
-	// 混在すると誤判定する。datetime() で UTC に正規化してから比較する（Postgres は素の比較で正しい）
-	ts := func(column string) string { return column }
-	param := "?"
-	if r.db.GetType() == "turso" {
-		ts = func(column string) string { return "datetime(" + column + ")" }
-		param = "datetime(?)"
-	}
+	ts, param := timeComparison(r.db)
 
 	var rooms []models.Room
 	err := r.db.GetConn().
 		Where("is_active = ?", true).
 		Where(ts("rooms.created_at")+" < "+param+" AND "+ts("rooms.updated_at")+" < "+param, idleSince, idleSince).
+		// 開始予定時刻も活動として扱う（開始前の部屋は解散せず、開始後は開始時刻から放置時間を数える）
+		Where("(rooms.scheduled_start_at IS NULL OR "+ts("rooms.scheduled_start_at")+" < "+param+")", idleSince).
 		Where("NOT EXISTS (SELECT 1 FROM room_messages rm WHERE rm.room_id = rooms.id AND "+ts("rm.created_at")+" >= "+param+")", idleSince).
 		Where("NOT EXISTS (SELECT 1 FROM room_logs rl WHERE rl.room_id = rooms.id AND "+ts("rl.created_at")+" >= "+param+")", idleSince).
 		Order("rooms.created_at ASC").
@@ -689,6 +1180,39 @@ func (r *roomRepository) FindInactiveRooms(idleSince time.Time) ([]models.Room,
 	return rooms, nil
 }
 
+// FindRoomsStartingBefore 開始予定時刻が now より後かつ until 以前で、開始前のお知らせをまだ送っていない部屋を取得
+func (r *roomRepository) FindRoomsStartingBefore(now, until time.Time) ([]models.Room, error) {
+	ts, param := timeComparison(r.db)
+
+	var rooms []models.Room
+	err := r.db.GetConn().
+		Where("is_active = ?", true).
+		Where("start_reminder_sent_at IS NULL").
+		Where("scheduled_start_at IS NOT NULL").
+		Where(ts("scheduled_start_at")+" > "+param+" AND "+ts("scheduled_start_at")+" <= "+param, now, until).
+		Order("scheduled_start_at ASC").
+		Find(&rooms).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+// MarkStartReminderSent 開始前のお知らせを送信済みにする
+func (r *roomRepository) MarkStartReminderSent(roomID uuid.UUID) error {
+	return r.db.GetConn().
+		Model(&models.Room{}).
+		Where("id = ?", roomID).
+		UpdateColumn("start_reminder_sent_at", time.Now()).Error
+}
+
+// upcomingCondition 開始予定時刻が now より後の「開始前」の部屋を判定する SQL 条件とパラメータを返す
+func (r *roomRepository) upcomingCondition(now time.Time) (string, []interface{}) {
+	ts, param := timeComparison(r.db)
+	return "rooms.scheduled_start_at IS NOT NULL AND " + ts("rooms.scheduled_start_at") + " > " + param, []interface{}{now}
+}
+
 // GetUserRoomStatus ユーザーの部屋状態を取得
 func (r *roomRepository) GetUserRoomStatus(userID uuid.UUID) (string, *models.Room, error) {
 	// 1. ホストとして部屋を持っているかチェック
@@ -736,8 +1260,8 @@ func (r *roomRepository) GetUserRoomStatus(userID uuid.UUID) (string, *models.Ro
 	return "NONE", nil, nil
 }
 
-// GetRoomsByHostUser ホストユーザーが作成した部屋一覧を取得
-func (r *roomRepository) GetRoomsByHostUser(userID uuid.UUID, limit, offset int) ([]models.Room, error) {
+// GetRoomsByHostUser ホストユーザーが作成した部屋一覧を取得。ホスト本人以外には閲覧者に公開されている部屋だけを返す
+func (r *roomRepository) GetRoomsByHostUser(userID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]models.Room, error) {
 	// 最適化されたクエリ: JOINを使用してN+1問題を解決
 	var results []struct {
 		models.Room
@@ -748,6 +1272,7 @@ func (r *roomRepository) GetRoomsByHostUser(userID uuid.UUID, limit, offset int)
 		CurrentPlayers  int     `json:"current_players"`
 	}
 
+	visibility, visibilityArgs := listedRoomCondition(viewerID)
 	query := `
 		SELECT
 			rooms.*,
@@ -760,13 +1285,15 @@ func (r *roomRepository) GetRoomsByHostUser(userID uuid.UUID, limit, offset int)
 		LEFT JOIN game_versions gv ON rooms.game_version_id = gv.id
 		LEFT JOIN users u ON rooms.host_user_id = u.id
 		LEFT JOIN room_members rm ON rooms.id = rm.room_id AND rm.status = 'active'
-		WHERE rooms.host_user_id = ?
+		WHERE rooms.host_user_id = ?` + visibility + `
 		GROUP BY rooms.id, gv.id, u.id
 		ORDER BY rooms.created_at DESC
 		LIMIT ? OFFSET ?
 	`
 
-	if err := r.db.GetConn().Raw(query, userID, limit, offset).Scan(&results).Error; err != nil {
+	args := append([]interface{}{userID}, visibilityArgs...)
+	args = append(args, limit, offset)
+	if err := r.db.GetConn().Raw(query, args...).Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
@@ -796,13 +1323,13 @@ func (r *roomRepository) GetRoomsByHostUser(userID uuid.UUID, limit, offset int)
 	return rooms, nil
 }
 
-// CountRoomsByHostUser ホストユーザーが作成した部屋の総数を取得
-func (r *roomRepository) CountRoomsByHostUser(userID uuid.UUID) (int64, error) {
+// CountRoomsByHostUser ホストユーザーが作成した部屋のうち、閲覧者に公開されている部屋の総数を取得
+func (r *roomRepository) CountRoomsByHostUser(userID uuid.UUID, viewerID *uuid.UUID) (int64, error) {
+	visibility, args := listedRoomCondition(viewerID)
 	var count int64
 	if err := r.db.GetConn().
-		Model(&models.Room{}).
-		Where("host_user_id = ?", userID).
-		Count(&count).Error; err != nil {
+		Raw("SELECT COUNT(*) FROM rooms WHERE rooms.host_user_id = ?"+visibility, append([]interface{}{userID}, args...)...).
+		Scan(&count).Error; err != nil {
 		return 0, err
 	}
 
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestScheduledRoomQueries(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomMessage{}, &models.RoomLog{})
+	now := time.Now().UTC()
+	old := now.Add(-72 * time.Hour)
+
+	host := createTestUser(t, repo, "ホスト")
+
+	newRoom := func(code string, start *time.Time) *models.Room {
+		room := &models.Room{
+			BaseModel:        models.BaseModel{ID: uuid.New(), CreatedAt: old, UpdatedAt: old},
+			RoomCode:         code,
+			Name:             code,
+			GameVersionID:    uuid.New(),
+			HostUserID:       host.ID,
+			MaxPlayers:       4,
+			IsActive:         true,
+			ScheduledStartAt: start,
+		}
+		if err := db.Create(room).Error; err != nil {
+			t.Fatal(err)
+		}
+		return room
+	}
+	soon, later, started := now.Add(30*time.Minute), now.Add(5*time.Hour), now.Add(-time.Hour)
+	live := newRoom("LIVE", nil)
+	startingSoon := newRoom("SOON", &soon)
+	startingLater := newRoom("LATER", &later)
+	justStarted := newRoom("STARTED", &started)
+
+	// 開始前の部屋と、開始から放置時間が経っていない部屋は自動解散の対象外
+	inactive, err := repo.Room.FindInactiveRooms(now.Add(-48 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inactive) != 1 || inactive[0].ID != live.ID {
+		t.Fatalf("自動解散対象 = %#v, want 募集中の放置部屋のみ", inactive)
+	}
+	inactive, err = repo.Room.FindInactiveRooms(now.Add(-30 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inactive) != 2 || inactive[0].ID == startingSoon.ID || inactive[1].ID == startingSoon.ID {
+		t.Fatalf("開始後の放置部屋 = %#v, want LIVE と STARTED", inactive)
+	}
+
+	// 開始1時間前までの部屋だけがお知らせ対象で、送信済みにすると再度は対象にならない
+	reminders, err := repo.Room.FindRoomsStartingBefore(now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reminders) != 1 || reminders[0].ID != startingSoon.ID {
+		t.Fatalf("お知らせ対象 = %#v, want SOON のみ", reminders)
+	}
+	if err := repo.Room.MarkStartReminderSent(startingSoon.ID); err != nil {
+		t.Fatal(err)
+	}
+	reminders, err = repo.Room.FindRoomsStartingBefore(now, now.Add(time.Hour))
+	if err != nil || len(reminders) != 0 {
+		t.Fatalf("送信済み後のお知らせ対象 = %#v, err=%v", reminders, err)
+	}
+
+	// 一覧は募集中の部屋が先、開始前の部屋は開始が近い順で後ろに並ぶ
+	rooms, err := repo.Room.GetActiveRooms(RoomSearchParams{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 4 {
+		t.Fatalf("部屋数 = %d, want 4", len(rooms))
+	}
+	if rooms[2].ID != startingSoon.ID || rooms[3].ID != startingLater.ID {
+		t.Fatalf("開始前の部屋の並び = %s, %s, want SOON, LATER", rooms[2].RoomCode, rooms[3].RoomCode)
+	}
+	for _, room := range rooms[:2] {
+		if room.ID != live.ID && room.ID != justStarted.ID {
+			t.Fatalf("募集中の部屋が先頭に来ていない: %s", room.RoomCode)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomSearch(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.UserFollow{})
+	now := time.Now().UTC()
+
+	host := createTestUser(t, repo, "ホスト")
+	mhp2g, mhp3 := uuid.New(), uuid.New()
+
+	str := func(s string) *string { return &s }
+	newRoom := func(code string, gameVersionID uuid.UUID, age time.Duration, monster, rank, password *string, members int) *models.Room {
+		room := &models.Room{
+			BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: now.Add(-age), UpdatedAt: now.Add(-age)},
+			RoomCode:        code,
+			Name:            code + "の部屋",
+			Description:     str("まったり" + code),
+			GameVersionID:   gameVersionID,
+			HostUserID:      host.ID,
+			MaxPlayers:      4,
+			IsActive:        true,
+			TargetMonster:   monster,
+			RankRequirement: rank,
+			PasswordHash:    password,
+		}
+		if err := db.Create(room).Error; err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < members; i++ {
+			member := &models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: uuid.New(), PlayerNumber: i + 1, IsHost: i == 0, Status: models.MemberStatusActive}
+			if err := db.Create(member).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		return room
+	}
+	tigrex := newRoom("TIGREX", mhp2g, time.Hour, str("ティガレックス"), str("HR6以上_G級"), nil, 1)
+	full := newRoom("FULL", mhp2g, 2*time.Hour, str("ナルガクルガ"), nil, nil, 4)
+	locked := newRoom("LOCKED", mhp3, 3*time.Hour, str("ジンオウガ"), str("HR3以上"), str("hash"), 2)
+
+	// 狩猟笛の枠は LOCKED ではメンバーが担当済み、FULL は満員のため空いているのは TIGREX のみ
+	for room, weapons := range map[*models.Room][]string{
+		tigrex: {models.WeaponHuntingHorn, models.WeaponBow},
+		full:   {models.WeaponHuntingHorn},
+		locked: {models.WeaponHuntingHorn},
+	} {
+		room.SetWantedWeapons(weapons)
+		if err := db.Model(room).Update("wanted_weapons", room.WantedWeapons).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Model(&models.RoomMember{}).Where("room_id = ? AND player_number = ?", locked.ID, 2).
+		Update("weapon_type", models.WeaponHuntingHorn).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	codes := func(rooms []models.Room) []string {
+		var got []string
+		for _, room := range rooms {
+			got = append(got, room.RoomCode)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name   string
+		params RoomSearchParams
+		want   []string
+	}{
+		{"条件なしは新着順", RoomSearchParams{}, []string{tigrex.RoomCode, full.RoomCode, locked.RoomCode}},
+		{"ゲームバージョン", RoomSearchParams{GameVersionID: &mhp3}, []string{locked.RoomCode}},
+		{"モンスターの部分一致", RoomSearchParams{TargetMonster: "ティガ"}, []string{tigrex.RoomCode}},
+		{"ランク条件", RoomSearchParams{RankRequirement: "hr3"}, []string{locked.RoomCode}},
+		{"ランク条件の _ は文字どおり", RoomSearchParams{RankRequirement: "上_g"}, []string{tigrex.RoomCode}},
+		{"ランク条件の % は文字どおり", RoomSearchParams{RankRequirement: "以上%"}, nil},
+		{"パスワードあり", RoomSearchParams{Password: RoomPasswordWith}, []string{locked.RoomCode}},
+		{"パスワードなし", RoomSearchParams{Password: RoomPasswordWithout}, []string{tigrex.RoomCode, full.RoomCode}},
+		{"空きのある部屋のみ", RoomSearchParams{HasVacancy: true}, []string{tigrex.RoomCode, locked.RoomCode}},
+		{"フリーワード（説明）", RoomSearchParams{Query: "まったりfull"}, []string{full.RoomCode}},
+		{"フリーワードの % は文字どおり", RoomSearchParams{Query: "%"}, nil},
+		{"フリーワードの _ は文字どおり", RoomSearchParams{Query: "_"}, nil},
+		{`フリーワードの \ は文字どおり`, RoomSearchParams{Query: `の部屋\`}, nil},
+		{"参加人数が多い順", RoomSearchParams{Sort: RoomSortPlayers}, []string{full.RoomCode, locked.RoomCode, tigrex.RoomCode}},
+		{"空きが多い順", RoomSearchParams{Sort: RoomSortVacancy}, []string{tigrex.RoomCode, locked.RoomCode, full.RoomCode}},
+		{"ページング", RoomSearchParams{Limit: 1, Offset: 1}, []string{full.RoomCode}},
+		{"狩猟笛の空き枠", RoomSearchParams{OpenWeapon: models.WeaponHuntingHorn}, []string{tigrex.RoomCode}},
+		{"募集していない武器種", RoomSearchParams{OpenWeapon: models.WeaponLance}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rooms, err := repo.Room.GetActiveRooms(tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := codes(rooms); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("GetActiveRooms = %v, want %v", got, tt.want)
+			}
+
+			withStatus, err := repo.Room.GetActiveRoomsWithJoinStatus(&host.ID, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(withStatus) != len(tt.want) {
+				t.Errorf("GetActiveRoomsWithJoinStatus = %d 件, want %d 件", len(withStatus), len(tt.want))
+			}
+
+			if tt.params.Limit == 0 {
+				count, err := repo.Room.CountActiveRooms(tt.params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if count != int64(len(tt.want)) {
+					t.Errorf("CountActiveRooms = %d, want %d", count, len(tt.want))
+				}
+			}
+		})
+	}
+
+	listed, err := repo.Room.GetActiveRooms(RoomSearchParams{OpenWeapon: models.WeaponBow})
+	if err != nil || len(listed) != 1 || fmt.Sprint(listed[0].GetWantedWeapons()) != fmt.Sprint([]string{models.WeaponHuntingHorn, models.WeaponBow}) {
+		t.Fatalf("一覧の募集武器種 = %+v, %v", listed, err)
+	}
+
+	weapons, err := repo.Room.GetActiveMemberWeapons([]uuid.UUID{tigrex.ID, locked.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slots := locked.WeaponSlots(weapons[locked.ID])
+	if len(slots) != 1 || !slots[0].Filled || slots[0].Name != "狩猟笛" {
+		t.Errorf("LOCKED の武器枠 = %+v", slots)
+	}
+	if slots := tigrex.WeaponSlots(weapons[tigrex.ID]); len(slots) != 2 || slots[0].Filled || slots[1].Filled {
+		t.Errorf("TIGREX の武器枠 = %+v", slots)
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// roomSessionSummaryRepository は解散した部屋のセッションのまとめを扱うリポジトリの実装
+type roomSessionSummaryRepository struct {
+	db DBInterface
+}
+
+// NewRoomSessionSummaryRepository は新しいRoomSessionSummaryRepositoryインスタンスを作成
+func NewRoomSessionSummaryRepository(db DBInterface) RoomSessionSummaryRepository {
+	return &roomSessionSummaryRepository{db: db}
+}
+
+// FindByRoomID 部屋のセッションのまとめを取得する
+func (r *roomSessionSummaryRepository) FindByRoomID(roomID uuid.UUID) (*models.RoomSessionSummary, error) {
+	var summary models.RoomSessionSummary
+	if err := r.db.GetConn().Where("room_id = ?", roomID).First(&summary).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// FindByRoomIDs 複数の部屋のセッションのまとめを部屋IDごとに取得する（まとめのない部屋は含まない）
+func (r *roomSessionSummaryRepository) FindByRoomIDs(roomIDs []uuid.UUID) (map[uuid.UUID]*models.RoomSessionSummary, error) {
+	result := make(map[uuid.UUID]*models.RoomSessionSummary, len(roomIDs))
+	if len(roomIDs) == 0 {
+		return result, nil
+	}
+
+	var summaries []models.RoomSessionSummary
+	if err := r.db.GetConn().Where("room_id IN ?", roomIDs).Find(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("セッションのまとめの取得に失敗しました: %w", err)
+	}
+	for i := range summaries {
+		result[summaries[i].RoomID] = &summaries[i]
+	}
+	return result, nil
+}
+
+// buildSessionSummary 解散処理のトランザクション内で、部屋のログ・メンバー・メッセージからセッションのまとめを作る
+func buildSessionSummary(tx *gorm.DB, room *models.Room, endedAt time.Time) (*models.RoomSessionSummary, error) {
+	// 開始予定時刻のある部屋は、作成から開始までの待ち時間をセッションに含めない
+	startedAt := room.CreatedAt
+	if room.ScheduledStartAt != nil && room.ScheduledStartAt.After(startedAt) && room.ScheduledStartAt.Before(endedAt) {
+		startedAt = *room.ScheduledStartAt
+	}
+	duration := endedAt.Sub(startedAt)
+	if duration < 0 {
+		duration = 0
+	}
+
+	var members []models.RoomMember
+	if err := tx.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "display_name", "username")
+	}).Where("room_id = ?", room.ID).Order("joined_at ASC").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("部屋メンバー検索エラー: %w", err)
+	}
+	participants := make([]models.SessionParticipant, 0, len(members))
+	for _, member := range members {
+		name := member.User.DisplayName
+		if name == "" && member.User.Username != nil {
+			name = *member.User.Username
+		}
+		participants = append(participants, models.SessionParticipant{
+			UserID:      member.UserID,
+			DisplayName: name,
+			IsHost:      member.UserID == room.HostUserID,
+			Kicked:      member.Status == models.MemberStatusKicked,
+		})
+	}
+
+	var logs []models.RoomLog
+	if err := tx.Select("action", "created_at").
+		Where("room_id = ? AND action IN ?", room.ID, []string{"join", "leave", "kick"}).
+		Order("created_at ASC").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("部屋ログ検索エラー: %w", err)
+	}
+
+	var messageCount int64
+	if err := tx.Model(&models.RoomMessage{}).
+		Where("room_id = ? AND message_type <> ? AND is_deleted = ?", room.ID, "system", false).
+		Count(&messageCount).Error; err != nil {
+		return nil, fmt.Errorf("メッセージ件数の取得に失敗しました: %w", err)
+	}
+
+	return &models.RoomSessionSummary{
+		RoomID:          room.ID,
+		HostUserID:      room.HostUserID,
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int64(duration / time.Second),
+		PeakPlayers:     peakPlayers(logs),
+		MessageCount:    int(messageCount),
+		Participants:    models.JSONB{Data: participants},
+	}, nil
+}
+
+// peakPlayers 入退室のログを順にたどって、同時に参加していた人数の最大を求める（作成時はホスト1人）
+func peakPlayers(logs []models.RoomLog) int {
+	current, peak := 1, 1
+	for _, l := range logs {
+		switch l.Action {
+		case "join":
+			current++
+		case "leave", "kick":
+			current--
+		}
+		if current > peak {
+			peak = current
+		}
+	}
+	return peak
+}
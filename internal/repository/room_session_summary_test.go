@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestDismissRoomCreatesSessionSummary(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomMessage{}, &models.RoomWaitlistEntry{}, &models.RoomJoinRequest{}, &models.HostBan{}, &models.RoomSessionSummary{})
+
+	host, guest1, guest2, guest3 := createTestUser(t, repo, "ホスト"), createTestUser(t, repo, "参加者1"), createTestUser(t, repo, "参加者2"), createTestUser(t, repo, "参加者3")
+
+	room := &models.Room{
+		BaseModel:      models.BaseModel{ID: uuid.New(), CreatedAt: time.Now().Add(-2 * time.Hour)},
+		RoomCode:       "SESS01",
+		Name:           "まとめの部屋",
+		GameVersionID:  uuid.New(),
+		HostUserID:     host.ID,
+		MaxPlayers:     4,
+		CurrentPlayers: 1,
+		IsActive:       true,
+	}
+	if err := db.Create(room).Error; err != nil {
+		t.Fatal(err)
+	}
+	hostMember := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: host.ID, PlayerNumber: 1, IsHost: true, Status: models.MemberStatusActive, JoinedAt: room.CreatedAt}
+	if err := db.Create(&hostMember).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 最大3人（ホスト・参加者1・参加者2）→ 参加者1が抜けて参加者3が入り、参加者2はキック
+	steps := []func() error{
+		func() error { return repo.Room.JoinRoom(room.ID, guest1.ID, "") },
+		func() error { return repo.Room.JoinRoom(room.ID, guest2.ID, "") },
+		func() error { return repo.Room.LeaveRoom(room.ID, guest1.ID) },
+		func() error { return repo.Room.JoinRoom(room.ID, guest3.ID, "") },
+		func() error { return repo.Room.KickMember(room.ID, guest2.ID, nil, nil) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		// ログの順序が確定するよう時刻をずらす
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	messages := []models.RoomMessage{
+		{RoomID: room.ID, UserID: host.ID, Message: "よろしく", MessageType: "chat"},
+		{RoomID: room.ID, UserID: guest3.ID, Message: "お願いします", MessageType: "chat"},
+		{RoomID: room.ID, UserID: host.ID, Message: "参加者2さんはホストにより退出となりました", MessageType: "system"},
+		{RoomID: room.ID, UserID: guest2.ID, Message: "削除済み", MessageType: "chat", IsDeleted: true},
+	}
+	for i := range messages {
+		if err := db.Create(&messages[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := repo.Room.DismissRoom(room.ID, models.DismissReasonHost); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := repo.RoomSession.FindByRoomID(room.ID)
+	if err != nil {
+		t.Fatalf("FindByRoomID() error = %v", err)
+	}
+	if summary.HostUserID != host.ID {
+		t.Errorf("HostUserID = %v, want %v", summary.HostUserID, host.ID)
+	}
+	if summary.PeakPlayers != 3 {
+		t.Errorf("PeakPlayers = %d, want 3", summary.PeakPlayers)
+	}
+	if summary.MessageCount != 2 {
+		t.Errorf("MessageCount = %d, want 2（システムメッセージ・削除済みを除く）", summary.MessageCount)
+	}
+	if summary.Duration() < 2*time.Hour || summary.Duration() > 2*time.Hour+time.Minute {
+		t.Errorf("Duration() = %v, want 約2時間", summary.Duration())
+	}
+
+	participants := summary.GetParticipants()
+	if len(participants) != 4 {
+		t.Fatalf("参加者 = %d 人, want 4: %+v", len(participants), participants)
+	}
+	byID := make(map[uuid.UUID]models.SessionParticipant, len(participants))
+	for _, p := range participants {
+		byID[p.UserID] = p
+	}
+	if !byID[host.ID].IsHost || byID[guest1.ID].IsHost {
+		t.Errorf("ホストの判定が誤り: %+v", participants)
+	}
+	if !byID[guest2.ID].Kicked || byID[guest3.ID].Kicked {
+		t.Errorf("キックの判定が誤り: %+v", participants)
+	}
+	if byID[guest1.ID].DisplayName != "参加者1" {
+		t.Errorf("表示名 = %q, want 参加者1", byID[guest1.ID].DisplayName)
+	}
+	if !summary.HasParticipant(guest1.ID) || summary.HasParticipant(uuid.New()) {
+		t.Error("HasParticipant の判定が誤り")
+	}
+
+	// 一覧用の取得
+	summaries, err := repo.RoomSession.FindByRoomIDs([]uuid.UUID{room.ID, uuid.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[room.ID] == nil {
+		t.Errorf("FindByRoomIDs() = %v, want 1件", summaries)
+	}
+}
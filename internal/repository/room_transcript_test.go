@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomTranscriptQueries(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomMessage{}, &models.RoomMessageAttachment{})
+
+	username := "hunter_b"
+	alice := createTestUser(t, repo, "ハンターA")
+	// 表示名のないユーザーはユーザー名で表示する
+	bob := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, SupabaseUserID: uuid.New(), Email: "b@example.test", Username: &username, IsActive: true, Role: models.RoleUser}
+	if err := repo.User.CreateUser(bob); err != nil {
+		t.Fatal(err)
+	}
+
+	roomID := uuid.New()
+	base := time.Date(2026, 9, 1, 21, 0, 0, 0, time.UTC)
+
+	t.Run("メッセージを古い順にバッチで読み込む", func(t *testing.T) {
+		// 同じ時刻のメッセージがバッチの境目をまたいでも漏れ・重複がないこと
+		var want []uuid.UUID
+		for i := 0; i < 7; i++ {
+			m := &models.RoomMessage{
+				BaseModel:   models.BaseModel{CreatedAt: base.Add(time.Duration(i/2) * time.Minute)},
+				RoomID:      roomID,
+				UserID:      alice.ID,
+				Message:     "msg",
+				MessageType: models.RoomMessageTypeChat,
+			}
+			if err := repo.RoomMessage.CreateMessage(m); err != nil {
+				t.Fatal(err)
+			}
+			want = append(want, m.ID)
+		}
+		other := &models.RoomMessage{RoomID: uuid.New(), UserID: alice.ID, Message: "別の部屋", MessageType: models.RoomMessageTypeChat}
+		if err := repo.RoomMessage.CreateMessage(other); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []models.RoomMessage
+		batches := 0
+		err := repo.RoomMessage.EachMessageBatch(roomID, 3, func(messages []models.RoomMessage) error {
+			batches++
+			got = append(got, messages...)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("EachMessageBatch() error = %v", err)
+		}
+		if batches != 3 || len(got) != len(want) {
+			t.Fatalf("batches = %d, 件数 = %d, want 3, %d", batches, len(got), len(want))
+		}
+		seen := map[uuid.UUID]bool{}
+		for i, m := range got {
+			if seen[m.ID] {
+				t.Errorf("メッセージ %s が重複している", m.ID)
+			}
+			seen[m.ID] = true
+			if i > 0 && m.CreatedAt.Before(got[i-1].CreatedAt) {
+				t.Errorf("古い順になっていない: %v の後に %v", got[i-1].CreatedAt, m.CreatedAt)
+			}
+			if m.User.DisplayName != "ハンターA" {
+				t.Errorf("投稿者が読み込まれていない: %+v", m.User)
+			}
+		}
+		for _, id := range want {
+			if !seen[id] {
+				t.Errorf("メッセージ %s が読み込まれていない", id)
+			}
+		}
+	})
+
+	t.Run("参加したことがあるか", func(t *testing.T) {
+		kickedUser := uuid.New()
+		members := []models.RoomMember{
+			{RoomID: roomID, UserID: alice.ID, PlayerNumber: 1, Status: models.MemberStatusActive, JoinedAt: base},
+			{RoomID: roomID, UserID: bob.ID, PlayerNumber: 2, Status: models.MemberStatusLeft, JoinedAt: base},
+			{RoomID: roomID, UserID: kickedUser, PlayerNumber: 3, Status: models.MemberStatusKicked, JoinedAt: base},
+		}
+		if err := db.Create(&members).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name   string
+			userID uuid.UUID
+			want   bool
+		}{
+			{"参加中", alice.ID, true},
+			{"退出済み", bob.ID, true},
+			{"キックされた", kickedUser, false},
+			{"参加していない", uuid.New(), false},
+		}
+		for _, tt := range tests {
+			if got := repo.Room.HasParticipatedInRoom(roomID, tt.userID); got != tt.want {
+				t.Errorf("%s: HasParticipatedInRoom() = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("入退室のログを古い順に取得", func(t *testing.T) {
+		logs := []models.RoomLog{
+			{BaseModel: models.BaseModel{CreatedAt: base.Add(2 * time.Minute)}, RoomID: roomID, UserID: &bob.ID, Action: "leave"},
+			{BaseModel: models.BaseModel{CreatedAt: base}, RoomID: roomID, UserID: &bob.ID, Action: "join"},
+			{BaseModel: models.BaseModel{CreatedAt: base.Add(time.Minute)}, RoomID: roomID, UserID: &alice.ID, Action: "update_settings"},
+			{BaseModel: models.BaseModel{CreatedAt: base}, RoomID: uuid.New(), UserID: &alice.ID, Action: "join"},
+		}
+		for i := range logs {
+			if err := repo.RoomLog.CreateLog(&logs[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := repo.RoomLog.ListRoomLogs(roomID, []string{"join", "leave", "kick"})
+		if err != nil {
+			t.Fatalf("ListRoomLogs() error = %v", err)
+		}
+		if len(got) != 2 || got[0].Action != "join" || got[1].Action != "leave" {
+			t.Fatalf("ListRoomLogs() = %+v", got)
+		}
+		if got[0].User == nil || got[0].User.Username == nil || *got[0].User.Username != username {
+			t.Errorf("ユーザーが読み込まれていない: %+v", got[0].User)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomVisibility(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.UserFollow{}, &models.UserActivity{})
+
+	host, follower, pending, stranger := createTestUser(t, repo, "ホスト"), createTestUser(t, repo, "フォロワー"), createTestUser(t, repo, "申請中"), createTestUser(t, repo, "通りすがり")
+
+	for user, status := range map[*models.User]string{follower: models.FollowStatusAccepted, pending: models.FollowStatusPending} {
+		follow := &models.UserFollow{FollowerUserID: user.ID, FollowingUserID: host.ID, Status: status}
+		if err := db.Create(follow).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rooms := map[string]*models.Room{}
+	for i, visibility := range []string{models.RoomVisibilityPublic, models.RoomVisibilityFollowers, models.RoomVisibilityUnlisted} {
+		room := &models.Room{
+			BaseModel:     models.BaseModel{ID: uuid.New()},
+			RoomCode:      "VIS0" + string(rune('1'+i)),
+			Name:          visibility + "の部屋",
+			GameVersionID: uuid.New(),
+			HostUserID:    host.ID,
+			MaxPlayers:    4,
+			IsActive:      true,
+			Visibility:    visibility,
+		}
+		if err := db.Create(room).Error; err != nil {
+			t.Fatal(err)
+		}
+		rooms[visibility] = room
+	}
+
+	listed := func(viewer *models.User) map[string]bool {
+		t.Helper()
+		params := RoomSearchParams{Limit: 10}
+		if viewer != nil {
+			params.ViewerID = &viewer.ID
+		}
+		result, err := repo.Room.GetActiveRooms(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := map[string]bool{}
+		for _, room := range result {
+			names[room.GetVisibility()] = true
+		}
+		return names
+	}
+
+	tests := []struct {
+		name   string
+		viewer *models.User
+		want   map[string]bool
+	}{
+		{"未ログイン", nil, map[string]bool{models.RoomVisibilityPublic: true}},
+		{"フォロー未承認", pending, map[string]bool{models.RoomVisibilityPublic: true}},
+		{"フォロワー", follower, map[string]bool{models.RoomVisibilityPublic: true, models.RoomVisibilityFollowers: true}},
+		{"ホスト", host, map[string]bool{models.RoomVisibilityPublic: true, models.RoomVisibilityFollowers: true, models.RoomVisibilityUnlisted: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := listed(tt.viewer)
+			if len(got) != len(tt.want) {
+				t.Fatalf("一覧の公開範囲 = %v, want %v", got, tt.want)
+			}
+			for visibility := range tt.want {
+				if !got[visibility] {
+					t.Errorf("%s の部屋が一覧にない: %v", visibility, got)
+				}
+			}
+		})
+	}
+
+	// 参加中のメンバーには限定公開の部屋も一覧に出る
+	member := models.RoomMember{ID: uuid.New(), RoomID: rooms[models.RoomVisibilityUnlisted].ID, UserID: stranger.ID, PlayerNumber: 1, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := listed(stranger); !got[models.RoomVisibilityUnlisted] || got[models.RoomVisibilityFollowers] {
+		t.Errorf("参加中メンバーの一覧 = %v", got)
+	}
+
+	// プロフィールのホスト部屋も閲覧者に応じて絞り込む
+	for _, tc := range []struct {
+		viewer *uuid.UUID
+		want   int64
+	}{{nil, 1}, {&follower.ID, 2}, {&host.ID, 3}} {
+		count, err := repo.Room.CountRoomsByHostUser(host.ID, tc.viewer)
+		if err != nil || count != tc.want {
+			t.Errorf("CountRoomsByHostUser(viewer=%v) = %d, %v, want %d", tc.viewer, count, err, tc.want)
+		}
+		hosted, err := repo.Room.GetRoomsByHostUser(host.ID, tc.viewer, 10, 0)
+		if err != nil || int64(len(hosted)) != tc.want {
+			t.Errorf("GetRoomsByHostUser(viewer=%v) = %d件, %v, want %d", tc.viewer, len(hosted), err, tc.want)
+		}
+	}
+
+	// 公開以外の部屋に関する活動は最近の活動に出さない
+	entityType := models.EntityTypeRoom
+	for visibility, room := range rooms {
+		roomID := room.ID
+		activity := &models.UserActivity{UserID: host.ID, ActivityType: models.ActivityRoomCreate, Title: visibility, RelatedEntityType: &entityType, RelatedEntityID: &roomID}
+		if err := repo.UserActivity.CreateActivity(activity); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recent, err := repo.UserActivity.GetRecentPublicActivities(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || recent[0].Title != models.RoomVisibilityPublic {
+		t.Errorf("最近の活動 = %d件, want 公開の部屋のみ", len(recent))
+	}
+}
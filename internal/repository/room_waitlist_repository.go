@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mhp-rooms/internal/models"
+)
+
+// roomWaitlistRepository は満員の部屋のキャンセル待ちを扱うリポジトリの実装
+type roomWaitlistRepository struct {
+	db DBInterface
+}
+
+// NewRoomWaitlistRepository は新しいRoomWaitlistRepositoryインスタンスを作成
+func NewRoomWaitlistRepository(db DBInterface) RoomWaitlistRepository {
+	return &roomWaitlistRepository{db: db}
+}
+
+// JoinWaitlist 満員の部屋のキャンセル待ちに登録する。既に待機中・確保中ならその登録をそのまま返す
+func (r *roomWaitlistRepository) JoinWaitlist(roomID, userID uuid.UUID) (*models.RoomWaitlistEntry, error) {
+	var entry models.RoomWaitlistEntry
+	err := r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		if err := tx.Where("id = ?", roomID).First(&room).Error; err != nil {
+			return err
+		}
+		if !room.IsActive || room.IsClosed {
+			return fmt.Errorf("この部屋は募集を締め切っています")
+		}
+
+		var member models.RoomMember
+		if err := tx.Where("room_id = ? AND user_id = ? AND status = ?", roomID, userID, models.MemberStatusActive).
+			First(&member).Error; err == nil {
+			return fmt.Errorf("ALREADY_JOINED:既にルームに参加しています")
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("部屋メンバー検索エラー: %w", err)
+		}
+		if err := checkJoinRestrictions(tx, &room, userID, time.Now()); err != nil {
+			return err
+		}
+
+		if err := tx.Where("room_id = ? AND user_id = ? AND status IN ?", roomID, userID, activeWaitlistStatuses).
+			First(&entry).Error; err == nil {
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("キャンセル待ち検索エラー: %w", err)
+		}
+
+		reserved, err := reservedSeatCount(r.db, tx, roomID, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		if room.CurrentPlayers+reserved < room.MaxPlayers {
+			return fmt.Errorf("NOT_FULL:空きがあるため、そのまま参加できます")
+		}
+
+		entry = models.RoomWaitlistEntry{
+			RoomID: roomID,
+			UserID: userID,
+			Status: models.WaitlistStatusWaiting,
+		}
+		return tx.Create(&entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// LeaveWaitlist キャンセル待ちを取り消す。確保中の空き席も手放す
+func (r *roomWaitlistRepository) LeaveWaitlist(roomID, userID uuid.UUID) error {
+	result := r.db.GetConn().
+		Model(&models.RoomWaitlistEntry{}).
+		Where("room_id = ? AND user_id = ? AND status IN ?", roomID, userID, activeWaitlistStatuses).
+		Update("status", models.WaitlistStatusCancelled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("キャンセル待ちに登録されていません")
+	}
+	return nil
+}
+
+// FindActiveEntry 待機中・確保中の登録と待ち順（1始まり。確保中は0）を取得する。登録がなければ nil を返す
+func (r *roomWaitlistRepository) FindActiveEntry(roomID, userID uuid.UUID) (*models.RoomWaitlistEntry, int, error) {
+	var entry models.RoomWaitlistEntry
+	result := r.db.GetConn().
+		Where("room_id = ? AND user_id = ? AND status IN ?", roomID, userID, activeWaitlistStatuses).
+		Limit(1).
+		Find(&entry)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, 0, nil
+	}
+	if entry.Status == models.WaitlistStatusOffered {
+		return &entry, 0, nil
+	}
+
+	waiting, err := r.GetWaitingEntries(roomID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range waiting {
+		if waiting[i].ID == entry.ID {
+			return &entry, i + 1, nil
+		}
+	}
+	return &entry, len(waiting), nil
+}
+
+// GetWaitingEntries 空き待ちの登録を待ち順に取得
+func (r *roomWaitlistRepository) GetWaitingEntries(roomID uuid.UUID) ([]models.RoomWaitlistEntry, error) {
+	var entries []models.RoomWaitlistEntry
+	err := r.db.GetConn().
+		Where("room_id = ? AND status = ?", roomID, models.WaitlistStatusWaiting).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// CountReservedSeats now の時点でキャンセル待ちの待機者に確保されている空き席の数
+func (r *roomWaitlistRepository) CountReservedSeats(roomID uuid.UUID, now time.Time) (int, error) {
+	return reservedSeatCount(r.db, r.db.GetConn(), roomID, uuid.Nil, now)
+}
+
+// PromoteWaitlist 期限切れの確保を取り消し、空いた席の数だけ先頭の待機者に reservation の間だけ席を確保する。
+// 部屋が解散済みなら登録をすべて取り消す。新しく確保した登録と、期限切れになった登録を返す
+func (r *roomWaitlistRepository) PromoteWaitlist(roomID uuid.UUID, now time.Time, reservation time.Duration) (offered, expired []models.RoomWaitlistEntry, err error) {
+	err = r.db.GetConn().Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		if err := tx.Where("id = ?", roomID).First(&room).Error; err != nil {
+			return err
+		}
+
+		if !room.IsActive {
+			return tx.Model(&models.RoomWaitlistEntry{}).
+				Where("room_id = ? AND status IN ?", roomID, activeWaitlistStatuses).
+				Update("status", models.WaitlistStatusCancelled).Error
+		}
+
+		var offers []models.RoomWaitlistEntry
+		if err := tx.Where("room_id = ? AND status = ?", roomID, models.WaitlistStatusOffered).Find(&offers).Error; err != nil {
+			return err
+		}
+		validOffers := 0
+		for _, offer := range offers {
+			if offer.HasValidOffer(now) {
+				validOffers++
+				continue
+			}
+			if err := tx.Model(&offer).Update("status", models.WaitlistStatusExpired).Error; err != nil {
+				return err
+			}
+			offer.Status = models.WaitlistStatusExpired
+			expired = append(expired, offer)
+		}
+
+		free := room.MaxPlayers - room.CurrentPlayers - validOffers
+		if room.IsClosed || free <= 0 {
+			return nil
+		}
+
+		var waiting []models.RoomWaitlistEntry
+		if err := tx.Where("room_id = ? AND status = ?", roomID, models.WaitlistStatusWaiting).
+			Order("created_at ASC, id ASC").
+			Limit(free).
+			Find(&waiting).Error; err != nil {
+			return err
+		}
+		expiresAt := now.Add(reservation)
+		for _, entry := range waiting {
+			if err := tx.Model(&entry).Updates(map[string]interface{}{
+				"status":           models.WaitlistStatusOffered,
+				"offered_at":       now,
+				"offer_expires_at": expiresAt,
+			}).Error; err != nil {
+				return err
+			}
+			entry.Status = models.WaitlistStatusOffered
+			entry.OfferedAt = &now
+			entry.OfferExpiresAt = &expiresAt
+			offered = append(offered, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return offered, expired, nil
+}
+
+// activeWaitlistStatuses 順番待ちとして有効な登録の状態
+var activeWaitlistStatuses = []string{models.WaitlistStatusWaiting, models.WaitlistStatusOffered}
+
+// reservedSeatCount exceptUserID 以外の待機者に確保されていて、now の時点で期限内の空き席の数
+func reservedSeatCount(db DBInterface, tx *gorm.DB, roomID, exceptUserID uuid.UUID, now time.Time) (int, error) {
+	ts, param := timeComparison(db)
+	var count int64
+	if err := tx.Model(&models.RoomWaitlistEntry{}).
+		Where("room_id = ? AND user_id <> ? AND status = ?", roomID, exceptUserID, models.WaitlistStatusOffered).
+		Where("offer_expires_at IS NOT NULL AND "+ts("offer_expires_at")+" > "+param, now).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("キャンセル待ち検索エラー: %w", err)
+	}
+	return int(count), nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRoomWaitlist(t *testing.T) {
+	db, repo := newTestRepository(t, &models.User{}, &models.GameVersion{}, &models.Room{}, &models.RoomMember{}, &models.RoomLog{}, &models.RoomWaitlistEntry{}, &models.RoomJoinRequest{}, &models.HostBan{}, &models.RoomSessionSummary{}, &models.RoomMessage{})
+
+	host, guest, first, second := createTestUser(t, repo, "ホスト"), createTestUser(t, repo, "参加者"), createTestUser(t, repo, "待機1"), createTestUser(t, repo, "待機2")
+
+	room := &models.Room{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		RoomCode:       "FULL01",
+		Name:           "満員部屋",
+		GameVersionID:  uuid.New(),
+		HostUserID:     host.ID,
+		MaxPlayers:     2,
+		CurrentPlayers: 2,
+		IsActive:       true,
+	}
+	if err := db.Create(room).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, user := range []*models.User{host, guest} {
+		member := models.RoomMember{ID: uuid.New(), RoomID: room.ID, UserID: user.ID, PlayerNumber: i + 1, IsHost: i == 0, Status: models.MemberStatusActive, JoinedAt: time.Now()}
+		if err := db.Create(&member).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expectPrefix := func(err error, prefix string) {
+		t.Helper()
+		if err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("err = %v, want %s", err, prefix)
+		}
+	}
+
+	// 満員の部屋には参加できず、キャンセル待ちに登録できる
+	expectPrefix(repo.Room.JoinRoom(room.ID, first.ID, ""), "ROOM_FULL:")
+	for _, user := range []*models.User{first, second} {
+		if _, err := repo.RoomWaitlist.JoinWaitlist(room.ID, user.ID); err != nil {
+			t.Fatalf("JoinWaitlist(%s) = %v", user.DisplayName, err)
+		}
+	}
+	again, err := repo.RoomWaitlist.JoinWaitlist(room.ID, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.RoomWaitlist.JoinWaitlist(room.ID, guest.ID)
+	expectPrefix(err, "ALREADY_JOINED:")
+
+	entry, position, err := repo.RoomWaitlist.FindActiveEntry(room.ID, second.ID)
+	if err != nil || entry == nil || position != 2 {
+		t.Fatalf("待機2の待ち順 = %v, %d, %v, want 2番目", entry, position, err)
+	}
+	if entry, _, _ := repo.RoomWaitlist.FindActiveEntry(room.ID, first.ID); entry == nil || entry.ID != again.ID {
+		t.Errorf("二重登録で新しい登録が作られた: %v", entry)
+	}
+
+	// 空きがなければ誰にも席を確保しない
+	now := time.Now()
+	offered, expired, err := repo.RoomWaitlist.PromoteWaitlist(room.ID, now, 5*time.Minute)
+	if err != nil || len(offered) != 0 || len(expired) != 0 {
+		t.Fatalf("満員での繰り上げ = %v, %v, %v", offered, expired, err)
+	}
+
+	// 退出で空いた席は先頭の待機者に確保され、他のユーザーは参加できない
+	if err := repo.Room.LeaveRoom(room.ID, guest.ID); err != nil {
+		t.Fatal(err)
+	}
+	offered, _, err = repo.RoomWaitlist.PromoteWaitlist(room.ID, now, 5*time.Minute)
+	if err != nil || len(offered) != 1 || offered[0].UserID != first.ID {
+		t.Fatalf("退出後の繰り上げ = %v, %v, want 待機1", offered, err)
+	}
+	expectPrefix(repo.Room.JoinRoom(room.ID, second.ID, ""), "ROOM_FULL:")
+	if reserved, _ := repo.RoomWaitlist.CountReservedSeats(room.ID, now); reserved != 1 {
+		t.Errorf("確保中の席 = %d, want 1", reserved)
+	}
+	if reserved, _ := repo.RoomWaitlist.CountReservedSeats(room.ID, now.Add(6*time.Minute)); reserved != 0 {
+		t.Errorf("期限切れの確保を数えている: %d", reserved)
+	}
+	if _, position, _ := repo.RoomWaitlist.FindActiveEntry(room.ID, second.ID); position != 1 {
+		t.Errorf("繰り上げ後の待機2の待ち順 = %d, want 1", position)
+	}
+
+	// 確保期限が切れると次の待機者に回る
+	later := now.Add(6 * time.Minute)
+	offered, expired, err = repo.RoomWaitlist.PromoteWaitlist(room.ID, later, 5*time.Minute)
+	if err != nil || len(expired) != 1 || expired[0].UserID != first.ID || len(offered) != 1 || offered[0].UserID != second.ID {
+		t.Fatalf("期限切れ後の繰り上げ = offered %v / expired %v, %v", offered, expired, err)
+	}
+	if entry, _, _ := repo.RoomWaitlist.FindActiveEntry(room.ID, first.ID); entry != nil {
+		t.Errorf("期限切れの登録が残っている: %+v", entry)
+	}
+
+	// 席を確保された本人は参加でき、登録は参加済みになる
+	if err := repo.Room.JoinRoom(room.ID, second.ID, ""); err != nil {
+		t.Fatalf("確保された席での参加 = %v", err)
+	}
+	var joined models.RoomWaitlistEntry
+	if err := db.Where("user_id = ?", second.ID).First(&joined).Error; err != nil || joined.Status != models.WaitlistStatusJoined {
+		t.Errorf("参加後の登録 = %+v, %v, want joined", joined, err)
+	}
+
+	// 解散するとキャンセル待ちはすべて取り消される
+	if _, err := repo.RoomWaitlist.JoinWaitlist(room.ID, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Room.DismissRoom(room.ID, models.DismissReasonHost); err != nil {
+		t.Fatal(err)
+	}
+	if entry, _, _ := repo.RoomWaitlist.FindActiveEntry(room.ID, first.ID); entry != nil {
+		t.Errorf("解散後もキャンセル待ちが残っている: %+v", entry)
+	}
+	if err := repo.RoomWaitlist.LeaveWaitlist(room.ID, first.ID); err == nil {
+		t.Error("取り消し済みの登録を取り消せてしまう")
+	}
+}
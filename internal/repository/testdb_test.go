@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+
+	"mhp-rooms/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testDB インメモリの SQLite を DBInterface として使うテスト用のラッパー。
+// dbType に "turso" を指定すると、libSQL 向けの SQL を SQLite で確かめられる
+type testDB struct {
+	conn   *gorm.DB
+	dbType string
+}
+
+func (d testDB) GetConn() *gorm.DB { return d.conn }
+func (d testDB) Close() error      { return nil }
+func (d testDB) GetType() string {
+	if d.dbType == "" {
+		return "sqlite"
+	}
+	return d.dbType
+}
+
+// newTestDB インメモリの SQLite を開き、テストで使うテーブルを作る
+func newTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// newTestRepository テーブルを作ったインメモリの SQLite と、それを使う Repository を返す
+func newTestRepository(t *testing.T, tables ...interface{}) (*gorm.DB, *Repository) {
+	t.Helper()
+	db := newTestDB(t, tables...)
+	return db, NewRepository(testDB{conn: db})
+}
+
+// createTestUser name を表示名とメールアドレスに使った一般ユーザーを作る
+func createTestUser(t *testing.T, repo *Repository, name string) *models.User {
+	t.Helper()
+	user := &models.User{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		SupabaseUserID: uuid.New(),
+		Email:          name + "@example.test",
+		DisplayName:    name,
+		IsActive:       true,
+		Role:           models.RoleUser,
+	}
+	if err := repo.User.CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
@@ -131,6 +131,9 @@ func (r *userActivityRepository) GetRecentPublicActivities(limit int) ([]models.
 	err := r.db.GetConn().
 		Joins("JOIN users ON users.id = user_activities.user_id AND users.is_active = ?", true).
 		Where("user_activities.activity_type IN ?", models.PublicFeedActivityTypes()).
+		// 公開範囲が「公開」以外の部屋に関する活動はフィードに出さない
+		Where("user_activities.related_entity_type IS NULL OR user_activities.related_entity_type <> ? OR NOT EXISTS (SELECT 1 FROM rooms WHERE rooms.id = user_activities.related_entity_id AND rooms.visibility <> ?)",
+			models.EntityTypeRoom, models.RoomVisibilityPublic).
 		Order("user_activities.created_at DESC").
 		Limit(limit).
 		Preload("User", func(db *gorm.DB) *gorm.DB {
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/storage"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// ErrChatImageUnavailable 画像の保存先が設定されていない
+var ErrChatImageUnavailable = errors.New("画像アップロードサービスが利用できません")
+
+// ChatImageStorage チャット画像の保存先（storage.GCSUploader が実装する）
+type ChatImageStorage interface {
+	UploadChatImage(ctx context.Context, roomID string, file multipart.File, header *multipart.FileHeader) (*storage.ChatImageUploadResult, error)
+	DeleteObjects(ctx context.Context, objectPaths []string) error
+}
+
+// ChatAttachmentService チャットの添付画像の保存と後始末を行うサービス
+type ChatAttachmentService struct {
+	repo    *repository.Repository
+	storage ChatImageStorage
+}
+
+// NewChatAttachmentService 新しいChatAttachmentServiceインスタンスを作成
+// storage が nil の場合、アップロードはできず後始末はレコードが残っていればエラーになる
+func NewChatAttachmentService(repo *repository.Repository, storage ChatImageStorage) *ChatAttachmentService {
+	return &ChatAttachmentService{repo: repo, storage: storage}
+}
+
+// Available 画像をアップロードできるか
+func (s *ChatAttachmentService) Available() bool {
+	return s.storage != nil
+}
+
+// Upload 画像とサムネイルを保存し、メッセージに紐づける前の添付レコードを返す
+func (s *ChatAttachmentService) Upload(ctx context.Context, roomID uuid.UUID, file multipart.File, header *multipart.FileHeader) (*models.RoomMessageAttachment, error) {
+	if s.storage == nil {
+		return nil, ErrChatImageUnavailable
+	}
+
+	result, err := s.storage.UploadChatImage(ctx, roomID.String(), file, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RoomMessageAttachment{
+		RoomID:              roomID,
+		ObjectPath:          result.ObjectPath,
+		URL:                 result.URL,
+		ContentType:         result.ContentType,
+		SizeBytes:           result.SizeBytes,
+		Width:               result.Width,
+		Height:              result.Height,
+		ThumbnailObjectPath: result.ThumbnailObjectPath,
+		ThumbnailURL:        result.ThumbnailURL,
+		ThumbnailWidth:      result.ThumbnailWidth,
+		ThumbnailHeight:     result.ThumbnailHeight,
+	}, nil
+}
+
+// Discard メッセージの保存に失敗したときに、アップロード済みの画像を削除する
+func (s *ChatAttachmentService) Discard(ctx context.Context, attachment *models.RoomMessageAttachment) error {
+	if s.storage == nil || attachment == nil {
+		return nil
+	}
+	return s.storage.DeleteObjects(ctx, attachment.ObjectPaths())
+}
+
+// PurgeMessage 削除されたメッセージの添付画像を削除する
+func (s *ChatAttachmentService) PurgeMessage(ctx context.Context, message *models.RoomMessage) error {
+	if message.Attachment == nil {
+		return nil
+	}
+	return s.purge(ctx, []models.RoomMessageAttachment{*message.Attachment})
+}
+
+// PurgeRoom 解散した部屋の添付画像をすべて削除する
+func (s *ChatAttachmentService) PurgeRoom(ctx context.Context, roomID uuid.UUID) error {
+	attachments, err := s.repo.RoomMessage.ListAttachmentsByRoom(roomID)
+	if err != nil {
+		return fmt.Errorf("添付画像の取得に失敗しました: %w", err)
+	}
+	return s.purge(ctx, attachments)
+}
+
+// PurgeOrphaned 削除済みメッセージ・解散済みの部屋に残っている添付画像を最大 limit 件削除し、削除した件数を返す
+func (s *ChatAttachmentService) PurgeOrphaned(ctx context.Context, limit int) (int, error) {
+	attachments, err := s.repo.RoomMessage.ListOrphanedAttachments(limit)
+	if err != nil {
+		return 0, fmt.Errorf("添付画像の取得に失敗しました: %w", err)
+	}
+	if err := s.purge(ctx, attachments); err != nil {
+		return 0, err
+	}
+	return len(attachments), nil
+}
+
+// purge ストレージ上の実体を消してからレコードを削除する。
+// 実体の削除に失敗した場合はレコードを残し、PurgeOrphaned で再試行できるようにする
+func (s *ChatAttachmentService) purge(ctx context.Context, attachments []models.RoomMessageAttachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	if s.storage == nil {
+		return ErrChatImageUnavailable
+	}
+
+	var paths []string
+	ids := make([]uuid.UUID, 0, len(attachments))
+	for i := range attachments {
+		paths = append(paths, attachments[i].ObjectPaths()...)
+		ids = append(ids, attachments[i].ID)
+	}
+	if err := s.storage.DeleteObjects(ctx, paths); err != nil {
+		return fmt.Errorf("添付画像の削除に失敗しました: %w", err)
+	}
+	if err := s.repo.RoomMessage.DeleteAttachments(ids); err != nil {
+		return fmt.Errorf("添付画像レコードの削除に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/infrastructure/storage"
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// fakeAttachmentRepo 添付画像のレコード操作だけを差し替えたテスト用リポジトリ
+type fakeAttachmentRepo struct {
+	repository.RoomMessageRepository
+	attachments []models.RoomMessageAttachment
+	deletedIDs  []uuid.UUID
+}
+
+func (f *fakeAttachmentRepo) ListAttachmentsByRoom(roomID uuid.UUID) ([]models.RoomMessageAttachment, error) {
+	var result []models.RoomMessageAttachment
+	for _, a := range f.attachments {
+		if a.RoomID == roomID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAttachmentRepo) DeleteAttachments(ids []uuid.UUID) error {
+	f.deletedIDs = append(f.deletedIDs, ids...)
+	return nil
+}
+
+// fakeChatImageStorage 削除したパスを記録するだけのテスト用ストレージ
+type fakeChatImageStorage struct {
+	deleted   []string
+	deleteErr error
+}
+
+func (f *fakeChatImageStorage) UploadChatImage(context.Context, string, multipart.File, *multipart.FileHeader) (*storage.ChatImageUploadResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeChatImageStorage) DeleteObjects(_ context.Context, objectPaths []string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, objectPaths...)
+	return nil
+}
+
+func newTestAttachment(roomID uuid.UUID, name string) models.RoomMessageAttachment {
+	return models.RoomMessageAttachment{
+		BaseModel:           models.BaseModel{ID: uuid.New()},
+		MessageID:           uuid.New(),
+		RoomID:              roomID,
+		ObjectPath:          "chat/" + roomID.String() + "/" + name + ".png",
+		ThumbnailObjectPath: "chat/" + roomID.String() + "/thumbs/" + name + ".jpg",
+	}
+}
+
+func TestChatAttachmentServicePurgeRoom(t *testing.T) {
+	roomID, otherRoomID := uuid.New(), uuid.New()
+	first := newTestAttachment(roomID, "first")
+	second := newTestAttachment(roomID, "second")
+	other := newTestAttachment(otherRoomID, "other")
+
+	tests := []struct {
+		name        string
+		storage     *fakeChatImageStorage
+		roomID      uuid.UUID
+		wantErr     error
+		wantDeleted []string
+		wantIDs     []uuid.UUID
+	}{
+		{
+			name:        "本体とサムネイルを消してからレコードを削除する",
+			storage:     &fakeChatImageStorage{},
+			roomID:      roomID,
+			wantDeleted: append(first.ObjectPaths(), second.ObjectPaths()...),
+			wantIDs:     []uuid.UUID{first.ID, second.ID},
+		},
+		{
+			name:    "ストレージの削除に失敗したらレコードを残す",
+			storage: &fakeChatImageStorage{deleteErr: errors.New("gcs down")},
+			roomID:  roomID,
+			wantErr: errors.New("添付画像の削除に失敗しました"),
+		},
+		{
+			name:    "保存先がなければエラーにしてレコードを残す",
+			roomID:  roomID,
+			wantErr: ErrChatImageUnavailable,
+		},
+		{
+			name:   "添付がなければ保存先がなくても何もしない",
+			roomID: uuid.New(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAttachmentRepo{attachments: []models.RoomMessageAttachment{first, second, other}}
+			var chatStorage ChatImageStorage
+			if tt.storage != nil {
+				chatStorage = tt.storage
+			}
+			svc := NewChatAttachmentService(&repository.Repository{RoomMessage: repo}, chatStorage)
+
+			err := svc.PurgeRoom(context.Background(), tt.roomID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("PurgeRoom() error = nil, want %v", tt.wantErr)
+				}
+				if len(repo.deletedIDs) != 0 {
+					t.Errorf("失敗時にレコードが削除されている: %v", repo.deletedIDs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PurgeRoom() error = %v", err)
+			}
+			if tt.storage != nil && !equalStrings(tt.storage.deleted, tt.wantDeleted) {
+				t.Errorf("削除したパス = %v, want %v", tt.storage.deleted, tt.wantDeleted)
+			}
+			if len(repo.deletedIDs) != len(tt.wantIDs) {
+				t.Fatalf("削除したレコード = %v, want %v", repo.deletedIDs, tt.wantIDs)
+			}
+			for i := range tt.wantIDs {
+				if repo.deletedIDs[i] != tt.wantIDs[i] {
+					t.Errorf("削除したレコード[%d] = %s, want %s", i, repo.deletedIDs[i], tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChatAttachmentServicePurgeMessage(t *testing.T) {
+	roomID := uuid.New()
+	attachment := newTestAttachment(roomID, "shot")
+	fakeStorage := &fakeChatImageStorage{}
+	repo := &fakeAttachmentRepo{}
+	svc := NewChatAttachmentService(&repository.Repository{RoomMessage: repo}, fakeStorage)
+
+	// 添付のないメッセージは何もしない
+	if err := svc.PurgeMessage(context.Background(), &models.RoomMessage{RoomID: roomID}); err != nil {
+		t.Fatalf("PurgeMessage() error = %v", err)
+	}
+	if len(fakeStorage.deleted) != 0 || len(repo.deletedIDs) != 0 {
+		t.Fatalf("添付のないメッセージで削除が行われた")
+	}
+
+	message := &models.RoomMessage{RoomID: roomID, MessageType: models.RoomMessageTypeImage, Attachment: &attachment}
+	if err := svc.PurgeMessage(context.Background(), message); err != nil {
+		t.Fatalf("PurgeMessage() error = %v", err)
+	}
+	if !equalStrings(fakeStorage.deleted, attachment.ObjectPaths()) {
+		t.Errorf("削除したパス = %v, want %v", fakeStorage.deleted, attachment.ObjectPaths())
+	}
+	if len(repo.deletedIDs) != 1 || repo.deletedIDs[0] != attachment.ID {
+		t.Errorf("削除したレコード = %v, want [%s]", repo.deletedIDs, attachment.ID)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// ErrNGWordBlocked 入力に投稿できない表現（扱いが block のNGワード）が含まれている
+var ErrNGWordBlocked = errors.New("不適切な表現が含まれているため保存できません")
+
+// ngWordMask 伏せ字に使う文字（一致した部分の1文字ごとに置き換える）
+const ngWordMask = "*"
+
+// NGWordFilter 管理者が登録したNGワードを入力に適用するサービス
+type NGWordFilter struct {
+	repo *repository.Repository
+}
+
+// NewNGWordFilter 新しいNGWordFilterインスタンスを作成
+func NewNGWordFilter(repo *repository.Repository) *NGWordFilter {
+	return &NGWordFilter{repo: repo}
+}
+
+// Apply 有効なNGワードを text に適用する。block のNGワードに一致すれば ErrNGWordBlocked を、
+// mask のみなら一致部分を伏せ字にした文字列を返す。一致したNGワードはすべて記録する。
+// NGワードを読み込めない場合は入力をそのまま通す
+func (f *NGWordFilter) Apply(userID uuid.UUID, roomID *uuid.UUID, target, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+	words, err := f.repo.NGWord.ListActiveNGWords()
+	if err != nil {
+		log.Printf("NGワードの取得に失敗しました: %v", err)
+		return text, nil
+	}
+
+	result := filterNGWords(words, text)
+	if len(result.matched) == 0 {
+		return text, nil
+	}
+
+	hits := make([]models.NGWordHit, 0, len(result.matched))
+	for _, word := range result.matched {
+		hits = append(hits, models.NGWordHit{
+			NGWordID: word.ID,
+			Pattern:  word.Pattern,
+			Action:   word.Action,
+			UserID:   userID,
+			RoomID:   roomID,
+			Target:   target,
+			Content:  text,
+		})
+	}
+	if err := f.repo.NGWord.CreateHits(hits); err != nil {
+		log.Printf("NGワードの一致記録に失敗しました user_id=%s target=%s: %v", userID, target, err)
+	}
+
+	if result.blocked {
+		return "", ErrNGWordBlocked
+	}
+	return result.text, nil
+}
+
+// ValidateNGWordPattern 登録しようとしているNGワードのパターンを検証する
+func ValidateNGWordPattern(matchType, pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("NGワードを入力してください")
+	}
+	if utf8.RuneCountInString(pattern) > 200 {
+		return fmt.Errorf("NGワードは200文字以内で入力してください")
+	}
+	if !models.IsValidNGWordMatchType(matchType) {
+		return fmt.Errorf("無効な照合方法です")
+	}
+	if matchType == models.NGWordMatchRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("正規表現が正しくありません: %v", err)
+		}
+		if re.MatchString("") {
+			return fmt.Errorf("空文字に一致する正規表現は登録できません")
+		}
+	}
+	return nil
+}
+
+// ngWordResult NGワードを適用した結果
+type ngWordResult struct {
+	text    string          // 伏せ字にした後の文字列
+	blocked bool            // block のNGワードに一致したか
+	matched []models.NGWord // 一致したNGワード
+}
+
+// filterNGWords NGワードの一致を調べ、mask のNGワードに一致した部分を伏せ字にする
+func filterNGWords(words []models.NGWord, text string) ngWordResult {
+	result := ngWordResult{text: text}
+	var masks [][]int
+	for _, word := range words {
+		ranges := ngWordRanges(word, text)
+		if len(ranges) == 0 {
+			continue
+		}
+		result.matched = append(result.matched, word)
+		if word.Action == models.NGWordActionBlock {
+			result.blocked = true
+		} else {
+			masks = append(masks, ranges...)
+		}
+	}
+	if !result.blocked && len(masks) > 0 {
+		result.text = maskRanges(text, masks)
+	}
+	return result
+}
+
+// ngWordRanges text 中でNGワードに一致する範囲（バイト位置）を返す
+func ngWordRanges(word models.NGWord, text string) [][]int {
+	var re *regexp.Regexp
+	var err error
+	switch word.MatchType {
+	case models.NGWordMatchRegex:
+		re, err = regexp.Compile(word.Pattern)
+	default:
+		re, err = regexp.Compile("(?i)" + regexp.QuoteMeta(word.Pattern))
+	}
+	if err != nil {
+		log.Printf("NGワードのパターンが不正です id=%s: %v", word.ID, err)
+		return nil
+	}
+
+	var ranges [][]int
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		if word.MatchType == models.NGWordMatchExact && !isWordBoundary(text, loc[0], loc[1]) {
+			continue
+		}
+		ranges = append(ranges, loc)
+	}
+	return ranges
+}
+
+// isWordBoundary text[start:end] の前後が文字・数字でないか（単語として一致しているか）
+func isWordBoundary(text string, start, end int) bool {
+	isWordRune := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// maskRanges 指定範囲の文字を1文字ずつ伏せ字にする（範囲の重なりはまとめる）
+func maskRanges(text string, ranges [][]int) string {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	var b strings.Builder
+	pos := 0
+	for _, rg := range ranges {
+		start, end := rg[0], rg[1]
+		if end <= pos {
+			continue
+		}
+		if start < pos {
+			start = pos
+		}
+		b.WriteString(text[pos:start])
+		b.WriteString(strings.Repeat(ngWordMask, utf8.RuneCountInString(text[start:end])))
+		pos = end
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
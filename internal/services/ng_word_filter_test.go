@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mhp-rooms/internal/models"
+	"mhp-rooms/internal/repository"
+)
+
+// fakeNGWordRepo 有効なNGワードを返し、一致記録を保持するだけのテスト用リポジトリ
+type fakeNGWordRepo struct {
+	repository.NGWordRepository
+	words []models.NGWord
+	hits  []models.NGWordHit
+}
+
+func (f *fakeNGWordRepo) ListActiveNGWords() ([]models.NGWord, error) { return f.words, nil }
+func (f *fakeNGWordRepo) CreateHits(hits []models.NGWordHit) error {
+	f.hits = append(f.hits, hits...)
+	return nil
+}
+
+func ngWord(pattern, matchType, action string) models.NGWord {
+	return models.NGWord{BaseModel: models.BaseModel{ID: uuid.New()}, Pattern: pattern, MatchType: matchType, Action: action, IsActive: true}
+}
+
+func TestFilterNGWords(t *testing.T) {
+	tests := []struct {
+		name        string
+		words       []models.NGWord
+		text        string
+		want        string
+		wantBlocked bool
+		wantMatched int
+	}{
+		{
+			name:  "一致しない",
+			words: []models.NGWord{ngWord("ばか", models.NGWordMatchSubstring, models.NGWordActionMask)},
+			text:  "よろしくお願いします",
+			want:  "よろしくお願いします",
+		},
+		{
+			name:        "部分一致は伏せ字（大文字小文字を区別しない）",
+			words:       []models.NGWord{ngWord("spam", models.NGWordMatchSubstring, models.NGWordActionMask)},
+			text:        "SPAMです、spamspam",
+			want:        "****です、********",
+			wantMatched: 1,
+		},
+		{
+			name:  "単語一致は前後に文字が続くと一致しない",
+			words: []models.NGWord{ngWord("ass", models.NGWordMatchExact, models.NGWordActionMask)},
+			text:  "pass the class",
+			want:  "pass the class",
+		},
+		{
+			name:        "単語一致",
+			words:       []models.NGWord{ngWord("ass", models.NGWordMatchExact, models.NGWordActionMask)},
+			text:        "you ass!",
+			want:        "you ***!",
+			wantMatched: 1,
+		},
+		{
+			name:        "正規表現",
+			words:       []models.NGWord{ngWord(`https?://\S+`, models.NGWordMatchRegex, models.NGWordActionMask)},
+			text:        "見て http://spam.example 今すぐ",
+			want:        "見て ******************* 今すぐ",
+			wantMatched: 1,
+		},
+		{
+			name: "拒否のNGワードが1つでもあれば拒否",
+			words: []models.NGWord{
+				ngWord("ばか", models.NGWordMatchSubstring, models.NGWordActionMask),
+				ngWord("死ね", models.NGWordMatchSubstring, models.NGWordActionBlock),
+			},
+			text:        "ばか、死ね",
+			want:        "ばか、死ね",
+			wantBlocked: true,
+			wantMatched: 2,
+		},
+		{
+			name: "重なる範囲はまとめて伏せ字",
+			words: []models.NGWord{
+				ngWord("あほ", models.NGWordMatchSubstring, models.NGWordActionMask),
+				ngWord("ほんだら", models.NGWordMatchSubstring, models.NGWordActionMask),
+			},
+			text:        "あほんだら！",
+			want:        "*****！",
+			wantMatched: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterNGWords(tt.words, tt.text)
+			if got.text != tt.want || got.blocked != tt.wantBlocked || len(got.matched) != tt.wantMatched {
+				t.Errorf("filterNGWords() = %q blocked=%v matched=%d, want %q blocked=%v matched=%d",
+					got.text, got.blocked, len(got.matched), tt.want, tt.wantBlocked, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestValidateNGWordPattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		matchType string
+		pattern   string
+		wantErr   bool
+	}{
+		{name: "部分一致", matchType: models.NGWordMatchSubstring, pattern: "spam"},
+		{name: "正規表現", matchType: models.NGWordMatchRegex, pattern: `(?i)free\s*money`},
+		{name: "空", matchType: models.NGWordMatchSubstring, pattern: " ", wantErr: true},
+		{name: "不明な照合方法", matchType: "fuzzy", pattern: "spam", wantErr: true},
+		{name: "不正な正規表現", matchType: models.NGWordMatchRegex, pattern: "(spam", wantErr: true},
+		{name: "空文字に一致する正規表現", matchType: models.NGWordMatchRegex, pattern: "a*", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateNGWordPattern(tt.matchType, tt.pattern); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNGWordPattern() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNGWordFilterApply(t *testing.T) {
+	fake := &fakeNGWordRepo{words: []models.NGWord{
+		ngWord("spam", models.NGWordMatchSubstring, models.NGWordActionMask),
+		ngWord("死ね", models.NGWordMatchSubstring, models.NGWordActionBlock),
+	}}
+	filter := NewNGWordFilter(&repository.Repository{NGWord: fake})
+	userID, roomID := uuid.New(), uuid.New()
+
+	got, err := filter.Apply(userID, &roomID, models.NGWordTargetChat, "spam部屋")
+	if err != nil || got != "****部屋" {
+		t.Fatalf("Apply() = %q, %v, want 伏せ字", got, err)
+	}
+	if _, err := filter.Apply(userID, nil, models.NGWordTargetBio, "死ね"); !errors.Is(err, ErrNGWordBlocked) {
+		t.Fatalf("Apply() error = %v, want ErrNGWordBlocked", err)
+	}
+	if got, err := filter.Apply(userID, nil, models.NGWordTargetDisplayName, "ハンター"); err != nil || got != "ハンター" {
+		t.Fatalf("Apply() = %q, %v, want そのまま", got, err)
+	}
+
+	if len(fake.hits) != 2 {
+		t.Fatalf("一致記録 = %d 件, want 2", len(fake.hits))
+	}
+	first := fake.hits[0]
+	if first.Content != "spam部屋" || first.Target != models.NGWordTargetChat || first.RoomID == nil || *first.RoomID != roomID || first.Pattern != "spam" {
+		t.Errorf("一致記録 = %+v", first)
+	}
+	if fake.hits[1].Action != models.NGWordActionBlock || fake.hits[1].UserID != userID {
+		t.Errorf("一致記録 = %+v", fake.hits[1])
+	}
+}
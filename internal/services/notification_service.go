@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,6 +11,9 @@ import (
 	"mhp-rooms/internal/repository"
 )
 
+// jst お知らせ本文の日時表記に使うタイムゾーン
+var jst = time.FixedZone("JST", 9*60*60)
+
 // NotificationService ユーザー宛のお知らせを作成するサービス
 type NotificationService struct {
 	repo *repository.Repository
@@ -35,17 +39,27 @@ func (s *NotificationService) NotifyRoomAutoDismissed(room *models.Room) error {
 	})
 }
 
-// NotifyRoomKicked 部屋から退出させられたことを本人に知らせる
-func (s *NotificationService) NotifyRoomKicked(userID uuid.UUID, room *models.Room) error {
+// NotifyRoomKicked 部屋から退出させられたことを本人に知らせる。理由と再参加できるようになる日時（nil なら無期限）を添える
+func (s *NotificationService) NotifyRoomKicked(userID uuid.UUID, room *models.Room, reason *string, until *time.Time) error {
 	if userID == uuid.Nil || room == nil {
 		return fmt.Errorf("invalid input: userID=%v room=%v", userID, room)
 	}
 
+	body := "ホストにより部屋から退出させられました。"
+	if reason != nil {
+		body += fmt.Sprintf("理由: %s。", *reason)
+	}
+	if until != nil {
+		body += fmt.Sprintf("%s まではこの部屋に参加できません。", until.In(jst).Format("1/2 15:04"))
+	} else {
+		body += "この部屋には再度参加できません。"
+	}
+
 	return s.repo.Notification.Create(&models.Notification{
 		UserID:      userID,
 		Type:        models.NotificationRoomKicked,
 		Title:       fmt.Sprintf("部屋「%s」から退出となりました", room.Name),
-		Body:        stringPtr("ホストにより部屋から退出させられました。この部屋には再度参加できません。"),
+		Body:        stringPtr(body),
 		LinkURL:     stringPtr("/rooms"),
 		ActorUserID: &room.HostUserID,
 	})
@@ -79,7 +93,8 @@ func (s *NotificationService) NotifyRoomDismissedToMembers(room *models.Room, me
 
 	return s.notifyMembers(room, members, models.NotificationRoomDismissed,
 		fmt.Sprintf("部屋「%s」が解散されました", room.Name),
-		"ホストにより部屋が解散されました。別の部屋を探すか、新しく部屋を作成してください。")
+		"ホストにより部屋が解散されました。参加者や発言数などのまとめを確認できます。",
+		sessionSummaryURL(room))
 }
 
 // NotifyRoomAutoDismissedToMembers 参加していた部屋が自動削除されたことをメンバー（ホスト以外）に知らせる
@@ -90,11 +105,135 @@ func (s *NotificationService) NotifyRoomAutoDismissedToMembers(room *models.Room
 
 	return s.notifyMembers(room, members, models.NotificationRoomAutoDismissed,
 		fmt.Sprintf("参加していた部屋「%s」が自動的に削除されました", room.Name),
-		"一定期間利用がなかったため、部屋は自動的に削除されました。")
+		"一定期間利用がなかったため、部屋は自動的に削除されました。参加者や発言数などのまとめを確認できます。",
+		sessionSummaryURL(room))
+}
+
+// NotifySessionSummaryToPastMembers 解散前に部屋を抜けていた参加者に、セッションのまとめができたことを知らせる。
+// 解散時に参加中だったメンバー（members）は解散のお知らせから開けるため除き、ホストとキックされた人にも送らない
+func (s *NotificationService) NotifySessionSummaryToPastMembers(room *models.Room, summary *models.RoomSessionSummary, members []models.RoomMember) error {
+	if room == nil || summary == nil {
+		return fmt.Errorf("invalid input: room=%v summary=%v", room, summary)
+	}
+
+	notified := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		notified[member.UserID] = true
+	}
+	var pastMembers []models.RoomMember
+	for _, p := range summary.GetParticipants() {
+		if notified[p.UserID] || p.Kicked {
+			continue
+		}
+		pastMembers = append(pastMembers, models.RoomMember{UserID: p.UserID})
+	}
+
+	return s.notifyMembers(room, pastMembers, models.NotificationSessionSummary,
+		fmt.Sprintf("参加していた部屋「%s」のまとめができました", room.Name),
+		"部屋が解散されました。参加者や発言数などのまとめを確認できます。",
+		sessionSummaryURL(room))
+}
+
+// sessionSummaryURL 解散した部屋のセッションのまとめページ
+func sessionSummaryURL(room *models.Room) string {
+	return "/rooms/" + room.ID.String() + "/summary"
+}
+
+// NotifyRoomStartingSoon 開始予定時刻が近づいたことを先に参加しているメンバー（ホスト以外）に知らせる
+func (s *NotificationService) NotifyRoomStartingSoon(room *models.Room, members []models.RoomMember) error {
+	if room == nil || room.ScheduledStartAt == nil {
+		return fmt.Errorf("room has no scheduled start: %v", room)
+	}
+
+	startAt := room.ScheduledStartAt.In(jst).Format("1/2 15:04")
+	return s.notifyMembers(room, members, models.NotificationRoomStartingSoon,
+		fmt.Sprintf("部屋「%s」がまもなく開始します", room.Name),
+		fmt.Sprintf("%s 開始予定の部屋です。準備ができたら部屋に入室してください。", startAt),
+		"/rooms/"+room.ID.String())
+}
+
+// NotifyWaitlistSeatOffered キャンセル待ちしていた部屋に空きが出て、expiresAt まで席を確保したことを本人に知らせる
+func (s *NotificationService) NotifyWaitlistSeatOffered(userID uuid.UUID, room *models.Room, expiresAt time.Time) error {
+	if userID == uuid.Nil || room == nil {
+		return fmt.Errorf("invalid input: userID=%v room=%v", userID, room)
+	}
+
+	return s.repo.Notification.Create(&models.Notification{
+		UserID:  userID,
+		Type:    models.NotificationWaitlistOffered,
+		Title:   fmt.Sprintf("部屋「%s」に空きが出ました", room.Name),
+		Body:    stringPtr(fmt.Sprintf("%s まで席を確保しています。時間内に参加しないと次の方に順番が回ります。", expiresAt.In(jst).Format("15:04"))),
+		LinkURL: stringPtr("/rooms/" + room.ID.String()),
+	})
+}
+
+// NotifyJoinRequestApproved 承認制の部屋への参加申請がホストに承認され、部屋に参加したことを申請者に知らせる
+func (s *NotificationService) NotifyJoinRequestApproved(userID uuid.UUID, room *models.Room) error {
+	if userID == uuid.Nil || room == nil {
+		return fmt.Errorf("invalid input: userID=%v room=%v", userID, room)
+	}
+
+	return s.repo.Notification.Create(&models.Notification{
+		UserID:      userID,
+		Type:        models.NotificationJoinApproved,
+		Title:       fmt.Sprintf("部屋「%s」への参加が承認されました", room.Name),
+		Body:        stringPtr("ホストが参加申請を承認し、部屋に参加しました。"),
+		LinkURL:     stringPtr("/rooms/" + room.ID.String()),
+		ActorUserID: &room.HostUserID,
+	})
+}
+
+// NotifyJoinRequestRejected 承認制の部屋への参加申請がホストに見送られたことを申請者に知らせる
+func (s *NotificationService) NotifyJoinRequestRejected(userID uuid.UUID, room *models.Room) error {
+	if userID == uuid.Nil || room == nil {
+		return fmt.Errorf("invalid input: userID=%v room=%v", userID, room)
+	}
+
+	return s.repo.Notification.Create(&models.Notification{
+		UserID:      userID,
+		Type:        models.NotificationJoinRejected,
+		Title:       fmt.Sprintf("部屋「%s」への参加申請は見送られました", room.Name),
+		Body:        stringPtr("ホストが参加申請を見送りました。他の部屋を探してみましょう。"),
+		LinkURL:     stringPtr("/rooms"),
+		ActorUserID: &room.HostUserID,
+	})
+}
+
+// NotifyMentioned 部屋のチャットでメンションされたことを本人に知らせる。本文にはメッセージの冒頭を添える
+func (s *NotificationService) NotifyMentioned(userID uuid.UUID, room *models.Room, sender *models.User, message string) error {
+	if userID == uuid.Nil || room == nil || sender == nil {
+		return fmt.Errorf("invalid input: userID=%v room=%v sender=%v", userID, room, sender)
+	}
+
+	name := sender.DisplayName
+	if name == "" && sender.Username != nil {
+		name = *sender.Username
+	}
+
+	return s.repo.Notification.Create(&models.Notification{
+		UserID:      userID,
+		Type:        models.NotificationMention,
+		Title:       fmt.Sprintf("%sさんが部屋「%s」であなたをメンションしました", name, room.Name),
+		Body:        stringPtr(truncateRunes(message, mentionExcerptLength)),
+		LinkURL:     stringPtr("/rooms/" + room.ID.String()),
+		ActorUserID: &sender.ID,
+	})
+}
+
+// mentionExcerptLength メンションのお知らせに添えるメッセージの最大文字数
+const mentionExcerptLength = 100
+
+// truncateRunes 文字数で切り詰め、切った場合は末尾に「…」を付ける
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "…"
 }
 
 // notifyMembers ホスト以外のメンバー全員に同じ内容のお知らせを作成する。一部失敗しても続行し、まとめて返す
-func (s *NotificationService) notifyMembers(room *models.Room, members []models.RoomMember, notificationType, title, body string) error {
+func (s *NotificationService) notifyMembers(room *models.Room, members []models.RoomMember, notificationType, title, body, linkURL string) error {
 	var errs []error
 	for _, member := range members {
 		if member.UserID == uuid.Nil || member.UserID == room.HostUserID {
@@ -105,7 +244,7 @@ func (s *NotificationService) notifyMembers(room *models.Room, members []models.
 			Type:        notificationType,
 			Title:       title,
 			Body:        stringPtr(body),
-			LinkURL:     stringPtr("/rooms"),
+			LinkURL:     stringPtr(linkURL),
 			ActorUserID: &room.HostUserID,
 		})
 		if err != nil {
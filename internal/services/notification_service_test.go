@@ -1,6 +1,7 @@
 package services
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -87,3 +88,243 @@ func TestNotifyRoomAutoDismissed(t *testing.T) {
 		t.Errorf("メンバー向けの内容が誤り: %+v", fake.created[1])
 	}
 }
+
+func TestNotifyRoomStartingSoon(t *testing.T) {
+	fake := &fakeNotificationRepo{}
+	svc := NewNotificationService(&repository.Repository{Notification: fake})
+
+	host := uuid.New()
+	guest := uuid.New()
+	startAt := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "夜の部屋", HostUserID: host, ScheduledStartAt: &startAt}
+
+	if err := svc.NotifyRoomStartingSoon(&models.Room{Name: "予定なし", HostUserID: host}, nil); err == nil {
+		t.Error("開始予定時刻のない部屋でエラーにならない")
+	}
+	if err := svc.NotifyRoomStartingSoon(room, []models.RoomMember{{UserID: host, IsHost: true}, {UserID: guest}}); err != nil {
+		t.Fatalf("NotifyRoomStartingSoon() error = %v", err)
+	}
+
+	if len(fake.created) != 1 {
+		t.Fatalf("作成されたお知らせ = %d 件, want 1（ホストを除くメンバー分）", len(fake.created))
+	}
+	n := fake.created[0]
+	if n.UserID != guest || n.Type != models.NotificationRoomStartingSoon {
+		t.Errorf("宛先/種類が誤り: %+v", n)
+	}
+	if n.LinkURL == nil || *n.LinkURL != "/rooms/"+room.ID.String() {
+		t.Errorf("リンク先が部屋詳細になっていない: %v", n.LinkURL)
+	}
+	if n.Body == nil || !strings.Contains(*n.Body, "10/17 21:00") {
+		t.Errorf("本文に開始予定時刻（JST）が含まれていない: %v", n.Body)
+	}
+}
+
+func TestNotifyWaitlistSeatOffered(t *testing.T) {
+	fake := &fakeNotificationRepo{}
+	svc := NewNotificationService(&repository.Repository{Notification: fake})
+
+	waiter := uuid.New()
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "満員部屋", HostUserID: uuid.New()}
+	expiresAt := time.Date(2026, time.October, 17, 12, 5, 0, 0, time.UTC)
+
+	if err := svc.NotifyWaitlistSeatOffered(uuid.Nil, room, expiresAt); err == nil {
+		t.Error("宛先なしでエラーにならない")
+	}
+	if err := svc.NotifyWaitlistSeatOffered(waiter, room, expiresAt); err != nil {
+		t.Fatalf("NotifyWaitlistSeatOffered() error = %v", err)
+	}
+
+	if len(fake.created) != 1 {
+		t.Fatalf("作成されたお知らせ = %d 件, want 1", len(fake.created))
+	}
+	n := fake.created[0]
+	if n.UserID != waiter || n.Type != models.NotificationWaitlistOffered || n.Title != "部屋「満員部屋」に空きが出ました" {
+		t.Errorf("宛先/種類/タイトルが誤り: %+v", n)
+	}
+	if n.LinkURL == nil || *n.LinkURL != "/rooms/"+room.ID.String() {
+		t.Errorf("リンク先が部屋詳細になっていない: %v", n.LinkURL)
+	}
+	if n.Body == nil || !strings.Contains(*n.Body, "21:05") {
+		t.Errorf("本文に確保期限（JST）が含まれていない: %v", n.Body)
+	}
+}
+
+func TestNotifyJoinRequestOutcome(t *testing.T) {
+	fake := &fakeNotificationRepo{}
+	svc := NewNotificationService(&repository.Repository{Notification: fake})
+
+	applicant := uuid.New()
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "承認制部屋", HostUserID: uuid.New()}
+
+	if err := svc.NotifyJoinRequestApproved(uuid.Nil, room); err == nil {
+		t.Error("宛先なしでエラーにならない")
+	}
+	if err := svc.NotifyJoinRequestApproved(applicant, room); err != nil {
+		t.Fatalf("NotifyJoinRequestApproved() error = %v", err)
+	}
+	if err := svc.NotifyJoinRequestRejected(applicant, room); err != nil {
+		t.Fatalf("NotifyJoinRequestRejected() error = %v", err)
+	}
+
+	if len(fake.created) != 2 {
+		t.Fatalf("作成されたお知らせ = %d 件, want 2", len(fake.created))
+	}
+	tests := []struct {
+		n        *models.Notification
+		wantType string
+		wantLink string
+	}{
+		{fake.created[0], models.NotificationJoinApproved, "/rooms/" + room.ID.String()},
+		{fake.created[1], models.NotificationJoinRejected, "/rooms"},
+	}
+	for _, tt := range tests {
+		if tt.n.UserID != applicant || tt.n.Type != tt.wantType {
+			t.Errorf("宛先/種類が誤り: %+v", tt.n)
+		}
+		if tt.n.LinkURL == nil || *tt.n.LinkURL != tt.wantLink {
+			t.Errorf("%s のリンク先 = %v, want %s", tt.wantType, tt.n.LinkURL, tt.wantLink)
+		}
+		if tt.n.ActorUserID == nil || *tt.n.ActorUserID != room.HostUserID {
+			t.Errorf("操作者がホストになっていない: %+v", tt.n)
+		}
+	}
+}
+
+func TestNotifyRoomKicked(t *testing.T) {
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "キックの部屋", HostUserID: uuid.New()}
+	reason := "暴言"
+	until := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		reason   *string
+		until    *time.Time
+		want     []string
+		wantNone []string
+	}{
+		{
+			name:     "理由・期限なしは無期限",
+			want:     []string{"再度参加できません"},
+			wantNone: []string{"理由"},
+		},
+		{
+			name:     "理由と期限（JST）を添える",
+			reason:   &reason,
+			until:    &until,
+			want:     []string{"理由: 暴言", "10/17 21:00 まで"},
+			wantNone: []string{"再度参加できません"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeNotificationRepo{}
+			svc := NewNotificationService(&repository.Repository{Notification: fake})
+			kicked := uuid.New()
+
+			if err := svc.NotifyRoomKicked(kicked, room, tt.reason, tt.until); err != nil {
+				t.Fatalf("NotifyRoomKicked() error = %v", err)
+			}
+			if len(fake.created) != 1 {
+				t.Fatalf("作成されたお知らせ = %d 件, want 1", len(fake.created))
+			}
+			n := fake.created[0]
+			if n.UserID != kicked || n.Type != models.NotificationRoomKicked || n.Body == nil {
+				t.Fatalf("宛先/種類/本文が誤り: %+v", n)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(*n.Body, s) {
+					t.Errorf("本文に %q が含まれていない: %s", s, *n.Body)
+				}
+			}
+			for _, s := range tt.wantNone {
+				if strings.Contains(*n.Body, s) {
+					t.Errorf("本文に %q が含まれている: %s", s, *n.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifySessionSummaryToPastMembers(t *testing.T) {
+	fake := &fakeNotificationRepo{}
+	svc := NewNotificationService(&repository.Repository{Notification: fake})
+
+	host, stayed, left, kicked := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "解散部屋", HostUserID: host}
+	summary := &models.RoomSessionSummary{
+		RoomID: room.ID,
+		Participants: models.JSONB{Data: []models.SessionParticipant{
+			{UserID: host, IsHost: true},
+			{UserID: stayed},
+			{UserID: left},
+			{UserID: kicked, Kicked: true},
+		}},
+	}
+	membersAtDismiss := []models.RoomMember{{UserID: host, IsHost: true}, {UserID: stayed}}
+
+	if err := svc.NotifyRoomDismissedToMembers(room, membersAtDismiss); err != nil {
+		t.Fatalf("NotifyRoomDismissedToMembers() error = %v", err)
+	}
+	if err := svc.NotifySessionSummaryToPastMembers(room, summary, membersAtDismiss); err != nil {
+		t.Fatalf("NotifySessionSummaryToPastMembers() error = %v", err)
+	}
+
+	// 解散時のメンバーには解散のお知らせ、途中で抜けた人にはまとめのお知らせだけが届く
+	if len(fake.created) != 2 {
+		t.Fatalf("作成されたお知らせ = %d 件, want 2: %+v", len(fake.created), fake.created)
+	}
+	tests := []struct {
+		n        *models.Notification
+		wantUser uuid.UUID
+		wantType string
+	}{
+		{fake.created[0], stayed, models.NotificationRoomDismissed},
+		{fake.created[1], left, models.NotificationSessionSummary},
+	}
+	wantLink := "/rooms/" + room.ID.String() + "/summary"
+	for _, tt := range tests {
+		if tt.n.UserID != tt.wantUser || tt.n.Type != tt.wantType {
+			t.Errorf("宛先/種類 = %v/%s, want %v/%s", tt.n.UserID, tt.n.Type, tt.wantUser, tt.wantType)
+		}
+		if tt.n.LinkURL == nil || *tt.n.LinkURL != wantLink {
+			t.Errorf("%s のリンク先 = %v, want %s", tt.wantType, tt.n.LinkURL, wantLink)
+		}
+	}
+}
+
+func TestNotifyMentioned(t *testing.T) {
+	fake := &fakeNotificationRepo{}
+	svc := NewNotificationService(&repository.Repository{Notification: fake})
+
+	target := uuid.New()
+	sender := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, DisplayName: "ハンター太郎"}
+	room := &models.Room{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "テスト部屋"}
+
+	if err := svc.NotifyMentioned(target, room, sender, "@花子 "+strings.Repeat("あ", 150)); err != nil {
+		t.Fatalf("NotifyMentioned() error = %v", err)
+	}
+	if len(fake.created) != 1 {
+		t.Fatalf("作成されたお知らせ = %d 件, want 1", len(fake.created))
+	}
+	n := fake.created[0]
+	if n.UserID != target || n.Type != models.NotificationMention {
+		t.Errorf("宛先/種類が誤り: %+v", n)
+	}
+	if n.Title != "ハンター太郎さんが部屋「テスト部屋」であなたをメンションしました" {
+		t.Errorf("Title = %q", n.Title)
+	}
+	if n.LinkURL == nil || *n.LinkURL != "/rooms/"+room.ID.String() {
+		t.Errorf("LinkURL = %v, want 部屋へのリンク", n.LinkURL)
+	}
+	if n.Body == nil || len([]rune(*n.Body)) != mentionExcerptLength+1 || !strings.HasSuffix(*n.Body, "…") {
+		t.Errorf("Body はメッセージの冒頭に切り詰めるはず: %v", n.Body)
+	}
+	if n.ActorUserID == nil || *n.ActorUserID != sender.ID {
+		t.Errorf("ActorUserID = %v, want 送信者", n.ActorUserID)
+	}
+
+	if err := svc.NotifyMentioned(uuid.Nil, room, sender, "x"); err == nil {
+		t.Error("宛先がないときはエラーになるはず")
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -10,11 +11,12 @@ import (
 	"mhp-rooms/internal/repository"
 )
 
-// RoomCleanupService 一定期間活動がない部屋を自動解散するサービス
+// RoomCleanupService 一定期間活動がない部屋の自動解散と、開始予定の部屋のお知らせを行う定期処理用サービス
 type RoomCleanupService struct {
 	repo                *repository.Repository
 	activityService     *ActivityService
 	notificationService *NotificationService
+	chatAttachments     *ChatAttachmentService
 }
 
 // NewRoomCleanupService 新しいRoomCleanupServiceインスタンスを作成
@@ -23,9 +25,15 @@ func NewRoomCleanupService(repo *repository.Repository) *RoomCleanupService {
 		repo:                repo,
 		activityService:     NewActivityService(repo),
 		notificationService: NewNotificationService(repo),
+		chatAttachments:     NewChatAttachmentService(repo, nil),
 	}
 }
 
+// SetChatAttachments 画像添付の保存先を設定したサービスを設定（未設定なら添付画像は削除しない）
+func (s *RoomCleanupService) SetChatAttachments(chatAttachments *ChatAttachmentService) {
+	s.chatAttachments = chatAttachments
+}
+
 // FindInactiveRooms idleDuration の間、活動（作成・設定変更・参加・退出・チャット）がない募集中の部屋を返す
 func (s *RoomCleanupService) FindInactiveRooms(idleDuration time.Duration) ([]models.Room, error) {
 	if idleDuration <= 0 {
@@ -59,6 +67,13 @@ func (s *RoomCleanupService) DismissInactiveRooms(idleDuration time.Duration) ([
 		}
 		dismissed = append(dismissed, room)
 
+		// 添付画像の削除に失敗しても PurgeOrphanedAttachments で拾い直す
+		if s.chatAttachments.Available() {
+			if err := s.chatAttachments.PurgeRoom(context.Background(), room.ID); err != nil {
+				log.Printf("添付画像の削除に失敗: room_id=%s: %v", room.ID, err)
+			}
+		}
+
 		// アクティビティ記録の失敗は解散処理の成否に影響させない
 		if err := s.activityService.RecordRoomAutoDismiss(room.HostUserID, &room); err != nil {
 			log.Printf("部屋自動削除アクティビティの記録に失敗: room_id=%s: %v", room.ID, err)
@@ -69,7 +84,66 @@ func (s *RoomCleanupService) DismissInactiveRooms(idleDuration time.Duration) ([
 		if err := s.notificationService.NotifyRoomAutoDismissedToMembers(&room, members); err != nil {
 			log.Printf("部屋自動削除のメンバー向けお知らせ作成に失敗: room_id=%s: %v", room.ID, err)
 		}
+		if summary, err := s.repo.RoomSession.FindByRoomID(room.ID); err != nil {
+			log.Printf("セッションのまとめの取得に失敗: room_id=%s: %v", room.ID, err)
+		} else if err := s.notificationService.NotifySessionSummaryToPastMembers(&room, summary, members); err != nil {
+			log.Printf("セッションのまとめのお知らせ作成に失敗: room_id=%s: %v", room.ID, err)
+		}
 	}
 
 	return dismissed, errors.Join(errs...)
 }
+
+// FindRoomsStartingSoon leadTime 以内に開始予定で、まだ開始前のお知らせを送っていない部屋を返す
+func (s *RoomCleanupService) FindRoomsStartingSoon(leadTime time.Duration) ([]models.Room, error) {
+	if leadTime <= 0 {
+		return nil, fmt.Errorf("lead time must be positive: %s", leadTime)
+	}
+
+	now := time.Now()
+	return s.repo.Room.FindRoomsStartingBefore(now, now.Add(leadTime))
+}
+
+// SendStartReminders 開始予定時刻が近い部屋の参加メンバーにお知らせを送り、送信済みにした部屋を返す。
+// 一部の部屋で失敗しても残りの処理を続け、失敗分をまとめたエラーを返す
+func (s *RoomCleanupService) SendStartReminders(leadTime time.Duration) ([]models.Room, error) {
+	rooms, err := s.FindRoomsStartingSoon(leadTime)
+	if err != nil {
+		return nil, fmt.Errorf("find rooms starting soon: %w", err)
+	}
+
+	var reminded []models.Room
+	var errs []error
+	for _, room := range rooms {
+		members, err := s.repo.Room.GetRoomMembers(room.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get members of room %s: %w", room.ID, err))
+			continue
+		}
+
+		// 二重送信を避けるため、お知らせの作成より先に送信済みにする
+		if err := s.repo.Room.MarkStartReminderSent(room.ID); err != nil {
+			errs = append(errs, fmt.Errorf("mark reminder sent for room %s: %w", room.ID, err))
+			continue
+		}
+		reminded = append(reminded, room)
+
+		if err := s.notificationService.NotifyRoomStartingSoon(&room, members); err != nil {
+			log.Printf("開始前のお知らせ作成に失敗: room_id=%s: %v", room.ID, err)
+		}
+	}
+
+	return reminded, errors.Join(errs...)
+}
+
+// PurgeOrphanedAttachments 削除済みメッセージ・解散済みの部屋に残っている添付画像を最大 limit 件削除し、削除した件数を返す
+func (s *RoomCleanupService) PurgeOrphanedAttachments(limit int) (int, error) {
+	if limit <= 0 {
+		return 0, fmt.Errorf("limit must be positive: %d", limit)
+	}
+	if !s.chatAttachments.Available() {
+		return 0, ErrChatImageUnavailable
+	}
+
+	return s.chatAttachments.PurgeOrphaned(context.Background(), limit)
+}
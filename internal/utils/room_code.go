@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 )
@@ -46,3 +47,12 @@ func GenerateUniqueRoomCode(checkExists func(string) (bool, error)) (string, err
 
 	return "", fmt.Errorf("一意な部屋コードの生成に失敗しました（%d回試行）", maxAttempts)
 }
+
+// GenerateInviteToken 招待リンク用のランダムなトークンを生成する（32文字の16進数）
+func GenerateInviteToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("招待トークンの生成に失敗しました: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
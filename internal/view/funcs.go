@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"html/template"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"mhp-rooms/internal/config"
+	"mhp-rooms/internal/models"
 )
 
 func toLower(s string) string {
@@ -165,6 +168,41 @@ func hunterListURL(query, sort string, page int) string {
 	return "/users?" + encoded
 }
 
+// formatJSTTime 日時を日本時間の「1/2 15:04」形式で返す。nil は空文字
+func formatJSTTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(time.FixedZone("JST", 9*60*60)).Format("1/2 15:04")
+}
+
+// weaponTypes 武器種の選択肢（部屋作成・参加・装備の申告フォーム用）
+func weaponTypes() []models.WeaponType {
+	return models.WeaponTypes
+}
+
+func slowModeOptions() []models.SlowModeOption {
+	return models.RoomSlowModeOptions
+}
+
+// highlightMentions メッセージ本文をエスケープし、メンションされた名前（@名前）を強調表示する
+func highlightMentions(text string, mentions []string) template.HTML {
+	escaped := template.HTMLEscapeString(text)
+	if len(mentions) == 0 {
+		return template.HTML(escaped)
+	}
+
+	// 長い名前を優先して置換する（「@花子」より「@花子 2号」を先に）
+	names := append([]string(nil), mentions...)
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	pairs := make([]string, 0, len(names)*2)
+	for _, name := range names {
+		mention := "@" + template.HTMLEscapeString(name)
+		pairs = append(pairs, mention, `<span class="text-blue-600 font-semibold">`+mention+`</span>`)
+	}
+	return template.HTML(strings.NewReplacer(pairs...).Replace(escaped))
+}
+
 func TemplateFuncs() template.FuncMap {
 	return template.FuncMap{
 		"lower":            toLower,
@@ -187,5 +225,9 @@ func TemplateFuncs() template.FuncMap {
 		"safeHTML":         safeHTMLString,
 		"truncate":         truncateHTML,
 		"hunterListURL":    hunterListURL,
+		"jstTime":          formatJSTTime,
+		"weaponTypes":      weaponTypes,
+		"slowModeOptions":  slowModeOptions,
+		"mentions":         highlightMentions,
 	}
 }
@@ -31,7 +31,10 @@ var partialDependencies = map[string][]string{
 	"profile_rooms":        {"tab_pagination.tmpl"},
 	"user_profile_rooms":   {"tab_pagination.tmpl"},
 	"profile_activity":     {"tab_pagination.tmpl"},
+	"profile_bans":         {},
+	"profile_hunts":        {},
 	"recent_activity_feed": {},
+	"message_item":         {},
 }
 
 func Template(w http.ResponseWriter, templateName string, data Data) {
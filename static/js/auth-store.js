@@ -21,6 +21,13 @@ document.addEventListener('alpine:init', () => {
       }
       this._initStarted = true
 
+      // ゲームから戻ってきたときに参加中の部屋の未読数を更新する
+      document.addEventListener('visibilitychange', () => {
+        if (document.visibilityState === 'visible' && this._currentRoomFetched) {
+          this.fetchCurrentRoom()
+        }
+      })
+
       if (window.supabaseClient) {
         this.checkAuth()
       } else {
@@ -627,6 +627,45 @@ window.userProfileRoomsHandler = {
   },
 }
 
+/**
+ * ユーザーを自分のBANリストに追加する（自分がホストの部屋すべてに参加できなくなる）
+ * @param {string} userId - 対象ユーザーのID
+ * @param {string} userName - 対象ユーザーの表示名
+ */
+async function banUserFromHost(userId, userName) {
+  const authStore = Alpine.store('auth')
+  if (!authStore || !authStore.session?.access_token) {
+    window.location.href = '/auth/login'
+    return
+  }
+
+  const reason = prompt(
+    `${userName}さんをBANリストに追加します。\nBANしたユーザーは、あなたがホストの部屋に参加できなくなります。\n\n理由（任意・本人には表示されません）`,
+    ''
+  )
+  if (reason === null) return
+
+  try {
+    const response = await fetch(`/api/users/${userId}/ban`, {
+      method: 'POST',
+      headers: {
+        Authorization: `Bearer ${authStore.session.access_token}`,
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ reason: reason }),
+    })
+    if (!response.ok) {
+      const errorText = await response.text()
+      throw new Error(errorText || 'BANリストへの追加に失敗しました')
+    }
+    const result = await response.json()
+    showNotification(result.message, 'success')
+  } catch (error) {
+    console.error('BAN追加エラー:', error)
+    showNotification(error.message || 'BANリストへの追加に失敗しました', 'error')
+  }
+}
+
 // DOMContentLoaded時の初期化
 document.addEventListener('DOMContentLoaded', () => {
   // プロフィール関連のイベントリスナーを設定
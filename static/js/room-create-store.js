@@ -14,8 +14,14 @@ document.addEventListener('alpine:init', () => {
       gameVersionId: '',
       maxPlayers: '',
       password: '',
+      requiresApproval: false,
+      wantedWeapons: [],
+      visibility: 'public',
       targetMonster: '',
+      questId: '',
       rankRequirement: '',
+      scheduledStartAt: '',
+      scheduledEndAt: '',
       description: '',
     },
 
@@ -25,6 +31,9 @@ document.addEventListener('alpine:init', () => {
     // ゲームバージョンリスト
     gameVersions: [],
 
+    // モンスター・クエスト図鑑（ゲームバージョンIDごとのキャッシュ）
+    catalogs: {},
+
     // 送信中フラグ
     isSubmitting: false,
 
@@ -55,6 +64,50 @@ document.addEventListener('alpine:init', () => {
       }
     },
 
+    // 図鑑を取得（ゲーム選択時。取得済みのゲームはキャッシュを使う）
+    async loadCatalog(gameVersionId) {
+      this.formData.questId = ''
+      if (!gameVersionId || this.catalogs[gameVersionId]) {
+        return
+      }
+      try {
+        const response = await fetch(`/api/game-versions/${gameVersionId}/catalog`, {
+          credentials: 'same-origin',
+        })
+        if (response.ok) {
+          const data = await response.json()
+          this.catalogs[gameVersionId] = {
+            monsters: data.monsters || [],
+            quests: data.quests || [],
+          }
+        }
+      } catch (error) {
+        // 図鑑が取れなくても自由入力で作成できる
+        console.error('図鑑取得エラー:', error)
+      }
+    },
+
+    // 選択中のゲームのモンスター一覧
+    get catalogMonsters() {
+      return this.catalogs[this.formData.gameVersionId]?.monsters || []
+    },
+
+    // 入力中のモンスター名に一致する図鑑のモンスター（一致しなければ自由入力）
+    get matchedMonster() {
+      const name = this.formData.targetMonster.trim().toLowerCase()
+      if (!name) {
+        return null
+      }
+      return this.catalogMonsters.find((monster) => monster.name.toLowerCase() === name) || null
+    },
+
+    // 選べるクエスト（モンスターが図鑑に一致した場合はそのモンスターのクエストのみ）
+    get catalogQuests() {
+      const quests = this.catalogs[this.formData.gameVersionId]?.quests || []
+      const monster = this.matchedMonster
+      return monster ? quests.filter((quest) => quest.monster_id === monster.id) : quests
+    },
+
     // モーダルを開く
     async open() {
       // 認証チェック
@@ -115,8 +168,14 @@ document.addEventListener('alpine:init', () => {
         gameVersionId: '',
         maxPlayers: '',
         password: '',
+        requiresApproval: false,
+        wantedWeapons: [],
+        visibility: 'public',
         targetMonster: '',
+        questId: '',
         rankRequirement: '',
+        scheduledStartAt: '',
+        scheduledEndAt: '',
         description: '',
       }
       this.formErrors = {}
@@ -152,12 +211,55 @@ document.addEventListener('alpine:init', () => {
       )
     },
 
+    // datetime-local の入力値を RFC3339（UTC）に変換。未入力は null
+    toRFC3339(value) {
+      if (!value) {
+        return null
+      }
+      const date = new Date(value)
+      return Number.isNaN(date.getTime()) ? null : date.toISOString()
+    },
+
+    // 開始・終了予定のバリデーション（詳細な範囲チェックはサーバー側で行う）
+    validateSchedule() {
+      const errors = {}
+      const start = this.formData.scheduledStartAt
+      const end = this.formData.scheduledEndAt
+      if (start && new Date(start) <= new Date()) {
+        errors.scheduledStartAt = '開始予定には現在より後の時刻を指定してください'
+      }
+      if (end && start && new Date(end) <= new Date(start)) {
+        errors.scheduledEndAt = '終了予定は開始予定より後にしてください'
+      }
+      return errors
+    },
+
+    // 募集する武器種は募集人数まで（詳細な検証はサーバー側で行う）
+    validateWantedWeapons() {
+      const maxPlayers = Number.parseInt(this.formData.maxPlayers)
+      if (maxPlayers && this.formData.wantedWeapons.length > maxPlayers) {
+        return {
+          wantedWeapons: `募集する武器種は募集人数（${maxPlayers}人）以内で選んでください`,
+        }
+      }
+      return {}
+    },
+
     // 部屋作成処理
     async createRoom() {
       if (!this.isValidForm || this.isSubmitting) {
         return
       }
 
+      const inputErrors = {
+        ...this.validateSchedule(),
+        ...this.validateWantedWeapons(),
+      }
+      if (Object.keys(inputErrors).length > 0) {
+        this.formErrors = inputErrors
+        return
+      }
+
       this.isSubmitting = true
       this.formErrors = {}
 
@@ -171,9 +273,20 @@ document.addEventListener('alpine:init', () => {
           name: this.formData.name.trim(),
           game_version_id: this.formData.gameVersionId,
           max_players: Number.parseInt(this.formData.maxPlayers),
-          password: this.formData.password.trim() || null,
+          password: this.formData.requiresApproval
+            ? null
+            : this.formData.password.trim() || null,
+          requires_approval: this.formData.requiresApproval,
+          wanted_weapons: this.formData.wantedWeapons,
+          visibility: this.formData.visibility,
           target_monster: this.formData.targetMonster.trim() || null,
+          monster_id: this.matchedMonster?.id || null,
+          quest_id: this.formData.questId || null,
           rank_requirement: this.formData.rankRequirement.trim() || null,
+          scheduled_start_at: this.toRFC3339(this.formData.scheduledStartAt),
+          scheduled_end_at: this.formData.scheduledStartAt
+            ? this.toRFC3339(this.formData.scheduledEndAt)
+            : null,
           description: this.formData.description.trim() || null,
         }
 
@@ -197,9 +310,16 @@ document.addEventListener('alpine:init', () => {
               return
             }
           } else if (response.status === 400) {
-            const errorData = await response.json()
+            // 入力エラーは JSON とテキストの両方があり得る
+            const errorText = await response.text()
+            let errorData = null
+            try {
+              errorData = JSON.parse(errorText)
+            } catch (_) {
+              throw new Error(errorText.trim() || 'バリデーションエラー')
+            }
             this.formErrors = errorData.errors || {}
-            throw new Error(errorData.message || 'バリデーションエラー')
+            throw new Error(errorData.message || errorData.error || 'バリデーションエラー')
           }
 
           const errorText = await response.text()